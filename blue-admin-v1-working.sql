@@ -63,22 +63,38 @@ INSERT INTO "page_roles" ("page_id","role_id") VALUES (1,5),
  (1,4),
  (2,4),
  (3,4);
-INSERT INTO "features" ("id","name","description","active","role_id") VALUES (1,'role_read','View List of Roles',1,2),
- (2,'role_write','Privilege to Update ,Create, View and Delete Role ',1,3),
- (3,'user_read','View List of Users',1,2),
- (4,'user_write','Privilege to Update ,Create, View and Delete User ',1,3),
- (5,'page_read','View List of Pages',1,2),
- (6,'page_write','Privilege to Update ,Create, View and Delete Page ',1,3),
- (7,'app_read','View List of Apps',1,2),
- (8,'app_write','Privilege to Update ,Create, View and Delete App ',1,4),
- (9,'endpoint_read','View List of Endpoints',1,2),
- (10,'endpoint_write','Privilege to Update ,Create, View and Delete EndPoint ',1,3),
- (11,'feature_read','View List of Features',1,2),
- (12,'features_write','Privilege to Update ,Create, View and Delete Feature ',1,3),
- (13,'login','Endpoints that can be accessed with out Logging In',1,5),
- (14,'drop_down','Endpoints that fetch Drowns ',0,6),
- (15,'app_role_read','Read Privileges for Apps to Communicate with API Calls',1,4),
- (16,'app_role_write',' Write Privileges for Apps the Communicate with API Calls',1,4);
+INSERT INTO "features" ("id","name","description","active") VALUES (1,'role_read','View List of Roles',1),
+ (2,'role_write','Privilege to Update ,Create, View and Delete Role ',1),
+ (3,'user_read','View List of Users',1),
+ (4,'user_write','Privilege to Update ,Create, View and Delete User ',1),
+ (5,'page_read','View List of Pages',1),
+ (6,'page_write','Privilege to Update ,Create, View and Delete Page ',1),
+ (7,'app_read','View List of Apps',1),
+ (8,'app_write','Privilege to Update ,Create, View and Delete App ',1),
+ (9,'endpoint_read','View List of Endpoints',1),
+ (10,'endpoint_write','Privilege to Update ,Create, View and Delete EndPoint ',1),
+ (11,'feature_read','View List of Features',1),
+ (12,'features_write','Privilege to Update ,Create, View and Delete Feature ',1),
+ (13,'login','Endpoints that can be accessed with out Logging In',1),
+ (14,'drop_down','Endpoints that fetch Drowns ',0),
+ (15,'app_role_read','Read Privileges for Apps to Communicate with API Calls',1),
+ (16,'app_role_write',' Write Privileges for Apps the Communicate with API Calls',1);
+INSERT INTO "feature_roles" ("feature_id","role_id") VALUES (1,2),
+ (2,3),
+ (3,2),
+ (4,3),
+ (5,2),
+ (6,3),
+ (7,2),
+ (8,4),
+ (9,2),
+ (10,3),
+ (11,2),
+ (12,3),
+ (13,5),
+ (14,6),
+ (15,4),
+ (16,4);
 INSERT INTO "endpoints" ("id","name","route_path","method","description","feature_id") VALUES (1,'swagger_routes_get','/docs/*','GET','swagger_routes-GET',NULL),
  (2,'custom_metrics_route_get','/lmetrics','GET','custom_metrics_route-GET',NULL),
  (3,'get_all_roles_get','/api/v1/role','GET','get_all_roles-GET',1),
@@ -122,7 +138,7 @@ INSERT INTO "endpoints" ("id","name","route_path","method","description","featur
  (41,'post_page_post','/api/v1/page','POST','post_page-POST',6),
  (42,'add_rolepage_post','/api/v1/rolepage/:role_id/:page_id','POST','add_rolepage-POST',2),
  (43,'login_route_post','/api/v1/login','POST','login_route-POST',13),
- (44,'activate_deactivate_role_put','/api/v1/roles/:role_id','PUT','activate_deactivate_role-PUT',2),
+ (44,'activate_deactivate_role_put','/api/v1/role/:role_id','PUT','activate_deactivate_role-PUT',2),
  (45,'activate_deactivate_user_put','/api/v1/user/:user_id','PUT','activate_deactivate_user-PUT',4),
  (46,'change_reset_password_put','/api/v1/user','PUT','change_reset_password-PUT',4),
  (47,'activate_deactivate_features_put','/api/v1/feature/:feature_id','PUT','activate_deactivate_features-PUT',12),
@@ -1,18 +1,85 @@
 package bluerpc
 
 import (
+	"errors"
 	"fmt"
 
+	"blue-admin.com/database"
 	"blue-admin.com/utils"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type BlueRPCServer struct {
 	BlueServiceServer
 }
 
+// Deprecated: tokens are signed with the asymmetric keys returned by GetJWKS,
+// the salts only verify tokens issued while JWT_ACCEPT_HS512 is enabled
 func (server *BlueRPCServer) GetSalt(ctx context.Context, message *BlueAppID) (*BlueSalt, error) {
 	fmt.Printf("The APP ID: %v\n", message.AppId)
 	salt_a, salt_b := utils.GetJWTSalt()
 	return &BlueSalt{SaltA: salt_a, SaltB: salt_b}, nil
 }
+
+// Public keys services use to verify tokens locally
+func (server *BlueRPCServer) GetJWKS(ctx context.Context, message *BlueAppID) (*BlueJWKS, error) {
+	jwks, err := utils.SigningKeys.JWKS()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*BlueJWK, 0, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		keys = append(keys, &BlueJWK{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return &BlueJWKS{Keys: keys}, nil
+}
+
+// Policy decision point for app clients, the same decision as POST /authorize
+func (server *BlueRPCServer) Authorize(ctx context.Context, message *BlueAuthorizeRequest) (*BlueAuthorizeDecision, error) {
+	db, err := database.ReturnSession()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	decision, err := utils.AuthorizeForClient(db, ctx, message.ClientId, message.ClientSecret, utils.AuthorizationRequest{
+		Token:    message.Token,
+		Subject:  message.Subject,
+		AppUUID:  message.AppId,
+		Method:   message.Method,
+		Path:     message.Path,
+		Endpoint: message.Endpoint,
+		IP:       message.Ip,
+	})
+	switch {
+	case errors.Is(err, utils.ErrClientInvalid):
+		return nil, status.Error(codes.Unauthenticated, "client authentication failed")
+	case errors.Is(err, utils.ErrAuthorizationOtherApp):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, utils.ErrAuthorizationTarget):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &BlueAuthorizeDecision{
+		Allowed:   decision.Allowed,
+		Role:      decision.Role,
+		Feature:   decision.Feature,
+		Endpoint:  decision.Endpoint,
+		Method:    decision.Method,
+		RoutePath: decision.RoutePath,
+		Reason:    decision.Reason,
+	}, nil
+}
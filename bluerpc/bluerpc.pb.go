@@ -122,6 +122,411 @@ func (x *BlueAppID) GetAppId() string {
 	return ""
 }
 
+type BlueAppRoles struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Roles []string `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+}
+
+func (x *BlueAppRoles) Reset() {
+	*x = BlueAppRoles{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bluerpc_bluerpc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlueAppRoles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueAppRoles) ProtoMessage() {}
+
+func (x *BlueAppRoles) ProtoReflect() protoreflect.Message {
+	mi := &file_bluerpc_bluerpc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueAppRoles.ProtoReflect.Descriptor instead.
+func (*BlueAppRoles) Descriptor() ([]byte, []int) {
+	return file_bluerpc_bluerpc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BlueAppRoles) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type BlueJWK struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kty string `protobuf:"bytes,1,opt,name=kty,proto3" json:"kty,omitempty"`
+	Kid string `protobuf:"bytes,2,opt,name=kid,proto3" json:"kid,omitempty"`
+	Use string `protobuf:"bytes,3,opt,name=use,proto3" json:"use,omitempty"`
+	Alg string `protobuf:"bytes,4,opt,name=alg,proto3" json:"alg,omitempty"`
+	N   string `protobuf:"bytes,5,opt,name=n,proto3" json:"n,omitempty"`
+	E   string `protobuf:"bytes,6,opt,name=e,proto3" json:"e,omitempty"`
+	Crv string `protobuf:"bytes,7,opt,name=crv,proto3" json:"crv,omitempty"`
+	X   string `protobuf:"bytes,8,opt,name=x,proto3" json:"x,omitempty"`
+}
+
+func (x *BlueJWK) Reset() {
+	*x = BlueJWK{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bluerpc_bluerpc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlueJWK) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueJWK) ProtoMessage() {}
+
+func (x *BlueJWK) ProtoReflect() protoreflect.Message {
+	mi := &file_bluerpc_bluerpc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueJWK.ProtoReflect.Descriptor instead.
+func (*BlueJWK) Descriptor() ([]byte, []int) {
+	return file_bluerpc_bluerpc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BlueJWK) GetKty() string {
+	if x != nil {
+		return x.Kty
+	}
+	return ""
+}
+
+func (x *BlueJWK) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *BlueJWK) GetUse() string {
+	if x != nil {
+		return x.Use
+	}
+	return ""
+}
+
+func (x *BlueJWK) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+func (x *BlueJWK) GetN() string {
+	if x != nil {
+		return x.N
+	}
+	return ""
+}
+
+func (x *BlueJWK) GetE() string {
+	if x != nil {
+		return x.E
+	}
+	return ""
+}
+
+func (x *BlueJWK) GetCrv() string {
+	if x != nil {
+		return x.Crv
+	}
+	return ""
+}
+
+func (x *BlueJWK) GetX() string {
+	if x != nil {
+		return x.X
+	}
+	return ""
+}
+
+type BlueJWKS struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Keys []*BlueJWK `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+}
+
+func (x *BlueJWKS) Reset() {
+	*x = BlueJWKS{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bluerpc_bluerpc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlueJWKS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueJWKS) ProtoMessage() {}
+
+func (x *BlueJWKS) ProtoReflect() protoreflect.Message {
+	mi := &file_bluerpc_bluerpc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueJWKS.ProtoReflect.Descriptor instead.
+func (*BlueJWKS) Descriptor() ([]byte, []int) {
+	return file_bluerpc_bluerpc_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BlueJWKS) GetKeys() []*BlueJWK {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// token or subject (a user uuid) and the request named by method and path or by endpoint name,
+// app_id is the client's app uuid and may be left empty
+type BlueAuthorizeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClientId     string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	AppId        string `protobuf:"bytes,3,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Token        string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	Subject      string `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	Method       string `protobuf:"bytes,6,opt,name=method,proto3" json:"method,omitempty"`
+	Path         string `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`
+	Endpoint     string `protobuf:"bytes,8,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Ip           string `protobuf:"bytes,9,opt,name=ip,proto3" json:"ip,omitempty"`
+}
+
+func (x *BlueAuthorizeRequest) Reset() {
+	*x = BlueAuthorizeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bluerpc_bluerpc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlueAuthorizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueAuthorizeRequest) ProtoMessage() {}
+
+func (x *BlueAuthorizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bluerpc_bluerpc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueAuthorizeRequest.ProtoReflect.Descriptor instead.
+func (*BlueAuthorizeRequest) Descriptor() ([]byte, []int) {
+	return file_bluerpc_bluerpc_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BlueAuthorizeRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type BlueAuthorizeDecision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Allowed   bool   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Role      string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Feature   string `protobuf:"bytes,3,opt,name=feature,proto3" json:"feature,omitempty"`
+	Endpoint  string `protobuf:"bytes,4,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Method    string `protobuf:"bytes,5,opt,name=method,proto3" json:"method,omitempty"`
+	RoutePath string `protobuf:"bytes,6,opt,name=route_path,json=routePath,proto3" json:"route_path,omitempty"`
+	Reason    string `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *BlueAuthorizeDecision) Reset() {
+	*x = BlueAuthorizeDecision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bluerpc_bluerpc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlueAuthorizeDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlueAuthorizeDecision) ProtoMessage() {}
+
+func (x *BlueAuthorizeDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_bluerpc_bluerpc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlueAuthorizeDecision.ProtoReflect.Descriptor instead.
+func (*BlueAuthorizeDecision) Descriptor() ([]byte, []int) {
+	return file_bluerpc_bluerpc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BlueAuthorizeDecision) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *BlueAuthorizeDecision) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeDecision) GetFeature() string {
+	if x != nil {
+		return x.Feature
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeDecision) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeDecision) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeDecision) GetRoutePath() string {
+	if x != nil {
+		return x.RoutePath
+	}
+	return ""
+}
+
+func (x *BlueAuthorizeDecision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 var File_bluerpc_bluerpc_proto protoreflect.FileDescriptor
 
 var file_bluerpc_bluerpc_proto_rawDesc = []byte{
@@ -132,11 +537,63 @@ var file_bluerpc_bluerpc_proto_rawDesc = []byte{
 	0x6c, 0x74, 0x5f, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x61, 0x6c, 0x74,
 	0x42, 0x22, 0x22, 0x0a, 0x09, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x70, 0x70, 0x49, 0x44, 0x12, 0x15,
 	0x0a, 0x06, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x61, 0x70, 0x70, 0x49, 0x64, 0x32, 0x31, 0x0a, 0x0b, 0x42, 0x6c, 0x75, 0x65, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x22, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x53, 0x61, 0x6c, 0x74, 0x12,
-	0x0a, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x70, 0x70, 0x49, 0x44, 0x1a, 0x09, 0x2e, 0x42, 0x6c,
-	0x75, 0x65, 0x53, 0x61, 0x6c, 0x74, 0x22, 0x00, 0x42, 0x0b, 0x5a, 0x09, 0x2e, 0x2f, 0x62, 0x6c,
-	0x75, 0x65, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x70, 0x70, 0x49, 0x64, 0x22, 0x24, 0x0a, 0x0c, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x70, 0x70,
+	0x52, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x22, 0x8d, 0x01, 0x0a, 0x07,
+	0x42, 0x6c, 0x75, 0x65, 0x4a, 0x57, 0x4b, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x74, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x74, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x73, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x61, 0x6c, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x6c, 0x67, 0x12,
+	0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x6e, 0x12, 0x0c, 0x0a,
+	0x01, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63,
+	0x72, 0x76, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x72, 0x76, 0x12, 0x0c, 0x0a,
+	0x01, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x78, 0x22, 0x28, 0x0a, 0x08, 0x42,
+	0x6c, 0x75, 0x65, 0x4a, 0x57, 0x4b, 0x53, 0x12, 0x1c, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x4a, 0x57, 0x4b, 0x52,
+	0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0xf7, 0x01, 0x0a, 0x14, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x12, 0x15, 0x0a, 0x06, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x61, 0x70, 0x70, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22,
+	0xca, 0x01, 0x0a, 0x15, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a,
+	0x65, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x50, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x32, 0xbf, 0x01, 0x0a,
+	0x0b, 0x42, 0x6c, 0x75, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x22, 0x0a, 0x07,
+	0x47, 0x65, 0x74, 0x53, 0x61, 0x6c, 0x74, 0x12, 0x0a, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x70,
+	0x70, 0x49, 0x44, 0x1a, 0x09, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x53, 0x61, 0x6c, 0x74, 0x22, 0x00,
+	0x12, 0x2a, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x41, 0x70, 0x70, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x12,
+	0x0a, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x70, 0x70, 0x49, 0x44, 0x1a, 0x0d, 0x2e, 0x42, 0x6c,
+	0x75, 0x65, 0x41, 0x70, 0x70, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x22, 0x00, 0x12, 0x22, 0x0a, 0x07,
+	0x47, 0x65, 0x74, 0x4a, 0x57, 0x4b, 0x53, 0x12, 0x0a, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x70,
+	0x70, 0x49, 0x44, 0x1a, 0x09, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x4a, 0x57, 0x4b, 0x53, 0x22, 0x00,
+	0x12, 0x3c, 0x0a, 0x09, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x12, 0x15, 0x2e,
+	0x42, 0x6c, 0x75, 0x65, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x42, 0x6c, 0x75, 0x65, 0x41, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x69, 0x7a, 0x65, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x42, 0x0b,
+	0x5a, 0x09, 0x2e, 0x2f, 0x62, 0x6c, 0x75, 0x65, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -151,19 +608,31 @@ func file_bluerpc_bluerpc_proto_rawDescGZIP() []byte {
 	return file_bluerpc_bluerpc_proto_rawDescData
 }
 
-var file_bluerpc_bluerpc_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_bluerpc_bluerpc_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_bluerpc_bluerpc_proto_goTypes = []interface{}{
-	(*BlueSalt)(nil),  // 0: BlueSalt
-	(*BlueAppID)(nil), // 1: BlueAppID
+	(*BlueSalt)(nil),              // 0: BlueSalt
+	(*BlueAppID)(nil),             // 1: BlueAppID
+	(*BlueAppRoles)(nil),          // 2: BlueAppRoles
+	(*BlueJWK)(nil),               // 3: BlueJWK
+	(*BlueJWKS)(nil),              // 4: BlueJWKS
+	(*BlueAuthorizeRequest)(nil),  // 5: BlueAuthorizeRequest
+	(*BlueAuthorizeDecision)(nil), // 6: BlueAuthorizeDecision
 }
 var file_bluerpc_bluerpc_proto_depIdxs = []int32{
-	1, // 0: BlueService.GetSalt:input_type -> BlueAppID
-	0, // 1: BlueService.GetSalt:output_type -> BlueSalt
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3, // 0: BlueJWKS.keys:type_name -> BlueJWK
+	1, // 1: BlueService.GetSalt:input_type -> BlueAppID
+	1, // 2: BlueService.GetAppRoles:input_type -> BlueAppID
+	1, // 3: BlueService.GetJWKS:input_type -> BlueAppID
+	5, // 4: BlueService.Authorize:input_type -> BlueAuthorizeRequest
+	0, // 5: BlueService.GetSalt:output_type -> BlueSalt
+	2, // 6: BlueService.GetAppRoles:output_type -> BlueAppRoles
+	4, // 7: BlueService.GetJWKS:output_type -> BlueJWKS
+	6, // 8: BlueService.Authorize:output_type -> BlueAuthorizeDecision
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_bluerpc_bluerpc_proto_init() }
@@ -196,6 +665,66 @@ func file_bluerpc_bluerpc_proto_init() {
 				return nil
 			}
 		}
+		file_bluerpc_bluerpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlueAppRoles); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bluerpc_bluerpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlueJWK); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bluerpc_bluerpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlueJWKS); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bluerpc_bluerpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlueAuthorizeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bluerpc_bluerpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlueAuthorizeDecision); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -203,7 +732,7 @@ func file_bluerpc_bluerpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_bluerpc_bluerpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	BlueService_GetSalt_FullMethodName = "/BlueService/GetSalt"
+	BlueService_GetSalt_FullMethodName     = "/BlueService/GetSalt"
+	BlueService_GetAppRoles_FullMethodName = "/BlueService/GetAppRoles"
+	BlueService_GetJWKS_FullMethodName     = "/BlueService/GetJWKS"
+	BlueService_Authorize_FullMethodName   = "/BlueService/Authorize"
 )
 
 // BlueServiceClient is the client API for BlueService service.
@@ -27,6 +30,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type BlueServiceClient interface {
 	GetSalt(ctx context.Context, in *BlueAppID, opts ...grpc.CallOption) (*BlueSalt, error)
+	GetAppRoles(ctx context.Context, in *BlueAppID, opts ...grpc.CallOption) (*BlueAppRoles, error)
+	GetJWKS(ctx context.Context, in *BlueAppID, opts ...grpc.CallOption) (*BlueJWKS, error)
+	Authorize(ctx context.Context, in *BlueAuthorizeRequest, opts ...grpc.CallOption) (*BlueAuthorizeDecision, error)
 }
 
 type blueServiceClient struct {
@@ -46,11 +52,41 @@ func (c *blueServiceClient) GetSalt(ctx context.Context, in *BlueAppID, opts ...
 	return out, nil
 }
 
+func (c *blueServiceClient) GetAppRoles(ctx context.Context, in *BlueAppID, opts ...grpc.CallOption) (*BlueAppRoles, error) {
+	out := new(BlueAppRoles)
+	err := c.cc.Invoke(ctx, BlueService_GetAppRoles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blueServiceClient) GetJWKS(ctx context.Context, in *BlueAppID, opts ...grpc.CallOption) (*BlueJWKS, error) {
+	out := new(BlueJWKS)
+	err := c.cc.Invoke(ctx, BlueService_GetJWKS_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blueServiceClient) Authorize(ctx context.Context, in *BlueAuthorizeRequest, opts ...grpc.CallOption) (*BlueAuthorizeDecision, error) {
+	out := new(BlueAuthorizeDecision)
+	err := c.cc.Invoke(ctx, BlueService_Authorize_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BlueServiceServer is the server API for BlueService service.
 // All implementations must embed UnimplementedBlueServiceServer
 // for forward compatibility
 type BlueServiceServer interface {
 	GetSalt(context.Context, *BlueAppID) (*BlueSalt, error)
+	GetAppRoles(context.Context, *BlueAppID) (*BlueAppRoles, error)
+	GetJWKS(context.Context, *BlueAppID) (*BlueJWKS, error)
+	Authorize(context.Context, *BlueAuthorizeRequest) (*BlueAuthorizeDecision, error)
 	mustEmbedUnimplementedBlueServiceServer()
 }
 
@@ -61,6 +97,15 @@ type UnimplementedBlueServiceServer struct {
 func (UnimplementedBlueServiceServer) GetSalt(context.Context, *BlueAppID) (*BlueSalt, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSalt not implemented")
 }
+func (UnimplementedBlueServiceServer) GetAppRoles(context.Context, *BlueAppID) (*BlueAppRoles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAppRoles not implemented")
+}
+func (UnimplementedBlueServiceServer) GetJWKS(context.Context, *BlueAppID) (*BlueJWKS, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJWKS not implemented")
+}
+func (UnimplementedBlueServiceServer) Authorize(context.Context, *BlueAuthorizeRequest) (*BlueAuthorizeDecision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+}
 func (UnimplementedBlueServiceServer) mustEmbedUnimplementedBlueServiceServer() {}
 
 // UnsafeBlueServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +137,60 @@ func _BlueService_GetSalt_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlueService_GetAppRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlueAppID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlueServiceServer).GetAppRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlueService_GetAppRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlueServiceServer).GetAppRoles(ctx, req.(*BlueAppID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlueService_GetJWKS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlueAppID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlueServiceServer).GetJWKS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlueService_GetJWKS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlueServiceServer).GetJWKS(ctx, req.(*BlueAppID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlueService_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlueAuthorizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlueServiceServer).Authorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlueService_Authorize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlueServiceServer).Authorize(ctx, req.(*BlueAuthorizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BlueService_ServiceDesc is the grpc.ServiceDesc for BlueService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +202,18 @@ var BlueService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSalt",
 			Handler:    _BlueService_GetSalt_Handler,
 		},
+		{
+			MethodName: "GetAppRoles",
+			Handler:    _BlueService_GetAppRoles_Handler,
+		},
+		{
+			MethodName: "GetJWKS",
+			Handler:    _BlueService_GetJWKS_Handler,
+		},
+		{
+			MethodName: "Authorize",
+			Handler:    _BlueService_Authorize_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "bluerpc/bluerpc.proto",
@@ -34,6 +34,36 @@ func ScheduledTasks() *tasks.Scheduler {
 		fmt.Println(err)
 	}
 
+	// JWT signing keys are rotated every JWT_KEY_LIFE_TIME minutes
+	// the retired key stays published in the jwks for the grace period
+	key_life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("JWT_KEY_LIFE_TIME", "1440"))
+	if _, err := scheduler.Add(&tasks.Task{
+		Interval: time.Minute * time.Duration(key_life_time),
+		TaskFunc: func() error {
+			return utils.SigningKeyRotate()
+		},
+	}); err != nil {
+		fmt.Println(err)
+	}
+
+	// Expired refresh tokens, revoked token ids, authorization codes, login counters, reset and magic link tokens, sessions and api keys are no longer needed
+	if _, err := scheduler.Add(&tasks.Task{
+		Interval: 60 * time.Minute,
+		TaskFunc: func() error {
+			utils.CleanExpiredRefreshTokens()
+			utils.CleanExpiredRevocations()
+			utils.CleanExpiredAuthorizationCodes()
+			utils.CleanExpiredLoginAttempts()
+			utils.CleanExpiredPasswordResetTokens()
+			utils.CleanExpiredMagicLinkTokens()
+			utils.CleanExpiredSessions()
+			utils.CleanExpiredAPIKeys()
+			return nil
+		},
+	}); err != nil {
+		fmt.Println(err)
+	}
+
 	// // Add a task to move to Logs Directory Every Interval, Interval to Be Provided From Configuration File
 	gormLoggerfile, _ := database.GormLoggerFile()
 	//  App should not start
@@ -71,7 +101,10 @@ func ScheduledTasks() *tasks.Scheduler {
 				fmt.Printf("Error Fetching: %v", err)
 			}
 
-			hashed_password := utils.HashFunc("default@123")
+			hashed_password, err := utils.HashFunc("default@123")
+			if err != nil {
+				return err
+			}
 			if err := db.Model(user_q).Where("id = ?", 1).Update("password", hashed_password); err != nil {
 				fmt.Println(err)
 			}
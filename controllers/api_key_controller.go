@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Created api key Response, the key is only shown once
+type APIKeyResponse struct {
+	APIKey models.APIKey `json:"api_key"`
+	Key    string        `json:"key"`
+}
+
+func userAPIKeys(db *gorm.DB, tracer *observe.RouteTracer, user_id uint) ([]models.APIKey, error) {
+	var api_keys []models.APIKey
+	res := db.WithContext(tracer.Tracer).Model(&models.APIKey{}).Preload("Roles").Where("user_id = ?", user_id).Order("id desc").Find(&api_keys)
+	return api_keys, res.Error
+}
+
+func apiKeyDeleteFailed(contx *fiber.Ctx, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "API key not found",
+			Data:    nil,
+		})
+	}
+	return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+		Success: false,
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
+
+// Get API Keys
+// @Summary Get API Keys
+// @Description API keys of the token's user, the keys themselves are never returned again
+// @Tags API Keys
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=[]models.APIKey}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /apikeys [get]
+func GetAPIKeys(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	claims, err := userTokenClaims(contx)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	api_keys, err := userAPIKeys(db, tracer, uint(claims.UserID))
+	if err != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get api keys.",
+		Data:    api_keys,
+	})
+}
+
+// Post API Key
+// @Summary Post API Key
+// @Description Creates a named api key carrying some of the user's roles, it is sent in X-APP-TOKEN or X-API-KEY.
+// @Description The key is only returned in this answer. API keys can not be used to manage api keys.
+// @Tags API Keys
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param api_key body models.APIKeyPost true "API Key"
+// @Success 201 {object} common.ResponseHTTP{data=APIKeyResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /apikeys [post]
+func PostAPIKey(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	claims, err := userTokenClaims(contx)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	posted_key := new(models.APIKeyPost)
+	if err := contx.BodyParser(posted_key); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(posted_key); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	api_key, key, err := utils.IssueAPIKey(db, tracer.Tracer, uint(claims.UserID), posted_key.Name, posted_key.RoleIDs, posted_key.ExpiresInDays)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, utils.ErrAPIKeyRoles) {
+			status = http.StatusBadRequest
+		}
+		return contx.Status(status).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusCreated).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Creating API Key.",
+		Data:    APIKeyResponse{APIKey: api_key, Key: key},
+	})
+}
+
+// Delete API Key
+// @Summary Delete API Key
+// @Description Deletes an api key of the token's user, it stops working immediately
+// @Tags API Keys
+// @Security ApiKeyAuth
+// @Produce json
+// @Param key_id path int true "API Key ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /apikeys/{key_id} [delete]
+func DeleteAPIKey(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	claims, err := userTokenClaims(contx)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// validate path params
+	key_id, err := strconv.Atoi(contx.Params("key_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := utils.DeleteAPIKey(db, tracer.Tracer, uint(claims.UserID), uint(key_id)); err != nil {
+		return apiKeyDeleteFailed(contx, err)
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Deleting API Key.",
+		Data:    nil,
+	})
+}
+
+// Get User API Keys
+// @Summary Get User API Keys
+// @Description API keys of the user with their roles, expiry and last use
+// @Tags API Keys
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=[]models.APIKey}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /userapikeys/{user_id} [get]
+func GetUserAPIKeys(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	api_keys, err := userAPIKeys(db, tracer, uint(user_id))
+	if err != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get user api keys.",
+		Data:    api_keys,
+	})
+}
+
+// Delete User API Key
+// @Summary Delete User API Key
+// @Description Deletes an api key of the user, it stops working immediately
+// @Tags API Keys
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Param key_id path int true "API Key ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /userapikeys/{user_id}/{key_id} [delete]
+func DeleteUserAPIKey(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	key_id, err := strconv.Atoi(contx.Params("key_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := utils.DeleteAPIKey(db, tracer.Tracer, uint(user_id), uint(key_id)); err != nil {
+		return apiKeyDeleteFailed(contx, err)
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Deleting User API Key.",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,409 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetAppClients is a function to get the clients of an App
+// @Summary Get App Clients
+// @Description Get the client_credentials clients registered for an app
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Success 200 {object} common.ResponseHTTP{data=[]models.AppClient}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /appclient/{app_id} [get]
+func GetAppClients(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	//  parsing Query Prameters
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var app_clients []models.AppClient
+	if res := db.WithContext(tracer.Tracer).Model(&models.AppClient{}).Where("app_id = ?", app_id).Order("id").Find(&app_clients); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	//  Finally returing response if All the above compeleted successfully
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success got app clients.",
+		Data:    &app_clients,
+	})
+}
+
+// Add App Client
+// @Summary Add App Client
+// @Description Register a client for an app, the secret is only returned once
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Param client body models.AppClientPost true "Add App Client"
+// @Success 200 {object} common.ResponseHTTP{data=models.AppClientSecret}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /appclient/{app_id} [post]
+func PostAppClient(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database Connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validator initialization
+	validate := validator.New()
+
+	// validate path params
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	//validating post data
+	posted_client := new(models.AppClientPost)
+
+	//first parse request data
+	if err := contx.BodyParser(&posted_client); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(posted_client); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// checking the app exists
+	var app models.App
+	if res := db.WithContext(tracer.Tracer).Model(&models.App{}).Where("id = ?", app_id).First(&app); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	client_secret, secret_hash, err := utils.NewClientSecret()
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	//  initiate -> app client
+	app_client := new(models.AppClient)
+	app_client.Name = posted_client.Name
+	app_client.RedirectURIs = strings.Join(posted_client.RedirectURIs, " ")
+	app_client.SecretHash = secret_hash
+	app_client.Public = posted_client.Public
+	app_client.Active = true
+	app_client.AppID = app.ID
+
+	if err := db.WithContext(tracer.Tracer).Create(&app_client).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "App Client Creation Failed",
+			Data:    err.Error(),
+		})
+	}
+
+	// the secret of a public client is never handed out
+	if app_client.Public {
+		client_secret = ""
+	}
+
+	// return data if transaction is sucessfull
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "App client created successfully.",
+		Data: models.AppClientSecret{
+			ClientID:     app_client.ClientID,
+			ClientSecret: client_secret,
+		},
+	})
+}
+
+// Patch App Client
+// @Summary Patch App Client
+// @Description Update the name and redirect uris of an app client
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param client_id path string true "Client ID"
+// @Param client body models.AppClientPost true "Patch App Client"
+// @Success 200 {object} common.ResponseHTTP{data=models.AppClient}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /appclient/{client_id} [patch]
+func PatchAppClient(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database Connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	//  initialize data validator
+	validate := validator.New()
+
+	// validate data struct
+	patch_client := new(models.AppClientPost)
+	if err := contx.BodyParser(&patch_client); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validating
+	if err := validate.Struct(patch_client); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	client_id := contx.Params("client_id")
+	var app_client models.AppClient
+	if res := db.WithContext(tracer.Tracer).Model(&models.AppClient{}).Where("client_id = ?", client_id).First(&app_client); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := db.WithContext(tracer.Tracer).Model(&app_client).UpdateColumns(map[string]interface{}{
+		"name":          patch_client.Name,
+		"redirect_uris": strings.Join(patch_client.RedirectURIs, " "),
+	}).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "App client updated successfully.",
+		Data:    app_client,
+	})
+}
+
+// Rotate App Client Secret
+// @Summary Rotate App Client Secret
+// @Description Replace the secret of an app client, the new secret is only returned once
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param client_id path string true "Client ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.AppClientSecret}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /appclientsecret/{client_id} [put]
+func RotateAppClientSecret(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database Connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	client_id := contx.Params("client_id")
+	var app_client models.AppClient
+	if res := db.WithContext(tracer.Tracer).Model(&models.AppClient{}).Where("client_id = ?", client_id).First(&app_client); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	if app_client.Public {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "Public clients have no secret",
+			Data:    nil,
+		})
+	}
+
+	client_secret, secret_hash, err := utils.NewClientSecret()
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := db.WithContext(tracer.Tracer).Model(&app_client).UpdateColumn("secret_hash", secret_hash).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "App client secret rotated successfully.",
+		Data: models.AppClientSecret{
+			ClientID:     app_client.ClientID,
+			ClientSecret: client_secret,
+		},
+	})
+}
+
+// Activate/Deactivate App Client
+// @Summary Activate/Deactivate App Client
+// @Description Activate/Deactivate App Client
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param client_id path string true "Client ID"
+// @Param status query bool true "Active"
+// @Success 200 {object} common.ResponseHTTP{data=models.AppClient}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /appclientstatus/{client_id} [put]
+func ActivateDeactivateAppClient(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database Connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	status, err := strconv.ParseBool(contx.Query("status"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	client_id := contx.Params("client_id")
+	var app_client models.AppClient
+	if res := db.WithContext(tracer.Tracer).Model(&models.AppClient{}).Where("client_id = ?", client_id).First(&app_client); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := db.WithContext(tracer.Tracer).Model(&app_client).UpdateColumn("active", status).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "App client status updated successfully.",
+		Data:    app_client,
+	})
+}
+
+// DeleteAppClient function removes an app client
+// @Summary Remove App Client
+// @Description Remove app client by client ID
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param client_id path string true "Client ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /appclient/{client_id} [delete]
+func DeleteAppClient(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	client_id := contx.Params("client_id")
+	var app_client models.AppClient
+	if res := db.WithContext(tracer.Tracer).Model(&models.AppClient{}).Where("client_id = ?", client_id).First(&app_client); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := db.WithContext(tracer.Tracer).Delete(&app_client).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "Error deleting app client",
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "App client deleted successfully.",
+		Data:    app_client,
+	})
+}
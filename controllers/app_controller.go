@@ -253,13 +253,13 @@ func GetAppRoleAllUUID(contx *fiber.Ctx) error {
 
 // GetAppRoleMatrix is a function to get APP
 // @Summary Get App Roles Matrix by UUID
-// @Description Get app endpoint role matrix by UUID
+// @Description Get app endpoint role matrix by UUID, every role allowed on an endpoint is listed
 // @Tags ClientOnly
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param app_uuid path string true "App UUID"
-// @Success 200 {object} common.ResponseHTTP{data=map[string]string}
+// @Success 200 {object} common.ResponseHTTP{data=map[string][]string}
 // @Failure 404 {object} common.ResponseHTTP{}
 // @Router /clientmatrix/{app_uuid} [get]
 func GetClientMatrix(contx *fiber.Ctx) error {
@@ -301,13 +301,13 @@ func GetClientMatrix(contx *fiber.Ctx) error {
 
 // GetAppRoleMatrixPath is a function to get APP
 // @Summary Get App Roles Matrix by UUID
-// @Description Get app endpoint role matrix by UUID
+// @Description Get app endpoint role matrix by UUID, every role allowed on an endpoint is listed
 // @Tags ClientOnly
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param app_uuid path string true "App UUID"
-// @Success 200 {object} common.ResponseHTTP{data=map[string]string}
+// @Success 200 {object} common.ResponseHTTP{data=map[string][]string}
 // @Failure 404 {object} common.ResponseHTTP{}
 // @Router /clientmatrixpath/{app_uuid} [get]
 func GetClientMatrixPath(contx *fiber.Ctx) error {
@@ -413,6 +413,7 @@ func PostApp(contx *fiber.Ctx) error {
 
 	// close transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return data if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -502,6 +503,8 @@ func PatchApp(contx *fiber.Ctx) error {
 		})
 	}
 
+	utils.InvalidatePermissions(db, tracer.Tracer)
+
 	// Return  success response
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
 		Success: true,
@@ -571,6 +574,7 @@ func DeleteApp(contx *fiber.Ctx) error {
 
 	// Commit the transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// Return success respons
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -580,6 +584,120 @@ func DeleteApp(contx *fiber.Ctx) error {
 	})
 }
 
+// App MFA Policy
+// @Summary App MFA Policy
+// @Description Require MFA for every user holding a role of the app
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Param required query bool true "MFA Required"
+// @Success 200 {object} common.ResponseHTTP{data=models.App}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /appmfa/{app_id} [put]
+func AppMFAPolicy(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	required := contx.QueryBool("required")
+
+	var app models.App
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", id).First(&app).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := db.WithContext(tracer.Tracer).Model(&app).Update("mfa_required", required).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	app.MFARequired = required
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Updating App MFA Policy.",
+		Data:    app,
+	})
+}
+
+// App Magic Link Policy
+// @Summary App Magic Link Policy
+// @Description Allow or forbid passwordless logins to the app with emailed magic links
+// @Tags Apps
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Param enabled query bool true "Magic Link Enabled"
+// @Success 200 {object} common.ResponseHTTP{data=models.App}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /appmagiclink/{app_id} [put]
+func AppMagicLinkPolicy(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	enabled := contx.QueryBool("enabled")
+
+	var app models.App
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", id).First(&app).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := db.WithContext(tracer.Tracer).Model(&app).Update("magic_link_enabled", enabled).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	app.MagicLinkEnabled = enabled
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Updating App Magic Link Policy.",
+		Data:    app,
+	})
+}
+
 // ################################################################
 // Relationship Based Endpoints
 // ################################################################
@@ -648,6 +766,7 @@ func AddRoleApps(contx *fiber.Ctx) error {
 		})
 	}
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return value if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -719,6 +838,7 @@ func DeleteRoleApps(contx *fiber.Ctx) error {
 		})
 	}
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return value if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
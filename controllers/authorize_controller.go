@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"blue-admin.com/common"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Authorization request of an app client, the credentials may be sent with basic auth instead
+type AuthorizeRequest struct {
+	utils.AuthorizationRequest
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Authorize is a function to decide a request to a client app
+// @Summary Authorize Request
+// @Description Policy decision point for app clients, decides whether the token's bearer, the subject (a user uuid) or an anonymous caller may make the request named by its method and path or endpoint name, with the same matrix, role inheritance and grant conditions as the route validator. Denials are successful responses with allowed false and the reason.
+// @Tags ClientOnly
+// @Accept json
+// @Produce json
+// @Param request body AuthorizeRequest true "Authorization Request"
+// @Success 200 {object} common.ResponseHTTP{data=utils.AuthorizationDecision}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Router /authorize [post]
+func PostAuthorize(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	authorize_request := new(AuthorizeRequest)
+	if err := contx.BodyParser(authorize_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	client_id, client_secret := authorize_request.ClientID, authorize_request.ClientSecret
+	if basic_id, basic_secret := oidcClientCredentials(contx); basic_id != "" {
+		client_id, client_secret = basic_id, basic_secret
+	}
+
+	decision, err := utils.AuthorizeForClient(db, tracer.Tracer, client_id, client_secret, authorize_request.AuthorizationRequest)
+	switch {
+	case errors.Is(err, utils.ErrClientInvalid):
+		contx.Set(fiber.HeaderWWWAuthenticate, "Basic")
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "client authentication failed",
+			Data:    nil,
+		})
+	case errors.Is(err, utils.ErrAuthorizationOtherApp):
+		return contx.Status(http.StatusForbidden).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, utils.ErrAuthorizationTarget):
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case err != nil:
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: decision.Reason,
+		Data:    decision,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"blue-admin.com/common"
 	"blue-admin.com/models"
 	"blue-admin.com/observe"
+	"blue-admin.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mitchellh/mapstructure"
@@ -163,7 +164,8 @@ func GetAppEndpointsAllUUID(contx *fiber.Ctx) error {
 	var total_counter int64
 	count_string := `select distinct endpoints.id from endpoints inner join features on
 							endpoints.feature_id = features.id
-							inner join roles on features.role_id= roles.id
+							inner join feature_roles on feature_roles.feature_id = features.id
+							inner join roles on feature_roles.role_id= roles.id
 							inner join apps on roles.app_id == apps.id
 							where apps.uuid = ? ORDER BY endpoints.id;`
 	if res := db.WithContext(tracer.Tracer).Raw(count_string, uuid, Limit, Page).Count(&total_counter); res.Error != nil {
@@ -178,7 +180,8 @@ func GetAppEndpointsAllUUID(contx *fiber.Ctx) error {
 	// select apps.id as appID, roles.id, roles.name, roles.description,roles.active from roles inner join apps on roles.app_id == apps.id where apps.uuid =="0191c74f-d039-71c6-a3be-66e2571a9cf1" ORDER BY roles.id;
 	query_string := `select distinct endpoints.id,endpoints.name, endpoints.method,endpoints.route_path,endpoints.description from endpoints inner join features on
 							endpoints.feature_id = features.id
-							inner join roles on features.role_id= roles.id
+							inner join feature_roles on feature_roles.feature_id = features.id
+							inner join roles on feature_roles.role_id= roles.id
 							inner join apps on roles.app_id == apps.id
 							where apps.uuid = ? ORDER BY endpoints.id LIMIT ? OFFSET ?;`
 
@@ -268,6 +271,7 @@ func PostEndpoint(contx *fiber.Ctx) error {
 
 	// close transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return data if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -356,6 +360,8 @@ func PatchEndpoint(contx *fiber.Ctx) error {
 		})
 	}
 
+	utils.InvalidatePermissions(db, tracer.Tracer)
+
 	// Return  success response
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
 		Success: true,
@@ -425,6 +431,7 @@ func DeleteEndpoint(contx *fiber.Ctx) error {
 
 	// Commit the transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// Return success respons
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -7,6 +7,7 @@ import (
 	"blue-admin.com/common"
 	"blue-admin.com/models"
 	"blue-admin.com/observe"
+	"blue-admin.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mitchellh/mapstructure"
@@ -180,6 +181,7 @@ func PostFeature(contx *fiber.Ctx) error {
 
 	// close transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return data if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -267,6 +269,8 @@ func PatchFeature(contx *fiber.Ctx) error {
 		})
 	}
 
+	utils.InvalidatePermissions(db, tracer.Tracer)
+
 	// Return  success response
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
 		Success: true,
@@ -336,6 +340,7 @@ func DeleteFeature(contx *fiber.Ctx) error {
 
 	// Commit the transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// Return success respons
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -413,6 +418,7 @@ func AddEndpointFeatures(contx *fiber.Ctx) error {
 		})
 	}
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return value if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -484,6 +490,7 @@ func DeleteEndpointFeatures(contx *fiber.Ctx) error {
 		})
 	}
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return value if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -576,6 +583,7 @@ func ActivateDeactivateFeature(contx *fiber.Ctx) error {
 		})
 	}
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 	if feature.ID != 0 {
 		feature.Active = active
 		// return value if transaction is sucessfull
@@ -644,7 +652,8 @@ func GetAppFeaturesAllUUID(contx *fiber.Ctx) error {
 	// Preparing and querying database using Gorm
 	//getting total count first
 	var total_counter int64
-	count_string := `select distinct features.id from features  inner join roles on features.role_id == roles.id
+	count_string := `select distinct features.id from features inner join feature_roles on feature_roles.feature_id == features.id
+							inner join roles on feature_roles.role_id == roles.id
 							inner join apps on roles.app_id == apps.id
 							where apps.uuid = ? ORDER BY features.id;`
 	if res := db.WithContext(tracer.Tracer).Raw(count_string, uuid, Limit, Page).Count(&total_counter); res.Error != nil {
@@ -657,7 +666,8 @@ func GetAppFeaturesAllUUID(contx *fiber.Ctx) error {
 
 	var features []models.FeaturePut
 	// select apps.id as appID, roles.id, roles.name, roles.description,roles.active from roles inner join apps on roles.app_id == apps.id where apps.uuid =="0191c74f-d039-71c6-a3be-66e2571a9cf1" ORDER BY roles.id;
-	query_string := `select distinct features.id, features.name, features.description,features.active from features inner join roles on features.role_id == roles.id
+	query_string := `select distinct features.id, features.name, features.description,features.active from features inner join feature_roles on feature_roles.feature_id == features.id
+							inner join roles on feature_roles.role_id == roles.id
 							inner join apps on roles.app_id == apps.id
 							where apps.uuid = ? ORDER BY features.id LIMIT ? OFFSET ?;`
 
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Impersonation Response, the access token carries the user's roles and the actor in its act claim
+type ImpersonationResponse struct {
+	AccessToken   string               `json:"access_token"`
+	TokenType     string               `json:"token_type"`
+	Impersonation models.Impersonation `json:"impersonation"`
+}
+
+// Post Impersonation
+// @Summary Impersonate User
+// @Description Issues a short lived access token to act as the user, only for holders of the IMPERSONATION_ROLES, there is no refresh token
+// @Tags Impersonation
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Param impersonation body models.ImpersonationPost true "Reason"
+// @Success 201 {object} common.ResponseHTTP{data=ImpersonationResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /impersonate/{user_id} [post]
+func PostImpersonation(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	claims, err := userTokenClaims(contx)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	impersonation_request := new(models.ImpersonationPost)
+	if err := contx.BodyParser(impersonation_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(impersonation_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	token, impersonation, err := utils.ImpersonateUser(db, tracer.Tracer, claims, uint(user_id), impersonation_request.Reason, contx.IP())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, utils.ErrImpersonationNotAllowed) {
+			status = http.StatusForbidden
+		} else if errors.Is(err, utils.ErrImpersonationTarget) {
+			status = http.StatusNotFound
+		}
+		return contx.Status(status).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusCreated).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Impersonation started, every request is audited.",
+		Data: ImpersonationResponse{
+			AccessToken:   token,
+			TokenType:     "Bearer",
+			Impersonation: impersonation,
+		},
+	})
+}
+
+// Get User Impersonations
+// @Summary Get User Impersonations
+// @Description Impersonations started by or targeting the user, newest first
+// @Tags Impersonation
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=[]models.Impersonation}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /impersonations/{user_id} [get]
+func GetUserImpersonations(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	impersonations, err := utils.UserImpersonations(db, tracer.Tracer, uint(user_id))
+	if err != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get impersonations.",
+		Data:    impersonations,
+	})
+}
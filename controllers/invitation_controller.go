@@ -0,0 +1,474 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mitchellh/mapstructure"
+	"gorm.io/gorm"
+)
+
+// Invitation accept Request for Endpoint, the token comes from the emailed link
+type InvitationAcceptPost struct {
+	Token    string `json:"token" validate:"required"`
+	Name     string `json:"name"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Email verification Request for Endpoint, the token comes from the emailed link
+type EmailVerificationPost struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Post Invitation
+// @Summary Invite User
+// @Description Invites an email to an app with preselected roles of the app, the invitee sets a password through the emailed link
+// @Tags Invitations
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param invitation body models.InvitationPost true "Invitation"
+// @Success 200 {object} common.ResponseHTTP{data=models.Invitation}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{}
+// @Router /invitation [post]
+func PostInvitation(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	posted_invitation := new(models.InvitationPost)
+	if err := contx.BodyParser(posted_invitation); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(posted_invitation); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var app models.App
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", posted_invitation.AppID).First(&app).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// only roles of the app can be preselected
+	roles := []models.Role{}
+	role_ids := []uint{}
+	seen := map[uint]bool{}
+	for _, value := range posted_invitation.RoleIDs {
+		if !seen[value] {
+			seen[value] = true
+			role_ids = append(role_ids, value)
+		}
+	}
+	if len(role_ids) > 0 {
+		if res := db.WithContext(tracer.Tracer).Model(&models.Role{}).Where("id IN ? AND app_id = ?", role_ids, app.ID).Find(&roles); res.Error != nil {
+			return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: res.Error.Error(),
+				Data:    nil,
+			})
+		}
+		if len(roles) != len(role_ids) {
+			return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: "Roles must exist and belong to the app",
+				Data:    nil,
+			})
+		}
+	}
+
+	// an invited user is kept as a placeholder so the email is reserved, it can not log in until accepting
+	var user models.User
+	res := db.WithContext(tracer.Tracer).Model(&models.User{}).Where("email = ?", posted_invitation.Email).First(&user)
+	if res.Error != nil && !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+	if res.Error == nil && user.Status != models.UserStatusInvited {
+		return contx.Status(http.StatusConflict).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "User already exists, assign the roles directly",
+			Data:    nil,
+		})
+	}
+
+	invited_by := 0
+	if claims, err := utils.ParseJWTToken(contx.Get("X-APP-TOKEN")); err == nil {
+		invited_by = claims.UserID
+	}
+
+	invitation := models.Invitation{
+		Email:     posted_invitation.Email,
+		AppID:     app.ID,
+		Roles:     roles,
+		InvitedBy: uint(invited_by),
+		ExpiresAt: time.Now().UTC().Add(utils.InvitationLifeTime()),
+	}
+	err := db.WithContext(tracer.Tracer).Transaction(func(tx *gorm.DB) error {
+		if user.ID == 0 {
+			placeholder_password, err := utils.GenerateOpaqueToken(32)
+			if err != nil {
+				return err
+			}
+			name := posted_invitation.Name
+			if name == "" {
+				name = posted_invitation.Email
+			}
+			user = models.User{
+				Name:     name,
+				Email:    posted_invitation.Email,
+				Password: placeholder_password,
+				Status:   models.UserStatusInvited,
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		}
+		invitation.UserID = user.ID
+		return tx.Create(&invitation).Error
+	})
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	token, err := utils.CreateInvitationToken(invitation)
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := utils.SendInvitationEmail(invitation, app.Name, token); err != nil {
+		fmt.Printf("sending invitation email failed: %v\n", err)
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Inviting User.",
+		Data:    invitation,
+	})
+}
+
+// Get Invitations
+// @Summary Get App Invitations
+// @Description Open invitations of the app
+// @Tags Invitations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Success 200 {object} common.ResponseHTTP{data=[]models.Invitation}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /invitation/{app_id} [get]
+func GetInvitations(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	invitations := []models.Invitation{}
+	if res := db.WithContext(tracer.Tracer).Model(&models.Invitation{}).Preload("Roles").Where("app_id = ? AND accepted_at IS NULL", app_id).Order("id asc").Find(&invitations); res.Error != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get invitations.",
+		Data:    invitations,
+	})
+}
+
+// Delete Invitation
+// @Summary Revoke Invitation
+// @Description Revokes an open invitation, the placeholder user is removed when no other invitation is open
+// @Tags Invitations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param invitation_id path int true "Invitation ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /invitation/{invitation_id} [delete]
+func DeleteInvitation(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	invitation_id, err := strconv.Atoi(contx.Params("invitation_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var invitation models.Invitation
+	if res := db.WithContext(tracer.Tracer).Model(&models.Invitation{}).Where("id = ? AND accepted_at IS NULL", invitation_id).First(&invitation); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	err = db.WithContext(tracer.Tracer).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&invitation).Association("Roles").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Delete(&invitation).Error; err != nil {
+			return err
+		}
+		var open int64
+		if err := tx.Model(&models.Invitation{}).Where("user_id = ? AND accepted_at IS NULL", invitation.UserID).Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return nil
+		}
+		return tx.Where("id = ? AND status = ?", invitation.UserID, models.UserStatusInvited).Delete(&models.User{}).Error
+	})
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Revoking Invitation.",
+		Data:    nil,
+	})
+}
+
+// Accept Invitation
+// @Summary Accept Invitation
+// @Description Sets the invitee's password, grants the invited roles and marks the email verified
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Param invitation body InvitationAcceptPost true "Accept"
+// @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Failure 400 {object} common.ResponseHTTP{data=[]passwords.Violation}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /onboarding/invitation [post]
+func PostInvitationAccept(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	accept_request := new(InvitationAcceptPost)
+	if err := contx.BodyParser(accept_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(accept_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	invitation, err := utils.FindInvitation(db, tracer.Tracer, accept_request.Token)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	user, err := utils.AcceptInvitation(db, tracer.Tracer, invitation, accept_request.Name, accept_request.Password)
+	if errors.Is(err, utils.ErrInvitationInvalid) {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	} else if err != nil {
+		return passwordPolicyFailed(contx, err)
+	}
+
+	var response_user models.UserGet
+	mapstructure.Decode(user, &response_user)
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Accepting Invitation.",
+		Data:    response_user,
+	})
+}
+
+// Send Email Verification
+// @Summary Send Email Verification
+// @Description Emails the user a signed link confirming the address
+// @Tags Users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 202 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /emailverification/{user_id} [post]
+func PostEmailVerification(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var user models.User
+	if err := db.WithContext(tracer.Tracer).Where("id = ? AND status <> ?", user_id, models.UserStatusInvited).First(&user).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	token, err := utils.CreateEmailVerificationToken(user)
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := utils.SendEmailVerification(user, token); err != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Verification email sent.",
+		Data:    nil,
+	})
+}
+
+// Verify Email
+// @Summary Verify Email
+// @Description Confirms the address with the emailed link, pending users become active
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param verification body EmailVerificationPost true "Verification"
+// @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /onboarding/verify [post]
+func PostVerifyEmail(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	verification_request := new(EmailVerificationPost)
+	if err := contx.BodyParser(verification_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(verification_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	user, err := utils.VerifyEmail(db, tracer.Tracer, verification_request.Token)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var response_user models.UserGet
+	mapstructure.Decode(user, &response_user)
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Verifying Email.",
+		Data:    response_user,
+	})
+}
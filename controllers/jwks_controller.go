@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"net/http"
+
+	"blue-admin.com/common"
+	"blue-admin.com/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetJWKS is a function to get the public token signing keys
+// served at /.well-known/jwks.json outside the api group, so it is not part of the swagger docs
+func GetJWKS(contx *fiber.Ctx) error {
+
+	jwks, err := utils.SigningKeys.JWKS()
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// the key set is returned as is so standard jwt libraries can consume it
+	contx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return contx.Status(http.StatusOK).JSON(jwks)
+}
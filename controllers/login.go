@@ -1,42 +1,67 @@
 package controllers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"blue-admin.com/common"
+	"blue-admin.com/configs"
 	"blue-admin.com/database"
 	"blue-admin.com/models"
 	"blue-admin.com/observe"
 	"blue-admin.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 // Login Request for Endpoint
 type LoginPost struct {
-	GrantType string `json:"grant_type" validate:"required" example:"authorization_code"`
-	Email     string `json:"email" validate:"email,min=6,max=32"`
-	Password  string `json:"password"`
-	Token     string `json:"token"`
+	GrantType    string `json:"grant_type" validate:"required" example:"authorization_code"`
+	Email        string `json:"email" validate:"omitempty,email,min=6,max=32"`
+	Password     string `json:"password" validate:"omitempty,max=128"`
+	Token        string `json:"token"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Code         string `json:"code"`
+	// the tokens are scoped to the app, their aud is the app uuid and only its roles are included, defaults to blue-admin's app
+	AppUUID string `json:"app_uuid"`
 }
 
 // Access token Response
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+}
+
+// MFA challenge Response, the mfa_token is sent back with a code using the mfa grant
+type MFAChallengeResponse struct {
+	MFAToken           string `json:"mfa_token"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
 }
 
 // Login is a function to login by EMAIL and ID
 // @Summary Auth
-// @Description Login
+// @Description Login with grant_type authorization_code, mfa, magic_link, refresh_token, client_credentials or token_decode
+// @Description magic_link exchanges the token of a link from /magiclink, the session is scoped to the link's app
+// @Description authorization_code and magic_link answer 403 with an mfa_token when a second factor is required
+// @Description and 403 with a reset_token for /password/reset when the password has to be changed
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param user body LoginPost true "Login"
 // @Success 200 {object} common.ResponseHTTP{data=TokenResponse{}}
+// @Failure 403 {object} common.ResponseHTTP{data=MFAChallengeResponse{}}
+// @Failure 403 {object} common.ResponseHTTP{data=PasswordChangeResponse{}}
 // @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 429 {object} common.ResponseHTTP{} "too many failed attempts, see the Retry-After header"
 // @Failure 503 {object} common.ResponseHTTP{}
 // @Router /login [post]
 func PostLogin(contx *fiber.Ctx) error {
@@ -69,29 +94,78 @@ func PostLogin(contx *fiber.Ctx) error {
 			Data:    nil,
 		})
 	}
+
+	// unknown apps are refused before the credentials are checked, magic links carry their own app
+	var app_id *uint
+	var err error
+	if login_request_data.AppUUID != "" || login_request_data.GrantType != "magic_link" {
+		app_id, err = loginApp(db, tracer.Tracer, login_request_data.AppUUID)
+	}
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
 	switch login_request_data.GrantType {
 	case "authorization_code":
-		var user models.User
-		res := db.WithContext(tracer.Tracer).Model(&models.User{}).Preload(clause.Associations).Where("email = ? AND disabled = ?", login_request_data.Email, false).First(&user)
-		if res.Error != nil {
+		// locked accounts, blocked ips and attempts inside the backoff are turned away before the password check
+		retry_after, err := utils.LoginRetryAfter(db, tracer.Tracer, login_request_data.Email, contx.IP())
+		if err != nil {
 			return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
 				Success: false,
-				Message: res.Error.Error(),
+				Message: err.Error(),
 				Data:    nil,
 			})
-		} else if utils.PasswordsMatch(user.Password, login_request_data.Password) {
-			roles := make([]string, 0, 20)
-			for _, value := range user.Roles {
+		}
+		if retry_after > 0 {
+			return loginThrottled(contx, retry_after)
+		}
+
+		// the configured backends are tried in order, see utils.Authenticators
+		user, err := utils.AuthenticateUser(db, tracer.Tracer, login_request_data.Email, login_request_data.Password)
+		if err != nil && !errors.Is(err, utils.ErrInvalidCredentials) {
+			return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		} else if err == nil {
+			// second step required, only a short lived challenge token is handed out
+			// and the failure counter is only cleared once the second factor passed
+			mfa_required, err := utils.MFARequired(db, tracer.Tracer, user)
+			if err != nil {
+				return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+					Success: false,
+					Message: err.Error(),
+					Data:    nil,
+				})
+			}
+			if mfa_required {
+				return mfaChallenge(contx, user)
+			}
+			utils.RecordLoginSuccess(db, tracer.Tracer, user.Email)
 
-				roles = append(roles, string(value.Name))
+			// forced by an admin or expired by the policy
+			if change_due, err := utils.PasswordChangeDue(db, tracer.Tracer, user); err != nil {
+				return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+					Success: false,
+					Message: err.Error(),
+					Data:    nil,
+				})
+			} else if change_due {
+				return passwordChangeRequired(contx, db, tracer.Tracer, user)
 			}
-			accessString, _ := utils.CreateJWTToken(user.Email, user.UUID, int(user.ID), roles, 60)
-			refreshString, _ := utils.CreateJWTToken(user.Email, user.UUID, int(user.ID), roles, 65)
 
-			data := TokenResponse{
-				AccessToken:  accessString,
-				RefreshToken: refreshString,
-				TokenType:    "Bearer",
+			data, err := issueSessionTokens(contx, db, tracer.Tracer, user, app_id, "", false)
+			if err != nil {
+				return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+					Success: false,
+					Message: err.Error(),
+					Data:    nil,
+				})
 			}
 			return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
 				Success: true,
@@ -99,6 +173,14 @@ func PostLogin(contx *fiber.Ctx) error {
 				Data:    data,
 			})
 		} else {
+			// unknown emails count like wrong passwords so the answer does not reveal which accounts exist
+			if err := utils.RecordLoginFailure(db, tracer.Tracer, login_request_data.Email, contx.IP()); err != nil {
+				return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+					Success: false,
+					Message: err.Error(),
+					Data:    nil,
+				})
+			}
 			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
 				Success: false,
 				Message: "Make sure You are Providing the Correct Credentials",
@@ -106,31 +188,220 @@ func PostLogin(contx *fiber.Ctx) error {
 			})
 		}
 		// return "something"
-	case "refresh_token":
-		claims, err := utils.ParseJWTToken(login_request_data.Token)
-		email := claims.Email
-		uuid := claims.UUID
-		roles := claims.Roles
-		user_id := claims.UserID
-		if err == nil {
-			accessString, _ := utils.CreateJWTToken(email, uuid, user_id, roles, 60)
-			refreshString, _ := utils.CreateJWTToken(email, uuid, user_id, roles, 65)
-			data := TokenResponse{
-				AccessToken:  accessString,
-				RefreshToken: refreshString,
-				TokenType:    "Bearer",
+	case "mfa":
+		// second step of the login, the challenge token with a totp or recovery code
+		mfa_claim, err := utils.ParseMFAChallengeToken(login_request_data.Token)
+		if err != nil {
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    "Authenthication Failed",
+			})
+		}
+
+		var user models.User
+		res := db.WithContext(tracer.Tracer).Model(&models.User{}).Preload(clause.Associations).Where("id = ? AND disabled = ?", mfa_claim.UserID, false).First(&user)
+		if res.Error != nil {
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: "User not found or disabled",
+				Data:    "Authenthication Failed",
+			})
+		}
+		if !user.MFAEnabled {
+			return contx.Status(http.StatusForbidden).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: "MFA enrollment required",
+				Data:    "Authenthication Failed",
+			})
+		}
+
+		// guessing codes counts towards the same lockout as guessing passwords
+		retry_after, err := utils.LoginRetryAfter(db, tracer.Tracer, user.Email, contx.IP())
+		if err != nil {
+			return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		if retry_after > 0 {
+			return loginThrottled(contx, retry_after)
+		}
+
+		if err := utils.VerifyMFACode(db, tracer.Tracer, user, login_request_data.Code); err != nil {
+			if err := utils.RecordLoginFailure(db, tracer.Tracer, user.Email, contx.IP()); err != nil {
+				return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+					Success: false,
+					Message: err.Error(),
+					Data:    nil,
+				})
 			}
-			return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
-				Success: true,
-				Message: "Authorization Granted",
-				Data:    data,
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    "Authenthication Failed",
 			})
 		}
+		utils.RevokeMFAChallengeToken(db, tracer.Tracer, mfa_claim)
+		utils.RecordLoginSuccess(db, tracer.Tracer, user.Email)
+		if change_due, err := utils.PasswordChangeDue(db, tracer.Tracer, user); err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		} else if change_due {
+			return passwordChangeRequired(contx, db, tracer.Tracer, user)
+		}
 
-		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
-			Success: false,
-			Message: "Request Type Unknown",
-			Data:    "Currently Not Implemented",
+		data, err := issueSessionTokens(contx, db, tracer.Tracer, user, app_id, "", true)
+		if err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
+			Success: true,
+			Message: "Authorization Granted",
+			Data:    data,
+		})
+	case "magic_link":
+		// passwordless login, the emailed token is single use and bound to the app it was requested for,
+		// the session is scoped to the link's app, app_uuid is only checked against it when given
+		magic_link_token, app, err := utils.ConsumeMagicLinkToken(db, tracer.Tracer, login_request_data.Token, app_id)
+		if err != nil {
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    "Authenthication Failed",
+			})
+		}
+
+		var user models.User
+		res := db.WithContext(tracer.Tracer).Model(&models.User{}).Preload(clause.Associations).Where("id = ? AND disabled = ? AND status <> ?", magic_link_token.UserID, false, models.UserStatusInvited).First(&user)
+		if res.Error != nil {
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: "User not found or disabled",
+				Data:    "Authenthication Failed",
+			})
+		}
+		retry_after, err := utils.LoginRetryAfter(db, tracer.Tracer, user.Email, contx.IP())
+		if err != nil {
+			return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		if retry_after > 0 {
+			return loginThrottled(contx, retry_after)
+		}
+
+		// the link only proves access to the mailbox, a required second factor still applies
+		mfa_required, err := utils.MFARequired(db, tracer.Tracer, user)
+		if err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		if mfa_required {
+			return mfaChallenge(contx, user)
+		}
+		utils.RecordLoginSuccess(db, tracer.Tracer, user.Email)
+
+		data, err := issueSessionTokens(contx, db, tracer.Tracer, user, &app.ID, "", false)
+		if err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
+			Success: true,
+			Message: "Authorization Granted",
+			Data:    data,
+		})
+	case "refresh_token":
+		// consuming the presented token, reuse revokes the whole token family
+		refresh_token, err := utils.ConsumeRefreshToken(db, tracer.Tracer, login_request_data.Token)
+		if err != nil {
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    "Authenthication Failed",
+			})
+		}
+
+		// reloading the user so disabled users and removed roles take effect on refresh
+		var user models.User
+		res := db.WithContext(tracer.Tracer).Model(&models.User{}).Preload(clause.Associations).Where("id = ? AND disabled = ?", refresh_token.UserID, false).First(&user)
+		if res.Error != nil {
+			utils.RevokeRefreshFamily(db, tracer.Tracer, refresh_token.FamilyID)
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: "User not found or disabled",
+				Data:    "Authenthication Failed",
+			})
+		}
+
+		// the idle timeout is checked against the last activity before this refresh,
+		// sessions of OpenID Connect clients are only refreshed at their token endpoint
+		data, err := issueTokenPair(db, tracer.Tracer, user, refresh_token.FamilyID, "", refresh_token.MFA)
+		if sessionEnded(err) {
+			utils.RevokeRefreshFamily(db, tracer.Tracer, refresh_token.FamilyID)
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    "Authenthication Failed",
+			})
+		} else if err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		utils.TouchSession(db, tracer.Tracer, refresh_token.FamilyID)
+		return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
+			Success: true,
+			Message: "Authorization Granted",
+			Data:    data,
+		})
+	case "client_credentials":
+		// machine to machine access, the token carries the roles of the client's app
+		app_client, app, roles, err := utils.AuthenticateClient(db, tracer.Tracer, login_request_data.ClientID, login_request_data.ClientSecret)
+		if err != nil {
+			return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    "Authenthication Failed",
+			})
+		}
+
+		// no refresh token, clients authenticate again when the access token expires
+		client_token_life, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("CLIENT_TOKEN_LIFE_TIME", "60"))
+		accessString, err := utils.CreateClientJWTToken(app_client.ClientID, app.UUID, roles, client_token_life)
+		if err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
+			Success: true,
+			Message: "Authorization Granted",
+			Data: TokenResponse{
+				AccessToken: accessString,
+				TokenType:   "Bearer",
+			},
 		})
 	case "token_decode":
 		claims, err := utils.ParseJWTToken(login_request_data.Token)
@@ -157,18 +428,162 @@ func PostLogin(contx *fiber.Ctx) error {
 
 }
 
+// 429 answer telling the client when it may try again
+func loginThrottled(contx *fiber.Ctx, retry_after time.Duration) error {
+	contx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retry_after/time.Second)))
+	return contx.Status(http.StatusTooManyRequests).JSON(common.ResponseHTTP{
+		Success: false,
+		Message: utils.ErrLoginThrottled.Error(),
+		Data:    "Authenthication Failed",
+	})
+}
+
+// 403 answer handing out a reset token, the user has to choose a new password before getting tokens
+func passwordChangeRequired(contx *fiber.Ctx, db *gorm.DB, ctx context.Context, user models.User) error {
+	reset_token, err := utils.IssuePasswordResetToken(db, ctx, user.ID)
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	return contx.Status(http.StatusForbidden).JSON(common.ResponseHTTP{
+		Success: false,
+		Message: "Password Change Required",
+		Data:    PasswordChangeResponse{ResetToken: reset_token},
+	})
+}
+
+// 403 answer with a short lived challenge token, the login is finished with the mfa grant
+func mfaChallenge(contx *fiber.Ctx, user models.User) error {
+	mfa_token, err := utils.CreateMFAChallengeToken(user.ID, !user.MFAEnabled)
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	return contx.Status(http.StatusForbidden).JSON(common.ResponseHTTP{
+		Success: false,
+		Message: "MFA Required",
+		Data: MFAChallengeResponse{
+			MFAToken:           mfa_token,
+			EnrollmentRequired: !user.MFAEnabled,
+		},
+	})
+}
+
+// App named by the app_uuid of a login, blue-admin's own app when none is given, unknown apps are not recorded
+func loginApp(db *gorm.DB, ctx context.Context, app_uuid string) (*uint, error) {
+	if app_uuid == "" {
+		app_uuid = utils.BlueAdminAudience()
+	}
+	app, err := utils.FindActiveApp(db, ctx, app_uuid)
+	if err != nil {
+		return nil, err
+	}
+	return &app.ID, nil
+}
+
+// Records the login as a new session and issues its first token pair
+func issueSessionTokens(contx *fiber.Ctx, db *gorm.DB, ctx context.Context, user models.User, app_id *uint, client_id string, mfa bool) (TokenResponse, error) {
+	session, err := utils.StartSession(db, ctx, user.ID, app_id, client_id, contx.IP(), contx.Get(fiber.HeaderUserAgent), mfa)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	return issueTokenPair(db, ctx, user, session.SessionID, client_id, mfa)
+}
+
+// Mints an access token from the user's current roles and a refresh token in the given family,
+// the family is the session id carried by the access token as its sid claim
+// mfa is carried in both so refreshed tokens keep the second factor claim
+// tokens have the session's app as audience and only the app's roles, sessions started
+// before logins were scoped to an app get blue-admin's app
+// lifetimes follow the token policy of the session's app, an expired session is signed out
+// only the client the session was started for, none for direct logins, gets tokens in it
+func issueTokenPair(db *gorm.DB, ctx context.Context, user models.User, family_id string, client_id string, mfa bool) (TokenResponse, error) {
+	session_policy, err := utils.LoadSessionPolicy(db, ctx, family_id)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	if session_policy.Session.ClientID != client_id {
+		return TokenResponse{}, utils.ErrSessionClient
+	}
+	now := time.Now().UTC()
+	if session_policy.Expired(now) {
+		utils.RevokeSession(db, ctx, user.ID, family_id)
+		return TokenResponse{}, utils.ErrSessionExpired
+	}
+	app := session_policy.App
+	if app == nil {
+		admin_app, err := utils.FindActiveApp(db, ctx, utils.BlueAdminAudience())
+		if err != nil {
+			return TokenResponse{}, err
+		}
+		app = &admin_app
+	}
+
+	access_expires := session_policy.AccessExpiry(now)
+	access_claim := utils.UserClaim{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "UI Authentication Token", Audience: jwt.ClaimStrings{app.UUID}, ExpiresAt: jwt.NewNumericDate(access_expires)},
+		Email:            user.Email,
+		UUID:             user.UUID,
+		UserID:           int(user.ID),
+		MFA:              mfa,
+		SessionID:        family_id,
+		Roles:            utils.AppRoleNames(user.Roles, app.ID),
+	}
+
+	accessString, err := utils.CreateClaimJWTToken(access_claim, 0)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	refresh_expires := session_policy.RefreshExpiry(now)
+	refreshString, err := utils.IssueRefreshTokenUntil(db, ctx, user.ID, family_id, mfa, refresh_expires)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	if err := utils.ExtendSession(db, ctx, family_id, refresh_expires); err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken:  accessString,
+		RefreshToken: refreshString,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(access_expires.Sub(now).Seconds()),
+	}, nil
+}
+
+// Refresh errors that end the session, the presented refresh token family is revoked,
+// refresh tokens presented by another client are taken as leaked
+func sessionEnded(err error) bool {
+	return errors.Is(err, utils.ErrAppInactive) || errors.Is(err, utils.ErrSessionExpired) || errors.Is(err, utils.ErrSessionNotFound) ||
+		errors.Is(err, utils.ErrSessionClient)
+}
+
 // CheckLogin is a function to checktoken Status
 // @Summary Auth
-// @Description CheckLogin
+// @Description CheckLogin, with app_uuid tokens scoped to another app are refused, so are tokens of sessions past the app's idle or absolute timeout
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param app_uuid query string false "App UUID the token must be valid for"
 // @Success 200 {object} common.ResponseHTTP{data=TokenResponse{}}
 // @Failure 404 {object} common.ResponseHTTP{}
 // @Router /checklogin [get]
 func CheckLogin(contx *fiber.Ctx) error {
 
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
 	token := contx.Get("X-APP-TOKEN")
 
 	if token == "" {
@@ -179,7 +594,7 @@ func CheckLogin(contx *fiber.Ctx) error {
 		})
 	}
 
-	claims, err := utils.ParseJWTToken(token)
+	claims, err := requestClaims(contx, token, true)
 	//  Decoding the token
 	if err != nil {
 		return contx.Status(http.StatusForbidden).JSON(common.ResponseHTTP{
@@ -188,6 +603,20 @@ func CheckLogin(contx *fiber.Ctx) error {
 			Data:    nil,
 		})
 	}
+	if app_uuid := contx.Query("app_uuid"); app_uuid != "" && !utils.AudienceAllowed(claims, app_uuid) {
+		return contx.Status(http.StatusForbidden).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "the token was issued for another app",
+			Data:    nil,
+		})
+	}
+	if err := utils.CheckSessionPolicy(db, tracer.Tracer, claims.SessionID); err != nil {
+		return contx.Status(http.StatusForbidden).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
 
 	// returning the value
 	return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
@@ -196,3 +625,68 @@ func CheckLogin(contx *fiber.Ctx) error {
 		Data:    claims,
 	})
 }
+
+// Logout Request for Endpoint
+type LogoutPost struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Logout is a function to revoke the current access token
+// @Summary Auth
+// @Description Logout, revokes the access token and the refresh token family if provided
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param logout body LogoutPost false "Logout"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /logout [post]
+func PostLogout(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	claims, err := requestClaims(contx, contx.Get("X-APP-TOKEN"), true)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// refresh token is optional
+	logout_request_data := new(LogoutPost)
+	if len(contx.Body()) > 0 {
+		if err := contx.BodyParser(&logout_request_data); err != nil {
+			return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+	}
+
+	if err := utils.RevokeToken(db, tracer.Tracer, claims); err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if logout_request_data.RefreshToken != "" {
+		utils.RevokeRefreshToken(db, tracer.Tracer, logout_request_data.RefreshToken, uint(claims.UserID))
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Logout sucessfull",
+		Data:    nil,
+	})
+}
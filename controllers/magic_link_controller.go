@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Magic link Request for Endpoint
+type MagicLinkPost struct {
+	Email   string `json:"email" validate:"required,email" example:"someone@domain.com"`
+	AppUUID string `json:"app_uuid" validate:"required"`
+}
+
+// MagicLink is a function to request a passwordless login link
+// @Summary Magic Link
+// @Description Emails a single use login link for the app when the app allows magic links and the email belongs to an active user, the answer is the same either way
+// @Description the link's token is exchanged at /login with grant_type magic_link
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param magic_link body MagicLinkPost true "Magic Link"
+// @Success 202 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /magiclink [post]
+func PostMagicLink(contx *fiber.Ctx) error {
+	//  Getting tracer context
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	magic_link_request := new(MagicLinkPost)
+	if err := contx.BodyParser(magic_link_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(magic_link_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// apps that do not allow magic links are refused, they are not secret
+	app, err := utils.MagicLinkApp(db, tracer.Tracer, magic_link_request.AppUUID)
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// failures are only logged so the answer does not reveal which emails are registered
+	var user models.User
+	if res := db.WithContext(tracer.Tracer).Model(&models.User{}).Where("email = ? AND disabled = ? AND status <> ?", magic_link_request.Email, false, models.UserStatusInvited).First(&user); res.Error == nil {
+		if retry_after, err := utils.LoginRetryAfter(db, tracer.Tracer, user.Email, contx.IP()); err != nil || retry_after > 0 {
+			fmt.Printf("magic link not sent to locked or throttled user %v\n", user.ID)
+		} else if token, err := utils.IssueMagicLinkToken(db, tracer.Tracer, user.ID, app.ID, contx.IP()); err != nil {
+			fmt.Printf("issuing magic link token failed: %v\n", err)
+		} else if err := utils.SendMagicLinkEmail(user, app, token); err != nil {
+			fmt.Printf("sending magic link email failed: %v\n", err)
+		}
+	}
+
+	return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "If the email is registered a login link has been sent.",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,420 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mitchellh/mapstructure"
+	"gorm.io/gorm"
+)
+
+// MFA code Request for Endpoint
+type MFACodePost struct {
+	Code string `json:"code" validate:"required" example:"123456"`
+}
+
+// MFA enrollment Response, the uri is shown as a qr code
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// One time recovery codes, only returned when generated
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// The user is identified by an access token or, while a policy forces enrollment during login,
+// by the mfa challenge token, allow_challenge is false for changes to an enabled mfa
+func mfaUser(contx *fiber.Ctx, db *gorm.DB, ctx context.Context, allow_challenge bool) (models.User, error) {
+	token := contx.Get("X-APP-TOKEN")
+
+	user_id := 0
+	if claims, err := requestClaims(contx, token, false); err == nil {
+		if claims.ClientID != "" {
+			return models.User{}, errors.New("a user token is required")
+		}
+		user_id = claims.UserID
+	} else if errors.Is(err, utils.ErrImpersonationRefused) {
+		return models.User{}, err
+	} else if mfa_claim, merr := utils.ParseMFAChallengeToken(token); allow_challenge && merr == nil && mfa_claim.Enrollment {
+		user_id = mfa_claim.UserID
+	} else {
+		return models.User{}, err
+	}
+
+	var user models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND disabled = ?", user_id, false).First(&user); res.Error != nil {
+		return models.User{}, errors.New("user not found or disabled")
+	}
+	return user, nil
+}
+
+// parses and validates the code body
+func mfaCode(contx *fiber.Ctx) (string, error) {
+	code_request := new(MFACodePost)
+	if err := contx.BodyParser(code_request); err != nil {
+		return "", err
+	}
+	if err := validator.New().Struct(code_request); err != nil {
+		return "", err
+	}
+	return code_request.Code, nil
+}
+
+// MFAEnroll is a function to start TOTP enrollment
+// @Summary MFA Enroll
+// @Description Generates a new TOTP secret for the user, it is only active once confirmed
+// @Tags MFA
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} common.ResponseHTTP{data=MFAEnrollResponse}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{}
+// @Router /mfa/enroll [post]
+func PostMFAEnroll(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	user, err := mfaUser(contx, db, tracer.Tracer, true)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if user.MFAEnabled {
+		return contx.Status(http.StatusConflict).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "MFA already enabled",
+			Data:    nil,
+		})
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := db.WithContext(tracer.Tracer).Model(&user).UpdateColumns(map[string]interface{}{
+		"mfa_secret":    secret,
+		"mfa_last_step": 0,
+	}).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Confirm the enrollment with a code from the authenticator",
+		Data: MFAEnrollResponse{
+			Secret:     secret,
+			OTPAuthURI: utils.TOTPURI(utils.MFAIssuer(), user.Email, secret),
+		},
+	})
+}
+
+// MFAConfirm is a function to finish TOTP enrollment
+// @Summary MFA Confirm
+// @Description Enables MFA once a code of the new secret is provided and returns the recovery codes
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param code body MFACodePost true "TOTP code"
+// @Success 200 {object} common.ResponseHTTP{data=MFARecoveryCodesResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /mfa/confirm [post]
+func PostMFAConfirm(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	user, err := mfaUser(contx, db, tracer.Tracer, true)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	code, err := mfaCode(contx)
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if user.MFAEnabled || user.MFASecret == "" {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "No pending MFA enrollment",
+			Data:    nil,
+		})
+	}
+
+	step, ok := utils.ValidateTOTP(user.MFASecret, code, time.Now().UTC(), user.MFALastStep)
+	if !ok {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: utils.ErrMFACodeInvalid.Error(),
+			Data:    nil,
+		})
+	}
+
+	recovery_codes, err := utils.GenerateRecoveryCodes(db, tracer.Tracer, user.ID)
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := db.WithContext(tracer.Tracer).Model(&user).UpdateColumns(map[string]interface{}{
+		"mfa_enabled":   true,
+		"mfa_last_step": step,
+	}).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "MFA enabled, store the recovery codes safely",
+		Data:    MFARecoveryCodesResponse{RecoveryCodes: recovery_codes},
+	})
+}
+
+// MFARecoveryCodes is a function to replace the recovery codes
+// @Summary MFA Recovery Codes
+// @Description Replaces the recovery codes, a current TOTP or recovery code is required
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param code body MFACodePost true "TOTP or recovery code"
+// @Success 200 {object} common.ResponseHTTP{data=MFARecoveryCodesResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /mfa/recoverycodes [post]
+func PostMFARecoveryCodes(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	user, err := mfaUser(contx, db, tracer.Tracer, false)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	code, err := mfaCode(contx)
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if !user.MFAEnabled {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "MFA is not enabled",
+			Data:    nil,
+		})
+	}
+	if err := utils.VerifyMFACode(db, tracer.Tracer, user, code); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	recovery_codes, err := utils.GenerateRecoveryCodes(db, tracer.Tracer, user.ID)
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Recovery codes replaced, store them safely",
+		Data:    MFARecoveryCodesResponse{RecoveryCodes: recovery_codes},
+	})
+}
+
+// MFADisable is a function to turn MFA off
+// @Summary MFA Disable
+// @Description Disables MFA for the user, a current TOTP or recovery code is required
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param code body MFACodePost true "TOTP or recovery code"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /mfa [delete]
+func DeleteMFA(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	user, err := mfaUser(contx, db, tracer.Tracer, false)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	code, err := mfaCode(contx)
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := utils.VerifyMFACode(db, tracer.Tracer, user, code); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := resetMFA(db, tracer.Tracer, user.ID); err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "MFA disabled",
+		Data:    nil,
+	})
+}
+
+// removes the secret and the recovery codes of the user
+func resetMFA(db *gorm.DB, ctx context.Context, user_id uint) error {
+	tx := db.WithContext(ctx).Begin()
+	if err := tx.Model(&models.User{}).Where("id = ?", user_id).UpdateColumns(map[string]interface{}{
+		"mfa_enabled":   false,
+		"mfa_secret":    "",
+		"mfa_last_step": 0,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("user_id = ?", user_id).Delete(&models.RecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// Reset User MFA
+// @Summary Reset User MFA
+// @Description Removes the MFA enrollment of a user who lost the authenticator, the user enrolls again on next login if a policy requires it
+// @Tags Users
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /usermfa/{user_id} [delete]
+func ResetUserMFA(contx *fiber.Ctx) error {
+	//  Getting tracer context
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// Fetching User
+	var user models.User
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", user_id).First(&user).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := resetMFA(db, tracer.Tracer, user.ID); err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var response_user models.UserGet
+	mapstructure.Decode(user, &response_user)
+	response_user.MFAEnabled = false
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Resetting User MFA.",
+		Data:    response_user,
+	})
+}
@@ -0,0 +1,502 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"blue-admin.com/common"
+	"blue-admin.com/configs"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OpenID Connect discovery document
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+// Authorization Request for the OpenID Connect code flow
+type OIDCAuthorizePost struct {
+	ClientID            string `json:"client_id" query:"client_id" validate:"required"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri" validate:"required"`
+	ResponseType        string `json:"response_type" query:"response_type" example:"code"`
+	Scope               string `json:"scope" query:"scope" example:"openid email profile"`
+	State               string `json:"state" query:"state"`
+	Nonce               string `json:"nonce" query:"nonce"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method" example:"S256"`
+}
+
+// Authorization Response, the user agent is sent to RedirectTo
+type OIDCAuthorizeResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// Token Response of the OpenID Connect token endpoint
+type OIDCTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// OAuth2 error body, standard clients expect it instead of common.ResponseHTTP
+type OAuthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// Claims returned by the userinfo endpoint
+type OIDCUserInfo struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+// errors the authorization endpoint reports back to the client through the redirect uri
+type oidcRedirectError struct {
+	code        string
+	description string
+}
+
+func (err oidcRedirectError) Error() string {
+	return err.code + ": " + err.description
+}
+
+// GetOpenIDConfiguration is a function to get the OpenID Connect discovery document
+// served at /.well-known/openid-configuration outside the api group, so it is not part of the swagger docs
+func GetOpenIDConfiguration(contx *fiber.Ctx) error {
+	issuer := utils.OIDCIssuer()
+	contx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return contx.Status(http.StatusOK).JSON(OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/api/v1/oidc/authorize",
+		TokenEndpoint:                     issuer + "/api/v1/oidc/token",
+		UserInfoEndpoint:                  issuer + "/api/v1/oidc/userinfo",
+		JwksURI:                           issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             issuer + "/api/v1/oidc/introspect",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"RS256", "EdDSA"},
+		ScopesSupported:                   []string{"openid", "email", "profile"},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "name"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+	})
+}
+
+// Checks the client and redirect uri first, errors about them must not be redirected,
+// the remaining errors are returned as oidcRedirectError
+func validateAuthorizeRequest(db *gorm.DB, contx *fiber.Ctx, authorize_request *OIDCAuthorizePost) error {
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	if err := validator.New().Struct(authorize_request); err != nil {
+		return err
+	}
+	app_client, _, err := utils.FindClient(db, tracer.Tracer, authorize_request.ClientID)
+	if err != nil {
+		return err
+	}
+	if !utils.RedirectURIAllowed(app_client, authorize_request.RedirectURI) {
+		return errors.New("redirect_uri is not registered for the client")
+	}
+
+	if authorize_request.ResponseType != "code" {
+		return oidcRedirectError{"unsupported_response_type", "only the code response type is supported"}
+	}
+	if !strings.Contains(" "+authorize_request.Scope+" ", " openid ") {
+		return oidcRedirectError{"invalid_scope", "the openid scope is required"}
+	}
+	if authorize_request.CodeChallenge == "" || authorize_request.CodeChallengeMethod != "S256" {
+		return oidcRedirectError{"invalid_request", "a S256 code_challenge is required"}
+	}
+	return nil
+}
+
+// Appends the values to the query of the redirect uri
+func oidcRedirectURI(redirect_uri string, values map[string]string) string {
+	target, err := url.Parse(redirect_uri)
+	if err != nil {
+		return redirect_uri
+	}
+	query := target.Query()
+	for key, value := range values {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// OIDCAuthorize is a function to start the OpenID Connect code flow
+// @Summary OpenID Connect Authorize
+// @Description Validates the authorization request and sends the user agent to the login page (OIDC_LOGIN_URL) with the same query
+// @Tags OpenID Connect
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Redirect URI"
+// @Param response_type query string true "code"
+// @Param scope query string true "openid email profile"
+// @Param state query string false "State"
+// @Param nonce query string false "Nonce"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "S256"
+// @Success 302
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /oidc/authorize [get]
+func GetOIDCAuthorize(contx *fiber.Ctx) error {
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	authorize_request := new(OIDCAuthorizePost)
+	if err := contx.QueryParser(authorize_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := validateAuthorizeRequest(db, contx, authorize_request); err != nil {
+		var redirect_error oidcRedirectError
+		if errors.As(err, &redirect_error) {
+			return contx.Redirect(oidcRedirectURI(authorize_request.RedirectURI, map[string]string{
+				"error":             redirect_error.code,
+				"error_description": redirect_error.description,
+				"state":             authorize_request.State,
+			}), http.StatusFound)
+		}
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	login_url := configs.AppConfig.GetOrDefault("OIDC_LOGIN_URL", "/admin/login")
+	return contx.Redirect(login_url+"?"+string(contx.Request().URI().QueryString()), http.StatusFound)
+}
+
+// OIDCAuthorize is a function to grant an authorization code to a client
+// @Summary OpenID Connect Authorize
+// @Description Called by the login page with the user's token once the user is logged in, returns the redirect uri carrying the code
+// @Tags OpenID Connect
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param authorize body OIDCAuthorizePost true "Authorization request"
+// @Success 200 {object} common.ResponseHTTP{data=OIDCAuthorizeResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /oidc/authorize [post]
+func PostOIDCAuthorize(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// impersonation tokens and tokens scoped to another app can not sign in to other apps
+	claims, err := requestClaims(contx, contx.Get("X-APP-TOKEN"), false)
+	if err != nil || claims.ClientID != "" || !utils.AudienceAllowed(claims, utils.BlueAdminAudience()) {
+		message := "A user token is required"
+		if err != nil {
+			message = err.Error()
+		}
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: message,
+			Data:    nil,
+		})
+	}
+
+	authorize_request := new(OIDCAuthorizePost)
+	if err := contx.BodyParser(authorize_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := validateAuthorizeRequest(db, contx, authorize_request); err != nil {
+		var redirect_error oidcRedirectError
+		if errors.As(err, &redirect_error) {
+			return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: redirect_error.Error(),
+				Data: OIDCAuthorizeResponse{RedirectTo: oidcRedirectURI(authorize_request.RedirectURI, map[string]string{
+					"error":             redirect_error.code,
+					"error_description": redirect_error.description,
+					"state":             authorize_request.State,
+				})},
+			})
+		}
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	auth_time := time.Now().UTC()
+	if claims.IssuedAt != nil {
+		auth_time = claims.IssuedAt.Time
+	}
+	code, err := utils.IssueAuthorizationCode(db, tracer.Tracer, models.AuthorizationCode{
+		ClientID:      authorize_request.ClientID,
+		UserID:        uint(claims.UserID),
+		RedirectURI:   authorize_request.RedirectURI,
+		Scope:         authorize_request.Scope,
+		Nonce:         authorize_request.Nonce,
+		CodeChallenge: authorize_request.CodeChallenge,
+		AuthTime:      auth_time,
+		MFA:           claims.MFA,
+	})
+	if err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Authorization code granted",
+		Data: OIDCAuthorizeResponse{RedirectTo: oidcRedirectURI(authorize_request.RedirectURI, map[string]string{
+			"code":  code,
+			"state": authorize_request.State,
+		})},
+	})
+}
+
+// Client credentials from the basic authorization header or the form body
+func oidcClientCredentials(contx *fiber.Ctx) (string, string) {
+	authorization := contx.Get(fiber.HeaderAuthorization)
+	if strings.HasPrefix(authorization, "Basic ") {
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authorization, "Basic ")); err == nil {
+			if client_id, client_secret, found := strings.Cut(string(decoded), ":"); found {
+				client_id, _ = url.QueryUnescape(client_id)
+				client_secret, _ = url.QueryUnescape(client_secret)
+				return client_id, client_secret
+			}
+		}
+	}
+	return contx.FormValue("client_id"), contx.FormValue("client_secret")
+}
+
+func oauthError(contx *fiber.Ctx, status int, code string, description string) error {
+	return contx.Status(status).JSON(OAuthError{Error: code, ErrorDescription: description})
+}
+
+// OIDCToken is a function to exchange an authorization code or refresh token
+// @Summary OpenID Connect Token
+// @Description Exchanges an authorization code (with its PKCE code_verifier) or a refresh token, errors use the OAuth2 error format
+// @Tags OpenID Connect
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code or refresh_token"
+// @Param code formData string false "Authorization code"
+// @Param redirect_uri formData string false "Redirect URI"
+// @Param code_verifier formData string false "PKCE code verifier"
+// @Param refresh_token formData string false "Refresh token"
+// @Param client_id formData string false "Client ID, unless sent with basic auth"
+// @Param client_secret formData string false "Client secret, unless sent with basic auth"
+// @Success 200 {object} OIDCTokenResponse
+// @Failure 400 {object} OAuthError
+// @Failure 401 {object} OAuthError
+// @Router /oidc/token [post]
+func PostOIDCToken(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// token responses must not be cached
+	contx.Set(fiber.HeaderCacheControl, "no-store")
+
+	// confidential clients always authenticate with their secret, clients registered as public
+	// have none and rely on PKCE, their refresh tokens are bound to the session of the code flow
+	client_id, client_secret := oidcClientCredentials(contx)
+	app_client, _, err := utils.FindClient(db, tracer.Tracer, client_id)
+	if err != nil || ((client_secret != "" || !app_client.Public) && !utils.ClientSecretMatch(app_client, client_secret)) {
+		return oauthError(contx, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+	}
+
+	switch contx.FormValue("grant_type") {
+	case "authorization_code":
+		authorization_code, err := utils.ConsumeAuthorizationCode(db, tracer.Tracer, contx.FormValue("code"), app_client.ClientID, contx.FormValue("redirect_uri"), contx.FormValue("code_verifier"))
+		if err != nil {
+			return oauthError(contx, http.StatusBadRequest, "invalid_grant", err.Error())
+		}
+
+		var user models.User
+		if res := db.WithContext(tracer.Tracer).Model(&models.User{}).Preload(clause.Associations).Where("id = ? AND disabled = ?", authorization_code.UserID, false).First(&user); res.Error != nil {
+			return oauthError(contx, http.StatusBadRequest, "invalid_grant", "user not found or disabled")
+		}
+
+		data, err := issueSessionTokens(contx, db, tracer.Tracer, user, &app_client.AppID, app_client.ClientID, authorization_code.MFA)
+		if err != nil {
+			return oauthError(contx, http.StatusInternalServerError, "server_error", err.Error())
+		}
+		id_token, err := utils.CreateIDToken(user, app_client.ClientID, authorization_code.Nonce, authorization_code.AuthTime, 60)
+		if err != nil {
+			return oauthError(contx, http.StatusInternalServerError, "server_error", err.Error())
+		}
+
+		return contx.Status(http.StatusOK).JSON(OIDCTokenResponse{
+			AccessToken:  data.AccessToken,
+			TokenType:    data.TokenType,
+			ExpiresIn:    data.ExpiresIn,
+			RefreshToken: data.RefreshToken,
+			IDToken:      id_token,
+			Scope:        authorization_code.Scope,
+		})
+	case "refresh_token":
+		refresh_token, err := utils.ConsumeRefreshToken(db, tracer.Tracer, contx.FormValue("refresh_token"))
+		if err != nil {
+			return oauthError(contx, http.StatusBadRequest, "invalid_grant", err.Error())
+		}
+
+		var user models.User
+		if res := db.WithContext(tracer.Tracer).Model(&models.User{}).Preload(clause.Associations).Where("id = ? AND disabled = ?", refresh_token.UserID, false).First(&user); res.Error != nil {
+			utils.RevokeRefreshFamily(db, tracer.Tracer, refresh_token.FamilyID)
+			return oauthError(contx, http.StatusBadRequest, "invalid_grant", "user not found or disabled")
+		}
+
+		// refresh tokens of another client or of a direct login end their session
+		data, err := issueTokenPair(db, tracer.Tracer, user, refresh_token.FamilyID, app_client.ClientID, refresh_token.MFA)
+		if sessionEnded(err) {
+			utils.RevokeRefreshFamily(db, tracer.Tracer, refresh_token.FamilyID)
+			return oauthError(contx, http.StatusBadRequest, "invalid_grant", err.Error())
+		} else if err != nil {
+			return oauthError(contx, http.StatusInternalServerError, "server_error", err.Error())
+		}
+		utils.TouchSession(db, tracer.Tracer, refresh_token.FamilyID)
+		return contx.Status(http.StatusOK).JSON(OIDCTokenResponse{
+			AccessToken:  data.AccessToken,
+			TokenType:    data.TokenType,
+			ExpiresIn:    data.ExpiresIn,
+			RefreshToken: data.RefreshToken,
+		})
+	default:
+		return oauthError(contx, http.StatusBadRequest, "unsupported_grant_type", "grant type is not supported")
+	}
+}
+
+// OIDCUserInfo is a function to get the claims of the token's user
+// @Summary OpenID Connect UserInfo
+// @Description Returns the claims of the user the bearer access token was issued to
+// @Tags OpenID Connect
+// @Produce json
+// @Param Authorization header string true "Bearer access token"
+// @Success 200 {object} OIDCUserInfo
+// @Failure 401 {object} OAuthError
+// @Router /oidc/userinfo [get]
+func GetOIDCUserInfo(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	access_token, found := strings.CutPrefix(contx.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !found {
+		contx.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return oauthError(contx, http.StatusUnauthorized, "invalid_token", "a bearer access token is required")
+	}
+
+	claims, err := requestClaims(contx, access_token, true)
+	if err != nil || claims.ClientID != "" {
+		contx.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return oauthError(contx, http.StatusUnauthorized, "invalid_token", "the access token is not valid for a user")
+	}
+
+	var user models.User
+	if res := db.WithContext(tracer.Tracer).Model(&models.User{}).Where("id = ? AND disabled = ?", claims.UserID, false).First(&user); res.Error != nil {
+		contx.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return oauthError(contx, http.StatusUnauthorized, "invalid_token", "user not found or disabled")
+	}
+
+	return contx.Status(http.StatusOK).JSON(OIDCUserInfo{
+		Subject: user.UUID,
+		Email:   user.Email,
+		Name:    user.Name,
+	})
+}
+
+// OIDCIntrospect is a function to report the state of a token to a resource server
+// @Summary OAuth2 Token Introspection
+// @Description RFC 7662 introspection for authenticated app clients, revoked or expired tokens, disabled users or clients and tokens for another audience are reported with only active false
+// @Tags OpenID Connect
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token, api key or refresh token"
+// @Param token_type_hint formData string false "access_token or refresh_token, the kind is told from the token itself"
+// @Param client_id formData string false "Client ID, unless sent with basic auth"
+// @Param client_secret formData string false "Client secret, unless sent with basic auth"
+// @Success 200 {object} utils.TokenIntrospection
+// @Failure 400 {object} OAuthError
+// @Failure 401 {object} OAuthError
+// @Router /oidc/introspect [post]
+func PostOIDCIntrospect(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	contx.Set(fiber.HeaderCacheControl, "no-store")
+
+	// only confidential clients may introspect
+	client_id, client_secret := oidcClientCredentials(contx)
+	_, app, _, err := utils.AuthenticateClient(db, tracer.Tracer, client_id, client_secret)
+	if err != nil {
+		contx.Set(fiber.HeaderWWWAuthenticate, "Basic")
+		return oauthError(contx, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+	}
+
+	token := contx.FormValue("token")
+	if token == "" {
+		return oauthError(contx, http.StatusBadRequest, "invalid_request", "the token parameter is required")
+	}
+
+	return contx.Status(http.StatusOK).JSON(utils.IntrospectToken(db, tracer.Tracer, app, token))
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/passwords"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Get Global Password Policy
+// @Summary Get Password Policy
+// @Description Global password policy read from the PASSWORD_* settings, app policies can only tighten it
+// @Tags Password Policies
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=passwords.Policy}
+// @Router /passwordpolicy [get]
+func GetPasswordPolicy(contx *fiber.Ctx) error {
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get password policy.",
+		Data:    passwords.GlobalPolicy(),
+	})
+}
+
+// Get App Password Policy
+// @Summary Get App Password Policy
+// @Description Password policy of the app
+// @Tags Password Policies
+// @Security ApiKeyAuth
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.PasswordPolicy}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /apppasswordpolicy/{app_id} [get]
+func GetAppPasswordPolicy(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var password_policy models.PasswordPolicy
+	if res := db.WithContext(tracer.Tracer).Model(&models.PasswordPolicy{}).Where("app_id = ?", app_id).First(&password_policy); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: "App has no password policy, the global policy applies",
+				Data:    nil,
+			})
+		}
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get app password policy.",
+		Data:    password_policy,
+	})
+}
+
+// Put App Password Policy
+// @Summary Put App Password Policy
+// @Description Creates or replaces the password policy of the app, it applies to users holding a role of the app
+// @Tags Password Policies
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Param policy body models.PasswordPolicyPut true "Password Policy"
+// @Success 200 {object} common.ResponseHTTP{data=models.PasswordPolicy}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /apppasswordpolicy/{app_id} [put]
+func PutAppPasswordPolicy(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	posted_policy := new(models.PasswordPolicyPut)
+	if err := contx.BodyParser(posted_policy); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(posted_policy); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var app models.App
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", app_id).First(&app).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	password_policy := models.PasswordPolicy{
+		AppID:         app.ID,
+		MinLength:     posted_policy.MinLength,
+		RequireUpper:  posted_policy.RequireUpper,
+		RequireLower:  posted_policy.RequireLower,
+		RequireDigit:  posted_policy.RequireDigit,
+		RequireSymbol: posted_policy.RequireSymbol,
+		DenyCommon:    posted_policy.DenyCommon,
+		HistoryCount:  posted_policy.HistoryCount,
+		MaxAgeDays:    posted_policy.MaxAgeDays,
+		UpdatedAt:     time.Now().UTC(),
+	}
+	if err := db.WithContext(tracer.Tracer).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "app_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"min_length", "require_upper", "require_lower", "require_digit", "require_symbol",
+			"deny_common", "history_count", "max_age_days", "updated_at",
+		}),
+	}).Create(&password_policy).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	db.WithContext(tracer.Tracer).Model(&models.PasswordPolicy{}).Where("app_id = ?", app.ID).First(&password_policy)
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Updating App Password Policy.",
+		Data:    password_policy,
+	})
+}
+
+// Delete App Password Policy
+// @Summary Delete App Password Policy
+// @Description Removes the password policy of the app, its users fall back to the global policy
+// @Tags Password Policies
+// @Security ApiKeyAuth
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /apppasswordpolicy/{app_id} [delete]
+func DeleteAppPasswordPolicy(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	res := db.WithContext(tracer.Tracer).Where("app_id = ?", app_id).Delete(&models.PasswordPolicy{})
+	if res.Error != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+	if res.RowsAffected == 0 {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "App has no password policy",
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Deleting App Password Policy.",
+		Data:    nil,
+	})
+}
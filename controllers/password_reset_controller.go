@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/passwords"
+	"blue-admin.com/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Forgot password Request for Endpoint
+type PasswordForgotPost struct {
+	Email string `json:"email" validate:"required,email" example:"someone@domain.com"`
+}
+
+// Password reset Request for Endpoint, the token comes from the emailed link or a forced change at login
+type PasswordResetPost struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Forced password change Response, the reset_token is sent back with the new password
+type PasswordChangeResponse struct {
+	ResetToken string `json:"reset_token"`
+}
+
+// 400 answer listing the broken policy rules, other errors are server errors
+func passwordPolicyFailed(contx *fiber.Ctx, err error) error {
+	var policy_err *passwords.PolicyError
+	if errors.As(err, &policy_err) {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "Password does not meet the policy",
+			Data:    policy_err.Violations,
+		})
+	}
+	return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+		Success: false,
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
+
+// PasswordForgot is a function to request a password reset link
+// @Summary Forgot Password
+// @Description Emails a single use reset link when the email belongs to an active local user, the answer is the same either way
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param email body PasswordForgotPost true "Email"
+// @Success 202 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /password/forgot [post]
+func PostPasswordForgot(contx *fiber.Ctx) error {
+	//  Getting tracer context
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	forgot_request := new(PasswordForgotPost)
+	if err := contx.BodyParser(forgot_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(forgot_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// failures are only logged so the answer does not reveal which emails are registered
+	var user models.User
+	if res := db.WithContext(tracer.Tracer).Model(&models.User{}).Where("email = ? AND disabled = ? AND status <> ? AND auth_source = ?", forgot_request.Email, false, models.UserStatusInvited, models.AuthSourceLocal).First(&user); res.Error == nil {
+		if token, err := utils.IssuePasswordResetToken(db, tracer.Tracer, user.ID); err != nil {
+			fmt.Printf("issuing password reset token failed: %v\n", err)
+		} else if err := utils.SendPasswordResetEmail(user, token); err != nil {
+			fmt.Printf("sending password reset email failed: %v\n", err)
+		}
+	}
+
+	return contx.Status(http.StatusAccepted).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "If the email is registered a reset link has been sent.",
+		Data:    nil,
+	})
+}
+
+// PasswordReset is a function to set a new password with a reset token
+// @Summary Reset Password
+// @Description Sets a new password with a single use reset token, every session of the user is signed out
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param reset body PasswordResetPost true "Reset"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{data=[]passwords.Violation}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /password/reset [post]
+func PostPasswordReset(contx *fiber.Ctx) error {
+	//  Getting tracer context
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	reset_request := new(PasswordResetPost)
+	if err := contx.BodyParser(reset_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(reset_request); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	reset_token, err := utils.FindPasswordResetToken(db, tracer.Tracer, reset_request.Token)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var user models.User
+	if res := db.WithContext(tracer.Tracer).Model(&models.User{}).Where("id = ? AND disabled = ?", reset_token.UserID, false).First(&user); res.Error != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "User not found or disabled",
+			Data:    nil,
+		})
+	}
+
+	// checked before using up the token so a rejected password can be corrected with the same link
+	if _, err := utils.CheckUserPassword(db, tracer.Tracer, user, reset_request.Password); err != nil {
+		return passwordPolicyFailed(contx, err)
+	}
+	if _, err := utils.ConsumePasswordResetToken(db, tracer.Tracer, reset_request.Token); err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := utils.SetUserPassword(db, tracer.Tracer, user, reset_request.Password); err != nil {
+		return passwordPolicyFailed(contx, err)
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Resetting Password.",
+		Data:    nil,
+	})
+}
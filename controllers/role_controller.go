@@ -7,6 +7,7 @@ import (
 	"blue-admin.com/common"
 	"blue-admin.com/models"
 	"blue-admin.com/observe"
+	"blue-admin.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mitchellh/mapstructure"
@@ -220,6 +221,7 @@ func PostRole(contx *fiber.Ctx) error {
 
 	// close transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return data if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -308,6 +310,8 @@ func PatchRole(contx *fiber.Ctx) error {
 		})
 	}
 
+	utils.InvalidatePermissions(db, tracer.Tracer)
+
 	// Return  success response
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
 		Success: true,
@@ -378,6 +382,7 @@ func DeleteRole(contx *fiber.Ctx) error {
 
 	// Commit the transaction
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// Return success respons
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -605,7 +610,7 @@ func AddFeatureRoles(contx *fiber.Ctx) error {
 	// startng update transaction
 
 	tx := db.WithContext(tracer.Tracer).Begin()
-	//  Adding one to many Relation
+	//  Adding many to many Relation
 	if err := db.WithContext(tracer.Tracer).Model(&role).Association("Features").Append(&feature); err != nil {
 		tx.Rollback()
 		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
@@ -615,6 +620,7 @@ func AddFeatureRoles(contx *fiber.Ctx) error {
 		})
 	}
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return value if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -686,6 +692,7 @@ func DeleteFeatureRoles(contx *fiber.Ctx) error {
 		})
 	}
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	// return value if transaction is sucessfull
 	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
@@ -695,6 +702,280 @@ func DeleteFeatureRoles(contx *fiber.Ctx) error {
 	})
 }
 
+// Get Feature Grants
+// @Summary Get Feature Grants
+// @Description Roles the feature is granted to with the condition of each grant
+// @Tags Roles
+// @Security ApiKeyAuth
+// @Produce json
+// @Param feature_id path int true "Feature ID"
+// @Success 200 {object} common.ResponseHTTP{data=[]models.FeatureRole}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /featurerole/{feature_id} [get]
+func GetFeatureRoles(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	feature_id, err := strconv.Atoi(contx.Params("feature_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var feature_roles []models.FeatureRole
+	if res := db.WithContext(tracer.Tracer).Model(&models.FeatureRole{}).Where("feature_id = ?", feature_id).Order("role_id").Find(&feature_roles); res.Error != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get feature grants.",
+		Data:    feature_roles,
+	})
+}
+
+// Put Feature Grant Condition
+// @Summary Put Feature Grant Condition
+// @Description Sets the condition requests must meet to use the feature through the role, an empty condition allows every request. Conditions combine ip, time, weekday, mfa and user attribute predicates, see utils.ParseCondition
+// @Tags Roles
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param feature_id path int true "Feature ID"
+// @Param role_id query int true "Role ID"
+// @Param condition body models.FeatureRoleCondition true "Condition"
+// @Success 200 {object} common.ResponseHTTP{data=models.FeatureRole}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /featurerole/{feature_id} [put]
+func PutFeatureRoleCondition(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	feature_id, err := strconv.Atoi(contx.Params("feature_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	role_id, _ := strconv.Atoi(contx.Query("role_id"))
+
+	posted_condition := new(models.FeatureRoleCondition)
+	if err := contx.BodyParser(posted_condition); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// broken conditions are rejected here so they can not lock the role out
+	condition, err := utils.ParseCondition(posted_condition.Condition)
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var feature_role models.FeatureRole
+	if res := db.WithContext(tracer.Tracer).Model(&models.FeatureRole{}).Where("feature_id = ? AND role_id = ?", feature_id, role_id).First(&feature_role); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "the feature is not granted to the role",
+			Data:    nil,
+		})
+	}
+
+	feature_role.Condition = condition.String()
+	if res := db.WithContext(tracer.Tracer).Model(&models.FeatureRole{}).
+		Where("feature_id = ? AND role_id = ?", feature_id, role_id).
+		Update("grant_condition", feature_role.Condition); res.Error != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+	utils.InvalidatePermissions(db, tracer.Tracer)
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Updating the Feature Grant Condition.",
+		Data:    feature_role,
+	})
+}
+
+// Add Role Parent
+// @Summary Add Parent Role
+// @Description Makes the role inherit the permissions of a parent role of the same app, a parent already inheriting the role is rejected
+// @Tags Roles
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param role_id path int true "Role ID"
+// @Param parent_id query int true "Parent Role ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.RoleGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /roleparent/{role_id} [patch]
+func AddRoleParents(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// connect
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	role_id, err := strconv.Atoi(contx.Params("role_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var role models.Role
+	if res := db.WithContext(tracer.Tracer).Model(&models.Role{}).Where("id = ?", role_id).First(&role); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	// fetching the parent role to be inherited
+	parent_id, _ := strconv.Atoi(contx.Query("parent_id"))
+	var parent models.Role
+	if res := db.WithContext(tracer.Tracer).Model(&models.Role{}).Where("id = ?", parent_id).First(&parent); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	// the check and the insert run in one transaction so concurrent changes can not close a cycle
+	tx := db.WithContext(tracer.Tracer).Begin()
+	if err := utils.CheckRoleParent(tx, tracer.Tracer, role, parent); err != nil {
+		tx.Rollback()
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := tx.Model(&role).Association("Parents").Append(&parent); err != nil {
+		tx.Rollback()
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "Error Adding Record",
+			Data:    err.Error(),
+		})
+	}
+	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
+
+	// return value if transaction is sucessfull
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Adding a Parent to Role.",
+		Data:    role,
+	})
+}
+
+// Delete Role Parent
+// @Summary Delete Parent Role
+// @Description Stops the role inheriting the permissions of the parent role
+// @Tags Roles
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param role_id path int true "Role ID"
+// @Param parent_id query int true "Parent Role ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.RoleGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /roleparent/{role_id} [delete]
+func DeleteRoleParents(contx *fiber.Ctx) error {
+
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	role_id, err := strconv.Atoi(contx.Params("role_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var role models.Role
+	if res := db.WithContext(tracer.Tracer).Model(&models.Role{}).Where("id = ?", role_id).First(&role); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	// fetching the parent role to be removed
+	parent_id, _ := strconv.Atoi(contx.Query("parent_id"))
+	var parent models.Role
+	if res := db.WithContext(tracer.Tracer).Model(&models.Role{}).Where("id = ?", parent_id).First(&parent); res.Error != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: res.Error.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := db.WithContext(tracer.Tracer).Model(&role).Association("Parents").Delete(&parent); err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "Error Removing Record",
+			Data:    err.Error(),
+		})
+	}
+	utils.InvalidatePermissions(db, tracer.Tracer)
+
+	// return value if transaction is sucessfull
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Removing a Parent From Role.",
+		Data:    role,
+	})
+}
+
 // Activate/Deactivate Role to data
 // @Summary Activate/Deactivate
 // @Description Activate/Deactivate
@@ -739,6 +1020,7 @@ func ActivateDeactivateRoles(contx *fiber.Ctx) error {
 	}
 	db.WithContext(tracer.Tracer).Model(&role).Update("active", active)
 	tx.Commit()
+	utils.InvalidatePermissions(db, tracer.Tracer)
 
 	if role.ID != 0 {
 		role.Active = active
@@ -757,6 +1039,63 @@ func ActivateDeactivateRoles(contx *fiber.Ctx) error {
 	})
 }
 
+// Role MFA Policy
+// @Summary Role MFA Policy
+// @Description Require MFA for every user holding the role
+// @Tags Roles
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param role_id path int true "Role ID"
+// @Param required query bool true "MFA Required"
+// @Success 200 {object} common.ResponseHTTP{data=models.Role}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /rolemfa/{role_id} [put]
+func RoleMFAPolicy(contx *fiber.Ctx) error {
+	//  Getting tracer context
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	id, err := strconv.Atoi(contx.Params("role_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	//  Get qurery Parm
+	required := contx.QueryBool("required")
+
+	var role models.Role
+	if err := db.WithContext(tracer.Tracer).Where("id = ? ", id).First(&role).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "Record not Found",
+			Data:    err.Error(),
+		})
+	}
+	if err := db.WithContext(tracer.Tracer).Model(&role).Update("mfa_required", required).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	role.MFARequired = required
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Updating Role MFA Policy.",
+		Data:    role,
+	})
+}
+
 type EndpiontsRoles struct {
 	ID   uint   `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
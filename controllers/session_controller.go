@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"blue-admin.com/utils"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Claims of the access token of a route validating its own token, the route validator does not see
+// these requests so the ones made with an impersonation token are audited here, refused unless allowed
+func requestClaims(contx *fiber.Ctx, token string, allow_impersonation bool) (utils.UserClaim, error) {
+	claims, err := utils.ParseJWTToken(token)
+	if err != nil {
+		return utils.UserClaim{}, err
+	}
+	if claims.Actor != nil {
+		utils.AuditImpersonatedRequest(claims, contx.Method(), contx.OriginalURL(), contx.IP(), allow_impersonation)
+		if !allow_impersonation {
+			return utils.UserClaim{}, utils.ErrImpersonationRefused
+		}
+	}
+	return claims, nil
+}
+
+// Access token of a signed in user, client tokens, api keys, impersonation tokens and tokens of other apps are refused
+func userTokenClaims(contx *fiber.Ctx) (utils.UserClaim, error) {
+	claims, err := requestClaims(contx, contx.Get("X-APP-TOKEN"), false)
+	if err != nil {
+		return utils.UserClaim{}, err
+	}
+	if claims.ClientID != "" || claims.UserID == 0 {
+		return utils.UserClaim{}, errors.New("a user token is required")
+	}
+	if !utils.AudienceAllowed(claims, utils.BlueAdminAudience()) {
+		return utils.UserClaim{}, errors.New("the token was issued for another app")
+	}
+	return claims, nil
+}
+
+func sessionRevokeFailed(contx *fiber.Ctx, err error) error {
+	if errors.Is(err, utils.ErrSessionNotFound) {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+		Success: false,
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
+
+// Get Sessions
+// @Summary Get Sessions
+// @Description Signed in sessions of the token's user, the session of the token is marked current
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=[]models.Session}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /sessions [get]
+func GetSessions(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	claims, err := userTokenClaims(contx)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	sessions, err := utils.UserSessions(db, tracer.Tracer, uint(claims.UserID))
+	if err != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	for index := range sessions {
+		sessions[index].Current = sessions[index].SessionID == claims.SessionID
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get sessions.",
+		Data:    sessions,
+	})
+}
+
+// Delete Session
+// @Summary Delete Session
+// @Description Signs out one session of the token's user, its access and refresh tokens stop working
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /sessions/{session_id} [delete]
+func DeleteSession(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	claims, err := userTokenClaims(contx)
+	if err != nil {
+		return contx.Status(http.StatusUnauthorized).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := utils.RevokeSession(db, tracer.Tracer, uint(claims.UserID), contx.Params("session_id")); err != nil {
+		return sessionRevokeFailed(contx, err)
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Signing Out Session.",
+		Data:    nil,
+	})
+}
+
+// Get User Sessions
+// @Summary Get User Sessions
+// @Description Signed in sessions of the user with ip, user agent, app and last seen time
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=[]models.Session}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /usersessions/{user_id} [get]
+func GetUserSessions(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var user models.User
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", user_id).First(&user).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	sessions, err := utils.UserSessions(db, tracer.Tracer, user.ID)
+	if err != nil {
+		return contx.Status(http.StatusServiceUnavailable).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get user sessions.",
+		Data:    sessions,
+	})
+}
+
+// Revoke User Session
+// @Summary Revoke User Session
+// @Description Signs out one session of the user, its access and refresh tokens stop working
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /usersessions/{user_id}/{session_id} [delete]
+func RevokeUserSession(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := utils.RevokeSession(db, tracer.Tracer, uint(user_id), contx.Params("session_id")); err != nil {
+		return sessionRevokeFailed(contx, err)
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Revoking User Session.",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"blue-admin.com/common"
+	"blue-admin.com/models"
+	"blue-admin.com/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Get App Token Policy
+// @Summary Get App Token Policy
+// @Description Token lifetimes and session timeouts of logins to the app in minutes, zero means the global default
+// @Tags Token Policies
+// @Security ApiKeyAuth
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.AppTokenPolicyGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /apptokenpolicy/{app_id} [get]
+func GetAppTokenPolicy(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var app models.App
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", app_id).First(&app).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success get app token policy.",
+		Data: models.AppTokenPolicyGet{
+			AppID:       app.ID,
+			TokenPolicy: app.TokenPolicy,
+			MFARequired: app.MFARequired,
+		},
+	})
+}
+
+// Put App Token Policy
+// @Summary Put App Token Policy
+// @Description Replaces the token lifetimes, session timeouts and MFA requirement of the app, they apply to tokens issued or refreshed from now on
+// @Tags Token Policies
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param app_id path int true "App ID"
+// @Param policy body models.AppTokenPolicyPut true "Token Policy"
+// @Success 200 {object} common.ResponseHTTP{data=models.AppTokenPolicyGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /apptokenpolicy/{app_id} [put]
+func PutAppTokenPolicy(contx *fiber.Ctx) error {
+	// Starting tracer context and tracer
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	// Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	app_id, err := strconv.Atoi(contx.Params("app_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	posted_policy := new(models.AppTokenPolicyPut)
+	if err := contx.BodyParser(posted_policy); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+	if err := validator.New().Struct(posted_policy); err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var app models.App
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", app_id).First(&app).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	token_policy := models.AppTokenPolicy{
+		AccessTokenLifeTime:  posted_policy.AccessTokenLifeTime,
+		RefreshTokenLifeTime: posted_policy.RefreshTokenLifeTime,
+		SessionIdleTimeout:   posted_policy.SessionIdleTimeout,
+		SessionMaxLifeTime:   posted_policy.SessionMaxLifeTime,
+	}
+	// a map so zero values, which fall back to the global default, are written too
+	if err := db.WithContext(tracer.Tracer).Model(&app).Updates(map[string]interface{}{
+		"access_token_life_time":  token_policy.AccessTokenLifeTime,
+		"refresh_token_life_time": token_policy.RefreshTokenLifeTime,
+		"session_idle_timeout":    token_policy.SessionIdleTimeout,
+		"session_max_life_time":   token_policy.SessionMaxLifeTime,
+		"mfa_required":            posted_policy.MFARequired,
+	}).Error; err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Updating App Token Policy.",
+		Data: models.AppTokenPolicyGet{
+			AppID:       app.ID,
+			TokenPolicy: token_policy,
+			MFARequired: posted_policy.MFARequired,
+		},
+	})
+}
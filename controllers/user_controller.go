@@ -8,6 +8,7 @@ import (
 	"blue-admin.com/common"
 	"blue-admin.com/models"
 	"blue-admin.com/observe"
+	"blue-admin.com/passwords"
 	"blue-admin.com/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -368,7 +369,7 @@ func GetUserByUUID(contx *fiber.Ctx) error {
 // @Produce json
 // @Param user body models.UserPost true "Add User"
 // @Success 200 {object} common.ResponseHTTP{data=models.UserPost}
-// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{data=[]passwords.Violation}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /user [post]
 func PostUser(contx *fiber.Ctx) error {
@@ -403,12 +404,18 @@ func PostUser(contx *fiber.Ctx) error {
 		})
 	}
 
+	// the new user has no roles yet so only the global policy applies
+	if _, err := utils.CheckUserPassword(db, tracer.Tracer, models.User{}, posted_user.Password); err != nil {
+		return passwordPolicyFailed(contx, err)
+	}
+
 	//  initiate -> user
 	user := new(models.User)
 	user.Name = posted_user.Name
 	user.Email = posted_user.Email
 	user.Password = posted_user.Password
 	user.Disabled = posted_user.Disabled
+	user.Status = models.UserStatusPending
 
 	//  start transaction to database
 	tx := db.WithContext(tracer.Tracer).Begin()
@@ -422,10 +429,25 @@ func PostUser(contx *fiber.Ctx) error {
 			Data:    err,
 		})
 	}
+	if err := utils.RecordPasswordHistory(tx, tracer.Tracer, user.ID, user.Password, passwords.GlobalPolicy().HistoryCount); err != nil {
+		tx.Rollback()
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "User Creation Failed",
+			Data:    err,
+		})
+	}
 
 	// close transaction
 	tx.Commit()
 
+	// the user stays pending until the address is confirmed
+	if token, err := utils.CreateEmailVerificationToken(*user); err != nil {
+		fmt.Printf("creating email verification token failed: %v\n", err)
+	} else if err := utils.SendEmailVerification(*user, token); err != nil {
+		fmt.Printf("sending email verification failed: %v\n", err)
+	}
+
 	var user_get models.UserGet
 	mapstructure.Decode(user, &user_get)
 
@@ -1106,6 +1128,11 @@ func ActivateDeactivateUser(contx *fiber.Ctx) error {
 	db.WithContext(tracer.Tracer).Model(&user).Update("disabled", status)
 	tx.Commit()
 
+	// disabled users should not keep using already issued tokens
+	if status {
+		utils.RevokeUserTokens(db, tracer.Tracer, user.ID)
+	}
+
 	var response_user models.UserGet
 	if user.ID != 0 {
 		mapstructure.Decode(user, &response_user)
@@ -1126,6 +1153,63 @@ func ActivateDeactivateUser(contx *fiber.Ctx) error {
 	})
 }
 
+// Revoke User Sessions
+// @Summary Revoke User Sessions
+// @Description Signs out every session of the user, revoking all access and refresh tokens issued so far
+// @Tags Users
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /usersessions/{user_id} [delete]
+func RevokeUserSessions(contx *fiber.Ctx) error {
+	//  Getting tracer context
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// Fetching User
+	var user models.User
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", user_id).First(&user).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := utils.RevokeUserTokens(db, tracer.Tracer, user.ID); err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var response_user models.UserGet
+	mapstructure.Decode(user, &response_user)
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Revoking User Sessions.",
+		Data:    response_user,
+	})
+}
+
 type UserPassword struct {
 	Email    string `validate:"required" json:"email" example:"someone@domain.com"`
 	Password string `validate:"required" json:"password"`
@@ -1139,9 +1223,9 @@ type UserPassword struct {
 // @Accept json
 // @Produce json
 // @Param user body UserPassword true "Password User"
-// @Param reset query bool true "Reset Password"
+// @Param reset query bool true "Reset Password, emails a reset link and forces a change at next login instead of setting the password"
 // @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
-// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{data=[]passwords.Violation}
 // @Router /user 	[put]
 func ChangePassword(contx *fiber.Ctx) error {
 	//  Getting tracer context
@@ -1184,32 +1268,48 @@ func ChangePassword(contx *fiber.Ctx) error {
 		})
 	}
 
+	if user_q.ID == 0 {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: "Record not Found",
+			Data:    nil,
+		})
+	}
+
 	var user models.UserGet
 
 	if !reset_password {
-		tx := db.WithContext(tracer.Tracer).Begin()
-		patch_User.Password = utils.HashFunc(patch_User.Password)
-		if err := db.WithContext(tracer.Tracer).Model(&user_q).UpdateColumns(*patch_User).Error; err != nil {
-			tx.Rollback()
-			return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+		// checked against the user's policy and password history
+		if err := utils.SetUserPassword(db, tracer.Tracer, user_q, patch_User.Password); err != nil {
+			return passwordPolicyFailed(contx, err)
+		}
+	} else {
+		// no known default, the user has to choose a new password through the emailed link or at next login
+		if err := db.WithContext(tracer.Tracer).Model(&user_q).Update("password_change_required", true).Error; err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
 				Success: false,
-				Message: "Record not Found",
-				Data:    err,
+				Message: err.Error(),
+				Data:    nil,
 			})
 		}
-		tx.Commit()
-	} else {
-		tx := db.WithContext(tracer.Tracer).Begin()
-		patch_User.Password = utils.HashFunc("default@123")
-		if err := db.WithContext(tracer.Tracer).Model(&user_q).UpdateColumns(*patch_User).Error; err != nil {
-			tx.Rollback()
-			return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+		if err := utils.RevokeUserTokens(db, tracer.Tracer, user_q.ID); err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
 				Success: false,
-				Message: "Record not Found",
-				Data:    err,
+				Message: err.Error(),
+				Data:    nil,
 			})
 		}
-		tx.Commit()
+		reset_token, err := utils.IssuePasswordResetToken(db, tracer.Tracer, user_q.ID)
+		if err != nil {
+			return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+		if err := utils.SendPasswordResetEmail(user_q, reset_token); err != nil {
+			fmt.Printf("sending password reset email failed: %v\n", err)
+		}
 	}
 
 	mapstructure.Decode(user_q, &user)
@@ -1220,3 +1320,61 @@ func ChangePassword(contx *fiber.Ctx) error {
 		Data:    user,
 	})
 }
+
+// Unlock User
+// @Summary Unlock User
+// @Description Lifts the lockout set after too many failed logins and clears the failure counter of the user's email
+// @Tags Users
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /userlock/{user_id} [delete]
+func UnlockUser(contx *fiber.Ctx) error {
+	//  Getting tracer context
+	ctx := contx.Locals("tracer")
+	tracer, _ := ctx.(*observe.RouteTracer)
+
+	//  Getting Database connection
+	db, _ := contx.Locals("db").(*gorm.DB)
+
+	// validate path params
+	user_id, err := strconv.Atoi(contx.Params("user_id"))
+	if err != nil {
+		return contx.Status(http.StatusBadRequest).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// Fetching User
+	var user models.User
+	if err := db.WithContext(tracer.Tracer).Where("id = ?", user_id).First(&user).Error; err != nil {
+		return contx.Status(http.StatusNotFound).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := utils.UnlockUser(db, tracer.Tracer, user, contx.IP()); err != nil {
+		return contx.Status(http.StatusInternalServerError).JSON(common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	var response_user models.UserGet
+	mapstructure.Decode(user, &response_user)
+	response_user.LockedUntil = nil
+	return contx.Status(http.StatusOK).JSON(common.ResponseHTTP{
+		Success: true,
+		Message: "Success Unlocking User.",
+		Data:    response_user,
+	})
+}
@@ -16,6 +16,165 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/apikeys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "API keys of the token's user, the keys themselves are never returned again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Get API Keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.APIKey"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a named api key carrying some of the user's roles, it is sent in X-APP-TOKEN or X-API-KEY.\nThe key is only returned in this answer. API keys can not be used to manage api keys.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Post API Key",
+                "parameters": [
+                    {
+                        "description": "API Key",
+                        "name": "api_key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKeyPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.APIKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/apikeys/{key_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes an api key of the token's user, it stops working immediately",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Delete API Key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API Key ID",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
         "/app": {
             "get": {
                 "security": [
@@ -315,14 +474,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/appendpointuuid/{app_uuid}": {
+        "/appclient/{app_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get app endpoints by UUID",
+                "description": "Get the client_credentials clients registered for an app",
                 "consumes": [
                     "application/json"
                 ],
@@ -330,28 +489,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Endpoints"
+                    "Apps"
                 ],
-                "summary": "Get App Endpoints by UUID",
+                "summary": "Get App Clients",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "App UUID",
-                        "name": "app_uuid",
+                        "description": "App ID",
+                        "name": "app_id",
                         "in": "path",
                         "required": true
                     }
@@ -370,7 +515,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.EndpointPut"
+                                                "$ref": "#/definitions/models.AppClient"
                                             }
                                         }
                                     }
@@ -385,16 +530,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/appfeatureuuid/{app_uuid}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get app features by UUID",
+                "description": "Register a client for an app, the secret is only returned once",
                 "consumes": [
                     "application/json"
                 ],
@@ -402,30 +545,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "Apps"
                 ],
-                "summary": "Get App Features by UUID",
+                "summary": "Add App Client",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "App UUID",
-                        "name": "app_uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "Add App Client",
+                        "name": "client",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AppClientPost"
+                        }
                     }
                 ],
                 "responses": {
@@ -440,18 +578,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.FeaturePut"
-                                            }
+                                            "$ref": "#/definitions/models.AppClientSecret"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -459,14 +600,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/apppagesuuid/{app_uuid}": {
-            "get": {
+        "/appclient/{client_id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get app pages by UUID",
+                "description": "Remove app client by client ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -474,28 +615,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pages"
+                    "Apps"
                 ],
-                "summary": "Get App Pages by UUID",
+                "summary": "Remove App Client",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "App UUID",
-                        "name": "app_uuid",
+                        "description": "Client ID",
+                        "name": "client_id",
                         "in": "path",
                         "required": true
                     }
@@ -504,22 +631,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.PagePut"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
                     "404": {
@@ -529,16 +641,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/approleuser/{role_id}/{user_id}": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add Role User",
+                "description": "Update the name and redirect uris of an app client",
                 "consumes": [
                     "application/json"
                 ],
@@ -546,80 +656,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RoleUsers"
+                    "Apps"
                 ],
-                "summary": "Add User to Role",
+                "summary": "Patch App Client",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "client_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "app uuid",
-                        "name": "app_uuid",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "Patch App Client",
+                        "name": "client",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
+                            "$ref": "#/definitions/models.AppClientPost"
                         }
                     }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Delete Role User",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Roles"
-                ],
-                "summary": "Add User",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "app uuid",
-                        "name": "app_uuid",
-                        "in": "query",
-                        "required": true
-                    }
                 ],
                 "responses": {
                     "200": {
@@ -633,7 +689,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserPost"
+                                            "$ref": "#/definitions/models.AppClient"
                                         }
                                     }
                                 }
@@ -646,8 +702,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -655,14 +711,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/approleuuid/{app_uuid}": {
-            "get": {
+        "/appclientsecret/{client_id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get app roles by UUID",
+                "description": "Replace the secret of an app client, the new secret is only returned once",
                 "consumes": [
                     "application/json"
                 ],
@@ -672,26 +728,12 @@ const docTemplate = `{
                 "tags": [
                     "Apps"
                 ],
-                "summary": "Get App Roles by UUID",
+                "summary": "Rotate App Client Secret",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "App UUID",
-                        "name": "app_uuid",
+                        "description": "Client ID",
+                        "name": "client_id",
                         "in": "path",
                         "required": true
                     }
@@ -708,33 +750,42 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.RolePut"
-                                            }
+                                            "$ref": "#/definitions/models.AppClientSecret"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
                     }
                 }
             }
         },
-        "/appruid/{app_uuid}": {
-            "get": {
+        "/appclientstatus/{client_id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get app roles by UUID",
+                "description": "Activate/Deactivate App Client",
                 "consumes": [
                     "application/json"
                 ],
@@ -744,14 +795,21 @@ const docTemplate = `{
                 "tags": [
                     "Apps"
                 ],
-                "summary": "Get App Roles by UUID",
+                "summary": "Activate/Deactivate App Client",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "App UUID",
-                        "name": "app_uuid",
+                        "description": "Client ID",
+                        "name": "client_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Active",
+                        "name": "status",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -766,10 +824,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.RolePut"
-                                            }
+                                            "$ref": "#/definitions/models.AppClient"
                                         }
                                     }
                                 }
@@ -785,14 +840,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/appsdrop": {
+        "/appendpointuuid/{app_uuid}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get FeatureDropDown",
+                "description": "Get app endpoints by UUID",
                 "consumes": [
                     "application/json"
                 ],
@@ -800,9 +855,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Feature"
+                    "Endpoints"
+                ],
+                "summary": "Get App Endpoints by UUID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App UUID",
+                        "name": "app_uuid",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get FeatureDropDown",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -817,7 +895,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/controllers.FeatureDropDown"
+                                                "$ref": "#/definitions/models.EndpointPut"
                                             }
                                         }
                                     }
@@ -834,17 +912,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/appuser": {
+        "/appfeatureuuid/{app_uuid}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
-                    },
-                    {
-                        "Refresh": []
                     }
                 ],
-                "description": "Get App Users",
+                "description": "Get app features by UUID",
                 "consumes": [
                     "application/json"
                 ],
@@ -852,9 +927,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Features"
                 ],
-                "summary": "Get App Users",
+                "summary": "Get App Features by UUID",
                 "parameters": [
                     {
                         "type": "integer",
@@ -872,9 +947,9 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "app uuid",
+                        "description": "App UUID",
                         "name": "app_uuid",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -884,7 +959,7 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/common.ResponsePagination"
+                                    "$ref": "#/definitions/common.ResponseHTTP"
                                 },
                                 {
                                     "type": "object",
@@ -892,7 +967,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.UserGet"
+                                                "$ref": "#/definitions/models.FeaturePut"
                                             }
                                         }
                                     }
@@ -909,14 +984,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/appuser/{user_id}": {
-            "get": {
+        "/appmagiclink/{app_id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get App user by ID",
+                "description": "Allow or forbid passwordless logins to the app with emailed magic links",
                 "consumes": [
                     "application/json"
                 ],
@@ -924,21 +999,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Apps"
                 ],
-                "summary": "Get App User by ID",
+                "summary": "App Magic Link Policy",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "App ID",
+                        "name": "app_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "app uuid",
-                        "name": "app_uuid",
+                        "type": "boolean",
+                        "description": "Magic Link Enabled",
+                        "name": "enabled",
                         "in": "query",
                         "required": true
                     }
@@ -955,13 +1030,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserGet"
+                                            "$ref": "#/definitions/models.App"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -969,14 +1050,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/appmfa/{app_id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove App user by ID",
+                "description": "Require MFA for every user holding a role of the app",
                 "consumes": [
                     "application/json"
                 ],
@@ -984,21 +1067,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Apps"
                 ],
-                "summary": "Remove App User by ID",
+                "summary": "App MFA Policy",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "App ID",
+                        "name": "app_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "app uuid",
-                        "name": "app_uuid",
+                        "type": "boolean",
+                        "description": "MFA Required",
+                        "name": "required",
                         "in": "query",
                         "required": true
                     }
@@ -1007,17 +1090,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.App"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -1025,14 +1120,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/checklogin": {
+        "/apppagesuuid/{app_uuid}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "CheckLogin",
+                "description": "Get app pages by UUID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1040,56 +1135,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Pages"
                 ],
-                "summary": "Auth",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.TokenResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
+                "summary": "Get App Pages by UUID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    }
-                }
-            }
-        },
-        "/clientmatrix/{app_uuid}": {
-            "get": {
-                "security": [
                     {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get app endpoint role matrix by UUID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "ClientOnly"
-                ],
-                "summary": "Get App Roles Matrix by UUID",
-                "parameters": [
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "App UUID",
@@ -1110,9 +1173,9 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PagePut"
                                             }
                                         }
                                     }
@@ -1129,29 +1192,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/clientmatrixpath/{app_uuid}": {
+        "/apppasswordpolicy/{app_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get app endpoint role matrix by UUID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Password policy of the app",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ClientOnly"
+                    "Password Policies"
                 ],
-                "summary": "Get App Roles Matrix by UUID",
+                "summary": "Get App Password Policy",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "App UUID",
-                        "name": "app_uuid",
+                        "type": "integer",
+                        "description": "App ID",
+                        "name": "app_id",
                         "in": "path",
                         "required": true
                     }
@@ -1168,16 +1228,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
+                                            "$ref": "#/definitions/models.PasswordPolicy"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1185,16 +1248,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/dashboard": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get App summary by App ID",
+                "description": "Creates or replaces the password policy of the app, it applies to users holding a role of the app",
                 "consumes": [
                     "application/json"
                 ],
@@ -1202,16 +1263,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard Meta"
+                    "Password Policies"
                 ],
-                "summary": "Get Roles Grouped By APP ID",
+                "summary": "Put App Password Policy",
                 "parameters": [
                     {
-                        "type": "string",
+                        "type": "integer",
                         "description": "App ID",
                         "name": "app_id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Password Policy",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordPolicyPut"
+                        }
                     }
                 ],
                 "responses": {
@@ -1226,13 +1296,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/controllers.AppMeta"
+                                            "$ref": "#/definitions/models.PasswordPolicy"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1240,32 +1316,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/dashboardends": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get App Endpoints summary",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Removes the password policy of the app, its users fall back to the global policy",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard Meta"
+                    "Password Policies"
                 ],
-                "summary": "Get App Endpoints Grouped By Role",
+                "summary": "Delete App Password Policy",
                 "parameters": [
                     {
-                        "type": "string",
+                        "type": "integer",
                         "description": "App ID",
                         "name": "app_id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1273,19 +1344,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.AppEndpointsMeta"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
                     "404": {
@@ -1297,14 +1362,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/dashboardfeat": {
-            "get": {
+        "/approleuser/{role_id}/{user_id}": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get App Featuers summary",
+                "description": "Add Role User",
                 "consumes": [
                     "application/json"
                 ],
@@ -1312,54 +1377,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard Meta"
+                    "RoleUsers"
                 ],
-                "summary": "Get App Features Grouped By Role",
+                "summary": "Add User to Role",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "App ID",
-                        "name": "app_id",
+                        "description": "app uuid",
+                        "name": "app_uuid",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.AppFeaturesMeta"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
-            }
-        },
-        "/dashboardpages": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get App Pages summary",
+                "description": "Delete Role User",
                 "consumes": [
                     "application/json"
                 ],
@@ -1367,14 +1426,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard Meta"
+                    "Roles"
                 ],
-                "summary": "Get App Pages",
+                "summary": "Add User",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "App ID",
-                        "name": "app_id",
+                        "description": "app uuid",
+                        "name": "app_uuid",
                         "in": "query",
                         "required": true
                     }
@@ -1391,15 +1464,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/controllers.AppFeaturesMeta"
+                                            "$ref": "#/definitions/models.UserPost"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -1407,14 +1486,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/dashboardroles": {
+        "/approleuuid/{app_uuid}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get App Roles summary",
+                "description": "Get app roles by UUID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1422,16 +1501,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard Meta"
+                    "Apps"
                 ],
-                "summary": "Get App Roles",
+                "summary": "Get App Roles by UUID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "App ID",
-                        "name": "app_id",
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "size",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App UUID",
+                        "name": "app_uuid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1446,7 +1539,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/controllers.AppFeaturesMeta"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.RolePut"
+                                            }
                                         }
                                     }
                                 }
@@ -1462,14 +1558,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/dashboardrolespage": {
+        "/appruid/{app_uuid}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get Roles by page by ID",
+                "description": "Get app roles by UUID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1477,15 +1573,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard Meta"
+                    "Apps"
                 ],
-                "summary": "Get Page Roles for Secfic App by ID",
+                "summary": "Get App Roles by UUID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "App ID",
-                        "name": "app_id",
-                        "in": "query",
+                        "description": "App UUID",
+                        "name": "app_uuid",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1501,7 +1597,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.PageGet"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.RolePut"
+                                            }
                                         }
                                     }
                                 }
@@ -1517,17 +1616,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/dropappusers": {
+        "/appsdrop": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
-                    },
-                    {
-                        "Refresh": []
                     }
                 ],
-                "description": "Get Drop App Users",
+                "description": "Get FeatureDropDown",
                 "consumes": [
                     "application/json"
                 ],
@@ -1535,25 +1631,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Get App Users",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "app uuid",
-                        "name": "app_uuid",
-                        "in": "query",
-                        "required": true
-                    }
+                    "Feature"
                 ],
+                "summary": "Get FeatureDropDown",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/common.ResponsePagination"
+                                    "$ref": "#/definitions/common.ResponseHTTP"
                                 },
                                 {
                                     "type": "object",
@@ -1561,7 +1648,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.UserGet"
+                                                "$ref": "#/definitions/controllers.FeatureDropDown"
                                             }
                                         }
                                     }
@@ -1578,24 +1665,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/droproles": {
+        "/apptokenpolicy/{app_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get RoleDropDown",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Token lifetimes and session timeouts of logins to the app in minutes, zero means the global default",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Token Policies"
+                ],
+                "summary": "Get App Token Policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get RoleDropDown",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1608,16 +1701,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/controllers.RoleDropDown"
-                                            }
+                                            "$ref": "#/definitions/models.AppTokenPolicyGet"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1625,16 +1721,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/email": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Sending Email",
+                "description": "Replaces the token lifetimes, session timeouts and MFA requirement of the app, they apply to tokens issued or refreshed from now on",
                 "consumes": [
                     "application/json"
                 ],
@@ -1642,17 +1736,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Utilities"
+                    "Token Policies"
                 ],
-                "summary": "Send Email to",
+                "summary": "Put App Token Policy",
                 "parameters": [
                     {
-                        "description": "messages",
-                        "name": "User",
+                        "type": "integer",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Token Policy",
+                        "name": "policy",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/messages.EmailMessage"
+                            "$ref": "#/definitions/models.AppTokenPolicyPut"
                         }
                     }
                 ],
@@ -1668,13 +1769,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/messages.EmailMessage"
+                                            "$ref": "#/definitions/models.AppTokenPolicyGet"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1684,7 +1791,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/endpoint": {
+        "/appuser": {
             "get": {
                 "security": [
                     {
@@ -1694,7 +1801,7 @@ const docTemplate = `{
                         "Refresh": []
                     }
                 ],
-                "description": "Get Endpoints",
+                "description": "Get App Users",
                 "consumes": [
                     "application/json"
                 ],
@@ -1702,9 +1809,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Endpoints"
+                    "Users"
                 ],
-                "summary": "Get Endpoints",
+                "summary": "Get App Users",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1719,6 +1826,13 @@ const docTemplate = `{
                         "name": "size",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "app uuid",
+                        "name": "app_uuid",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1735,7 +1849,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.EndpointGet"
+                                                "$ref": "#/definitions/models.UserGet"
                                             }
                                         }
                                     }
@@ -1750,77 +1864,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Add Endpoint",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Endpoints"
-                ],
-                "summary": "Add a new Endpoint",
-                "parameters": [
-                    {
-                        "description": "Add Endpoint",
-                        "name": "endpoint",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.EndpointPost"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.EndpointPost"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    }
-                }
             }
         },
-        "/endpoint/{endpoint_id}": {
+        "/appuser/{user_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get endpoint by ID",
+                "description": "Get App user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1828,16 +1881,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Endpoints"
+                    "Users"
                 ],
-                "summary": "Get Endpoint by ID",
+                "summary": "Get App User by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Endpoint ID",
-                        "name": "endpoint_id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "app uuid",
+                        "name": "app_uuid",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1852,7 +1912,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.EndpointGet"
+                                            "$ref": "#/definitions/models.UserGet"
                                         }
                                     }
                                 }
@@ -1873,7 +1933,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove endpoint by ID",
+                "description": "Remove App user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1881,16 +1941,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Endpoints"
+                    "Users"
                 ],
-                "summary": "Remove Endpoint by ID",
+                "summary": "Remove App User by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Endpoint ID",
-                        "name": "endpoint_id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "app uuid",
+                        "name": "app_uuid",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1913,14 +1980,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Patch Endpoint",
+            }
+        },
+        "/authorize": {
+            "post": {
+                "description": "Policy decision point for app clients, decides whether the token's bearer, the subject (a user uuid) or an anonymous caller may make the request named by its method and path or endpoint name, with the same matrix, role inheritance and grant conditions as the route validator. Denials are successful responses with allowed false and the reason.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1928,25 +1992,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Endpoints"
+                    "ClientOnly"
                 ],
-                "summary": "Patch Endpoint",
+                "summary": "Authorize Request",
                 "parameters": [
                     {
-                        "description": "Patch Endpoint",
-                        "name": "endpoint",
+                        "description": "Authorization Request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.EndpointPost"
+                            "$ref": "#/definitions/controllers.AuthorizeRequest"
                         }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Endpoint ID",
-                        "name": "endpoint_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -1961,7 +2018,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.EndpointPost"
+                                            "$ref": "#/definitions/utils.AuthorizationDecision"
                                         }
                                     }
                                 }
@@ -1974,8 +2031,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -1983,14 +2046,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/endpointdrop": {
+        "/checklogin": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get EndPointDropDown",
+                "description": "CheckLogin, with app_uuid tokens scoped to another app are refused, so are tokens of sessions past the app's idle or absolute timeout",
                 "consumes": [
                     "application/json"
                 ],
@@ -1998,9 +2061,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "EndPoints"
+                    "Authentication"
+                ],
+                "summary": "Auth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App UUID the token must be valid for",
+                        "name": "app_uuid",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get EndPointDropDown",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -2013,10 +2084,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/controllers.EndPointDropDown"
-                                            }
+                                            "$ref": "#/definitions/controllers.TokenResponse"
                                         }
                                     }
                                 }
@@ -2032,14 +2100,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/endpointfeature/{endpoint_id}": {
-            "delete": {
+        "/clientmatrix/{app_uuid}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Feature Endpoint",
+                "description": "Get app endpoint role matrix by UUID, every role allowed on an endpoint is listed",
                 "consumes": [
                     "application/json"
                 ],
@@ -2047,41 +2115,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "ClientOnly"
                 ],
-                "summary": "Delete Feature Endpoint",
+                "summary": "Get App Roles Matrix by UUID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Feature ID",
-                        "name": "endpoint_id",
+                        "type": "string",
+                        "description": "App UUID",
+                        "name": "app_uuid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Endpoint ID",
-                        "name": "feature_id",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "array",
+                                                "items": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/clientmatrixpath/{app_uuid}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add Feature to Endpoint",
+                "description": "Get app endpoint role matrix by UUID, every role allowed on an endpoint is listed",
                 "consumes": [
                     "application/json"
                 ],
@@ -2089,28 +2176,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "ClientOnly"
                 ],
-                "summary": "Add Feature to Endpoint",
+                "summary": "Get App Roles Matrix by UUID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Endpoint ID",
-                        "name": "endpoint_id",
+                        "type": "string",
+                        "description": "App UUID",
+                        "name": "app_uuid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": " Feature ID",
-                        "name": "feature_id",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "array",
+                                                "items": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -2118,17 +2222,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/feature": {
+        "/dashboard": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
-                    },
-                    {
-                        "Refresh": []
                     }
                 ],
-                "description": "Get Features",
+                "description": "Get App summary by App ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2136,21 +2237,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "Dashboard Meta"
                 ],
-                "summary": "Get Features",
+                "summary": "Get Roles Grouped By APP ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size",
-                        "name": "size",
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
                         "in": "query",
                         "required": true
                     }
@@ -2161,16 +2255,13 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/common.ResponsePagination"
+                                    "$ref": "#/definitions/common.ResponseHTTP"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.FeatureGet"
-                                            }
+                                            "$ref": "#/definitions/controllers.AppMeta"
                                         }
                                     }
                                 }
@@ -2184,14 +2275,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/dashboardends": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add Feature",
+                "description": "Get App Endpoints summary",
                 "consumes": [
                     "application/json"
                 ],
@@ -2199,18 +2292,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "Dashboard Meta"
                 ],
-                "summary": "Add a new Feature",
+                "summary": "Get App Endpoints Grouped By Role",
                 "parameters": [
                     {
-                        "description": "Add Feature",
-                        "name": "feature",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.FeaturePost"
-                        }
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -2225,21 +2316,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.FeaturePost"
+                                            "$ref": "#/definitions/controllers.AppEndpointsMeta"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -2247,14 +2332,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/feature/{feature_id}": {
+        "/dashboardfeat": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get feature by ID",
+                "description": "Get App Featuers summary",
                 "consumes": [
                     "application/json"
                 ],
@@ -2262,15 +2347,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "Dashboard Meta"
                 ],
-                "summary": "Get Feature by ID",
+                "summary": "Get App Features Grouped By Role",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Feature ID",
-                        "name": "feature_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -2286,7 +2371,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.FeatureGet"
+                                            "$ref": "#/definitions/controllers.AppFeaturesMeta"
                                         }
                                     }
                                 }
@@ -2300,14 +2385,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/dashboardpages": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove feature by ID",
+                "description": "Get App Pages summary",
                 "consumes": [
                     "application/json"
                 ],
@@ -2315,15 +2402,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "Dashboard Meta"
                 ],
-                "summary": "Remove Feature by ID",
+                "summary": "Get App Pages",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Feature ID",
-                        "name": "feature_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -2331,7 +2418,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.AppFeaturesMeta"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "404": {
@@ -2339,22 +2438,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/dashboardroles": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Patch Feature",
+                "description": "Get App Roles summary",
                 "consumes": [
                     "application/json"
                 ],
@@ -2362,24 +2457,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "Dashboard Meta"
                 ],
-                "summary": "Patch Feature",
+                "summary": "Get App Roles",
                 "parameters": [
                     {
-                        "description": "Patch Feature",
-                        "name": "feature",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.FeaturePost"
-                        }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Feature ID",
-                        "name": "feature_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -2395,21 +2481,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.FeaturePost"
+                                            "$ref": "#/definitions/controllers.AppFeaturesMeta"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -2417,14 +2497,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/featuredrop": {
+        "/dashboardrolespage": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get FeatureDropDown",
+                "description": "Get Roles by page by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2432,9 +2512,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Feature"
+                    "Dashboard Meta"
+                ],
+                "summary": "Get Page Roles for Secfic App by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get FeatureDropDown",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -2447,10 +2536,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/controllers.FeatureDropDown"
-                                            }
+                                            "$ref": "#/definitions/models.PageGet"
                                         }
                                     }
                                 }
@@ -2466,100 +2552,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/featurerole/{feature_id}": {
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Delete Role Feature",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Roles"
-                ],
-                "summary": "Delete Role Feature",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "feature_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Feature ID",
-                        "name": "role_id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    }
-                }
-            },
-            "patch": {
+        "/dropappusers": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Add Role to Feature",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Roles"
-                ],
-                "summary": "Add Role to Feature",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Feature ID",
-                        "name": "feature_id",
-                        "in": "path",
-                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": " Role ID",
-                        "name": "role_id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    }
-                }
-            }
-        },
-        "/features/{feature_id}": {
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
+                        "Refresh": []
                     }
                 ],
-                "description": "Activate/Deactivate Feature",
+                "description": "Get Drop App Users",
                 "consumes": [
                     "application/json"
                 ],
@@ -2567,21 +2570,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Feature"
+                    "Users"
                 ],
-                "summary": "Activate/Deactivate Feature",
+                "summary": "Get App Users",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Feature ID",
-                        "name": "feature_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Active",
-                        "name": "active",
+                        "type": "string",
+                        "description": "app uuid",
+                        "name": "app_uuid",
                         "in": "query",
                         "required": true
                     }
@@ -2592,21 +2588,24 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                    "$ref": "#/definitions/common.ResponsePagination"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.FeaturePost"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserGet"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -2614,17 +2613,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/jwtsalt": {
+        "/droproles": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
-                    },
-                    {
-                        "Refresh": []
                     }
                 ],
-                "description": "Get JWTSalts",
+                "description": "Get RoleDropDown",
                 "consumes": [
                     "application/json"
                 ],
@@ -2632,22 +2628,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "JWTSalts"
+                    "Role"
                 ],
-                "summary": "Get JWTSalts",
+                "summary": "Get RoleDropDown",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/common.ResponsePagination"
+                                    "$ref": "#/definitions/common.ResponseHTTP"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.JWTSalt"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/controllers.RoleDropDown"
+                                            }
                                         }
                                     }
                                 }
@@ -2663,9 +2662,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/email": {
             "post": {
-                "description": "Login",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sending Email",
                 "consumes": [
                     "application/json"
                 ],
@@ -2673,17 +2677,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Utilities"
                 ],
-                "summary": "Auth",
+                "summary": "Send Email to",
                 "parameters": [
                     {
-                        "description": "Login",
-                        "name": "user",
+                        "description": "messages",
+                        "name": "User",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.LoginPost"
+                            "$ref": "#/definitions/messages.EmailMessage"
                         }
                     }
                 ],
@@ -2699,7 +2703,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/controllers.TokenResponse"
+                                            "$ref": "#/definitions/messages.EmailMessage"
                                         }
                                     }
                                 }
@@ -2711,9 +2715,49 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
+                    }
+                }
+            }
+        },
+        "/emailverification/{user_id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Emails the user a signed link confirming the address",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Send Email Verification",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -2721,7 +2765,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/page": {
+        "/endpoint": {
             "get": {
                 "security": [
                     {
@@ -2731,7 +2775,7 @@ const docTemplate = `{
                         "Refresh": []
                     }
                 ],
-                "description": "Get Pages",
+                "description": "Get Endpoints",
                 "consumes": [
                     "application/json"
                 ],
@@ -2739,9 +2783,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pages"
+                    "Endpoints"
                 ],
-                "summary": "Get Pages",
+                "summary": "Get Endpoints",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2772,7 +2816,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.PageGet"
+                                                "$ref": "#/definitions/models.EndpointGet"
                                             }
                                         }
                                     }
@@ -2794,7 +2838,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add Page",
+                "description": "Add Endpoint",
                 "consumes": [
                     "application/json"
                 ],
@@ -2802,17 +2846,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pages"
+                    "Endpoints"
                 ],
-                "summary": "Add a new Page",
+                "summary": "Add a new Endpoint",
                 "parameters": [
                     {
-                        "description": "Add Page",
-                        "name": "page",
+                        "description": "Add Endpoint",
+                        "name": "endpoint",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PagePost"
+                            "$ref": "#/definitions/models.EndpointPost"
                         }
                     }
                 ],
@@ -2828,7 +2872,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.PagePost"
+                                            "$ref": "#/definitions/models.EndpointPost"
                                         }
                                     }
                                 }
@@ -2850,14 +2894,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/page/{page_id}": {
+        "/endpoint/{endpoint_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get page by ID",
+                "description": "Get endpoint by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2865,14 +2909,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pages"
+                    "Endpoints"
                 ],
-                "summary": "Get Page by ID",
+                "summary": "Get Endpoint by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page ID",
-                        "name": "page_id",
+                        "description": "Endpoint ID",
+                        "name": "endpoint_id",
                         "in": "path",
                         "required": true
                     }
@@ -2889,7 +2933,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.PageGet"
+                                            "$ref": "#/definitions/models.EndpointGet"
                                         }
                                     }
                                 }
@@ -2910,7 +2954,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove page by ID",
+                "description": "Remove endpoint by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2918,14 +2962,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pages"
+                    "Endpoints"
                 ],
-                "summary": "Remove Page by ID",
+                "summary": "Remove Endpoint by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page ID",
-                        "name": "page_id",
+                        "description": "Endpoint ID",
+                        "name": "endpoint_id",
                         "in": "path",
                         "required": true
                     }
@@ -2957,7 +3001,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Patch Page",
+                "description": "Patch Endpoint",
                 "consumes": [
                     "application/json"
                 ],
@@ -2965,23 +3009,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pages"
+                    "Endpoints"
                 ],
-                "summary": "Patch Page",
+                "summary": "Patch Endpoint",
                 "parameters": [
                     {
-                        "description": "Patch Page",
-                        "name": "page",
+                        "description": "Patch Endpoint",
+                        "name": "endpoint",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PagePost"
+                            "$ref": "#/definitions/models.EndpointPost"
                         }
                     },
                     {
                         "type": "integer",
-                        "description": "Page ID",
-                        "name": "page_id",
+                        "description": "Endpoint ID",
+                        "name": "endpoint_id",
                         "in": "path",
                         "required": true
                     }
@@ -2998,7 +3042,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.PagePatch"
+                                            "$ref": "#/definitions/models.EndpointPost"
                                         }
                                     }
                                 }
@@ -3020,7 +3064,142 @@ const docTemplate = `{
                 }
             }
         },
-        "/role": {
+        "/endpointdrop": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get EndPointDropDown",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "EndPoints"
+                ],
+                "summary": "Get EndPointDropDown",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/controllers.EndPointDropDown"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/endpointfeature/{endpoint_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Feature Endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Features"
+                ],
+                "summary": "Delete Feature Endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feature ID",
+                        "name": "endpoint_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "feature_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add Feature to Endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Features"
+                ],
+                "summary": "Add Feature to Endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Endpoint ID",
+                        "name": "endpoint_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": " Feature ID",
+                        "name": "feature_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/feature": {
             "get": {
                 "security": [
                     {
@@ -3030,7 +3209,7 @@ const docTemplate = `{
                         "Refresh": []
                     }
                 ],
-                "description": "Get Roles",
+                "description": "Get Features",
                 "consumes": [
                     "application/json"
                 ],
@@ -3038,9 +3217,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Features"
                 ],
-                "summary": "Get Roles",
+                "summary": "Get Features",
                 "parameters": [
                     {
                         "type": "integer",
@@ -3071,7 +3250,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.RoleGet"
+                                                "$ref": "#/definitions/models.FeatureGet"
                                             }
                                         }
                                     }
@@ -3093,7 +3272,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add Role",
+                "description": "Add Feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -3101,17 +3280,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Features"
                 ],
-                "summary": "Add a new Role",
+                "summary": "Add a new Feature",
                 "parameters": [
                     {
-                        "description": "Add Role",
-                        "name": "role",
+                        "description": "Add Feature",
+                        "name": "feature",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RolePost"
+                            "$ref": "#/definitions/models.FeaturePost"
                         }
                     }
                 ],
@@ -3127,7 +3306,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.RolePost"
+                                            "$ref": "#/definitions/models.FeaturePost"
                                         }
                                     }
                                 }
@@ -3149,14 +3328,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/role/{role_id}": {
+        "/feature/{feature_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get role by ID",
+                "description": "Get feature by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -3164,14 +3343,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Features"
                 ],
-                "summary": "Get Role by ID",
+                "summary": "Get Feature by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
+                        "description": "Feature ID",
+                        "name": "feature_id",
                         "in": "path",
                         "required": true
                     }
@@ -3188,7 +3367,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.RoleGet"
+                                            "$ref": "#/definitions/models.FeatureGet"
                                         }
                                     }
                                 }
@@ -3203,13 +3382,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Activate/Deactivate",
+                "description": "Remove feature by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -3217,59 +3396,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Features"
                 ],
-                "summary": "Activate/Deactivate",
+                "summary": "Remove Feature by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
+                        "description": "Feature ID",
+                        "name": "feature_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Active",
-                        "name": "active",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.RolePost"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
             },
-            "delete": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove role by ID",
+                "description": "Patch Feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -3277,14 +3443,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Features"
                 ],
-                "summary": "Remove Role by ID",
+                "summary": "Patch Feature",
                 "parameters": [
+                    {
+                        "description": "Patch Feature",
+                        "name": "feature",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FeaturePost"
+                        }
+                    },
                     {
                         "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
+                        "description": "Feature ID",
+                        "name": "feature_id",
                         "in": "path",
                         "required": true
                     }
@@ -3293,30 +3468,44 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FeaturePost"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/featuredrop": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Patch Role",
+                "description": "Get FeatureDropDown",
                 "consumes": [
                     "application/json"
                 ],
@@ -3324,27 +3513,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
-                ],
-                "summary": "Patch Role",
-                "parameters": [
-                    {
-                        "description": "Patch Role",
-                        "name": "role",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RolePost"
-                        }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Feature"
                 ],
+                "summary": "Get FeatureDropDown",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -3357,21 +3528,18 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.RolePost"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/controllers.FeatureDropDown"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -3379,30 +3547,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/role_endpoints": {
+        "/featurerole/{feature_id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get role EndPoints by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Roles the feature is granted to with the condition of each grant",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Roles"
                 ],
-                "summary": "Get EndPoints Role by ID",
+                "summary": "Get Feature Grants",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "query",
+                        "description": "Feature ID",
+                        "name": "feature_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -3420,7 +3585,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.EndpointGet"
+                                                "$ref": "#/definitions/models.FeatureRole"
                                             }
                                         }
                                     }
@@ -3428,23 +3593,21 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
-            }
-        },
-        "/roleapp/{role_id}": {
-            "delete": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete App Role",
+                "description": "Sets the condition requests must meet to use the feature through the role, an empty condition allows every request. Conditions combine ip, time, weekday, mfa and user attribute predicates, see utils.ParseCondition",
                 "consumes": [
                     "application/json"
                 ],
@@ -3452,41 +3615,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Roles"
                 ],
-                "summary": "Delete App Role",
+                "summary": "Put Feature Grant Condition",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "App ID",
-                        "name": "role_id",
+                        "description": "Feature ID",
+                        "name": "feature_id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
                         "description": "Role ID",
-                        "name": "app_id",
+                        "name": "role_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "description": "Condition",
+                        "name": "condition",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FeatureRoleCondition"
+                        }
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FeatureRole"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add App to Role",
+                "description": "Delete Role Feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -3494,21 +3690,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Roles"
                 ],
-                "summary": "Add App to Role",
+                "summary": "Delete Role Feature",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Role ID",
-                        "name": "role_id",
+                        "name": "feature_id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": " App ID",
-                        "name": "app_id",
+                        "description": "Feature ID",
+                        "name": "role_id",
                         "in": "query",
                         "required": true
                     }
@@ -3521,16 +3717,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/rolepage/{role_id}/{page_id}": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add Role Page",
+                "description": "Add Role to Feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -3538,22 +3732,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RolePages"
+                    "Roles"
                 ],
-                "summary": "Add Page to Role",
+                "summary": "Add Role to Feature",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
+                        "description": "Feature ID",
+                        "name": "feature_id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Page ID",
-                        "name": "page_id",
-                        "in": "path",
+                        "description": " Role ID",
+                        "name": "role_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -3565,14 +3759,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/features/{feature_id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Role Page",
+                "description": "Activate/Deactivate Feature",
                 "consumes": [
                     "application/json"
                 ],
@@ -3580,22 +3776,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Feature"
                 ],
-                "summary": "Add Page",
+                "summary": "Activate/Deactivate Feature",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
+                        "description": "Feature ID",
+                        "name": "feature_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page ID",
-                        "name": "page_id",
-                        "in": "path",
+                        "type": "boolean",
+                        "description": "Active",
+                        "name": "active",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -3611,7 +3807,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.PagePost"
+                                            "$ref": "#/definitions/models.FeaturePost"
                                         }
                                     }
                                 }
@@ -3623,24 +3819,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
                     }
                 }
             }
         },
-        "/roleuser/{role_id}/{user_id}": {
+        "/impersonate/{user_id}": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add Role User",
+                "description": "Issues a short lived access token to act as the user, only for holders of the IMPERSONATION_ROLES, there is no refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -3648,59 +3838,89 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RoleUsers"
+                    "Impersonation"
                 ],
-                "summary": "Add User to Role",
+                "summary": "Impersonate User",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "integer",
                         "description": "User ID",
                         "name": "user_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "impersonation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImpersonationPost"
+                        }
                     }
                 ],
                 "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.ImpersonationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Delete Role User",
-                "consumes": [
-                    "application/json"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonations/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Impersonations started by or targeting the user, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Impersonation"
                 ],
-                "summary": "Add User",
+                "summary": "Get User Impersonations",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "integer",
                         "description": "User ID",
@@ -3721,7 +3941,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserPost"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Impersonation"
+                                            }
                                         }
                                     }
                                 }
@@ -3733,27 +3956,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
                     }
                 }
             }
         },
-        "/user": {
-            "get": {
+        "/invitation": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
-                    },
-                    {
-                        "Refresh": []
                     }
                 ],
-                "description": "Get Users",
+                "description": "Invites an email to an app with preselected roles of the app, the invitee sets a password through the emailed link",
                 "consumes": [
                     "application/json"
                 ],
@@ -3761,23 +3975,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Invitations"
                 ],
-                "summary": "Get Users",
+                "summary": "Invite User",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query",
-                        "required": true
+                        "description": "Invitation",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InvitationPost"
+                        }
                     }
                 ],
                 "responses": {
@@ -3786,62 +3995,61 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/common.ResponsePagination"
+                                    "$ref": "#/definitions/common.ResponseHTTP"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.UserGet"
-                                            }
+                                            "$ref": "#/definitions/models.Invitation"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/invitation/{app_id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Put User",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Open invitations of the app",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Invitations"
                 ],
-                "summary": "Put User",
+                "summary": "Get App Invitations",
                 "parameters": [
                     {
-                        "description": "Password User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UserPassword"
-                        }
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Reset Password",
-                        "name": "reset",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "App ID",
+                        "name": "app_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -3857,7 +4065,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserGet"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Invitation"
+                                            }
                                         }
                                     }
                                 }
@@ -3871,62 +4082,96 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/invitation/{invitation_id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add User",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Revokes an open invitation, the placeholder user is removed when no other invitation is open",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Invitations"
                 ],
-                "summary": "Add a new User",
+                "summary": "Revoke Invitation",
                 "parameters": [
                     {
-                        "description": "Add User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Invitation ID",
+                        "name": "invitation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserPost"
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
+                }
+            }
+        },
+        "/jwtsalt": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "Refresh": []
+                    }
+                ],
+                "description": "Get JWTSalts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "JWTSalts"
                 ],
+                "summary": "Get JWTSalts",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                    "$ref": "#/definitions/common.ResponsePagination"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserPost"
+                                            "$ref": "#/definitions/models.JWTSalt"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/common.ResponseHTTP"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -3934,14 +4179,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{user_id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get user by ID",
+        "/login": {
+            "post": {
+                "description": "Login with grant_type authorization_code, mfa, magic_link, refresh_token, client_credentials or token_decode\nmagic_link exchanges the token of a link from /magiclink, the session is scoped to the link's app\nauthorization_code and magic_link answer 403 with an mfa_token when a second factor is required\nand 403 with a reset_token for /password/reset when the password has to be changed",
                 "consumes": [
                     "application/json"
                 ],
@@ -3949,16 +4189,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Get User by ID",
+                "summary": "Auth",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Login",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginPost"
+                        }
                     }
                 ],
                 "responses": {
@@ -3973,7 +4215,25 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserGet"
+                                            "$ref": "#/definitions/controllers.TokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.PasswordChangeResponse"
                                         }
                                     }
                                 }
@@ -3985,16 +4245,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
+                    },
+                    "429": {
+                        "description": "too many failed attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/logout": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Activate/Deactivate User",
+                "description": "Logout, revokes the access token and the refresh token family if provided",
                 "consumes": [
                     "application/json"
                 ],
@@ -4002,26 +4276,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Activate/Deactivate User",
+                "summary": "Auth",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Logout",
+                        "name": "logout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LogoutPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/magiclink": {
+            "post": {
+                "description": "Emails a single use login link for the app when the app allows magic links and the email belongs to an active user, the answer is the same either way\nthe link's token is exchanged at /login with grant_type magic_link",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Magic Link",
+                "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Disabled",
-                        "name": "status",
-                        "in": "query",
-                        "required": true
+                        "description": "Magic Link",
+                        "name": "magic_link",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MagicLinkPost"
+                        }
                     }
                 ],
                 "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -4029,14 +4343,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/mfa": {
             "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove user by ID",
+                "description": "Disables MFA for the user, a current TOTP or recovery code is required",
                 "consumes": [
                     "application/json"
                 ],
@@ -4044,16 +4360,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "MFA"
                 ],
-                "summary": "Remove User by ID",
+                "summary": "MFA Disable",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "description": "TOTP or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MFACodePost"
+                        }
                     }
                 ],
                 "responses": {
@@ -4063,27 +4381,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/mfa/confirm": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Patch User",
+                "description": "Enables MFA once a code of the new secret is provided and returns the recovery codes",
                 "consumes": [
                     "application/json"
                 ],
@@ -4091,25 +4411,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "MFA"
                 ],
-                "summary": "Patch User",
+                "summary": "MFA Confirm",
                 "parameters": [
                     {
-                        "description": "Patch User",
-                        "name": "user",
+                        "description": "TOTP code",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserPost"
+                            "$ref": "#/definitions/controllers.MFACodePost"
                         }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -4124,7 +4437,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserPost"
+                                            "$ref": "#/definitions/controllers.MFARecoveryCodesResponse"
                                         }
                                     }
                                 }
@@ -4137,8 +4450,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -4146,56 +4459,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/userrole/{user_id}/{role_id}": {
+        "/mfa/enroll": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add User Role",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Generates a new TOTP secret for the user, it is only active once confirmed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "UserRoles"
-                ],
-                "summary": "Add Role to User",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "MFA"
                 ],
+                "summary": "MFA Enroll",
                 "responses": {
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/controllers.MFAEnrollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/mfa/recoverycodes": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete User Role",
+                "description": "Replaces the recovery codes, a current TOTP or recovery code is required",
                 "consumes": [
                     "application/json"
                 ],
@@ -4203,23 +4523,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "MFA"
                 ],
-                "summary": "Add Role",
+                "summary": "MFA Recovery Codes",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
+                        "description": "TOTP or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MFACodePost"
+                        }
                     }
                 ],
                 "responses": {
@@ -4234,7 +4549,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.RolePost"
+                                            "$ref": "#/definitions/controllers.MFARecoveryCodesResponse"
                                         }
                                     }
                                 }
@@ -4247,8 +4562,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
@@ -4256,14 +4571,88 @@ const docTemplate = `{
                 }
             }
         },
-        "/useruuid": {
+        "/oidc/authorize": {
             "get": {
+                "description": "Validates the authorization request and sends the user agent to the login page (OIDC_LOGIN_URL) with the same query",
+                "tags": [
+                    "OpenID Connect"
+                ],
+                "summary": "OpenID Connect Authorize",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Redirect URI",
+                        "name": "redirect_uri",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "code",
+                        "name": "response_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "openid email profile",
+                        "name": "scope",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nonce",
+                        "name": "nonce",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PKCE code challenge",
+                        "name": "code_challenge",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "S256",
+                        "name": "code_challenge_method",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get user by UUID",
+                "description": "Called by the login page with the user's token once the user is logged in, returns the redirect uri carrying the code",
                 "consumes": [
                     "application/json"
                 ],
@@ -4271,23 +4660,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "OpenID Connect"
                 ],
-                "summary": "Get User by UUID",
+                "summary": "OpenID Connect Authorize",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User UUID",
-                        "name": "uuid",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "App UUID",
-                        "name": "app_uuid",
-                        "in": "query",
-                        "required": true
+                        "description": "Authorization request",
+                        "name": "authorize",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OIDCAuthorizePost"
+                        }
                     }
                 ],
                 "responses": {
@@ -4302,149 +4686,3209 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserNoRlnGet"
+                                            "$ref": "#/definitions/controllers.OIDCAuthorizeResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/common.ResponseHTTP"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "common.ResponseHTTP": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "details": {
-                    "type": "string"
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
+        },
+        "/oidc/introspect": {
+            "post": {
+                "description": "RFC 7662 introspection for authenticated app clients, revoked or expired tokens, disabled users or clients and tokens for another audience are reported with only active false",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OpenID Connect"
+                ],
+                "summary": "OAuth2 Token Introspection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Access token, api key or refresh token",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "access_token or refresh_token, the kind is told from the token itself",
+                        "name": "token_type_hint",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID, unless sent with basic auth",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret, unless sent with basic auth",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.TokenIntrospection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OAuthError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OAuthError"
+                        }
+                    }
+                }
+            }
+        },
+        "/oidc/token": {
+            "post": {
+                "description": "Exchanges an authorization code (with its PKCE code_verifier) or a refresh token, errors use the OAuth2 error format",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OpenID Connect"
+                ],
+                "summary": "OpenID Connect Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "authorization_code or refresh_token",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Redirect URI",
+                        "name": "redirect_uri",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PKCE code verifier",
+                        "name": "code_verifier",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Refresh token",
+                        "name": "refresh_token",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client ID, unless sent with basic auth",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client secret, unless sent with basic auth",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OIDCTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OAuthError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OAuthError"
+                        }
+                    }
+                }
+            }
+        },
+        "/oidc/userinfo": {
+            "get": {
+                "description": "Returns the claims of the user the bearer access token was issued to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OpenID Connect"
+                ],
+                "summary": "OpenID Connect UserInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer access token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OIDCUserInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OAuthError"
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding/invitation": {
+            "post": {
+                "description": "Sets the invitee's password, grants the invited roles and marks the email verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Accept Invitation",
+                "parameters": [
+                    {
+                        "description": "Accept",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.InvitationAcceptPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/passwords.Violation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding/verify": {
+            "post": {
+                "description": "Confirms the address with the emailed link, pending users become active",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Verify Email",
+                "parameters": [
+                    {
+                        "description": "Verification",
+                        "name": "verification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.EmailVerificationPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/page": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "Refresh": []
+                    }
+                ],
+                "description": "Get Pages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Get Pages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponsePagination"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PageGet"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add Page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Add a new Page",
+                "parameters": [
+                    {
+                        "description": "Add Page",
+                        "name": "page",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PagePost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PagePost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/page/{page_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get page by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Get Page by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page ID",
+                        "name": "page_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PageGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove page by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Remove Page by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page ID",
+                        "name": "page_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Patch Page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pages"
+                ],
+                "summary": "Patch Page",
+                "parameters": [
+                    {
+                        "description": "Patch Page",
+                        "name": "page",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PagePost"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page ID",
+                        "name": "page_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PagePatch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/forgot": {
+            "post": {
+                "description": "Emails a single use reset link when the email belongs to an active local user, the answer is the same either way",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Forgot Password",
+                "parameters": [
+                    {
+                        "description": "Email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PasswordForgotPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Sets a new password with a single use reset token, every session of the user is signed out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Reset Password",
+                "parameters": [
+                    {
+                        "description": "Reset",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PasswordResetPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/passwords.Violation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/passwordpolicy": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Global password policy read from the PASSWORD_* settings, app policies can only tighten it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Password Policies"
+                ],
+                "summary": "Get Password Policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/passwords.Policy"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/role": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "Refresh": []
+                    }
+                ],
+                "description": "Get Roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get Roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponsePagination"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.RoleGet"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add Role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Add a new Role",
+                "parameters": [
+                    {
+                        "description": "Add Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RolePost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RolePost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/{role_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get role by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get Role by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RoleGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Activate/Deactivate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Activate/Deactivate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Active",
+                        "name": "active",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RolePost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove role by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Remove Role by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Patch Role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Patch Role",
+                "parameters": [
+                    {
+                        "description": "Patch Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RolePost"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RolePost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/role_endpoints": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get role EndPoints by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Get EndPoints Role by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.EndpointGet"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/roleapp/{role_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete App Role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Delete App Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "App ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "app_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add App to Role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Add App to Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": " App ID",
+                        "name": "app_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/rolemfa/{role_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Require MFA for every user holding the role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Role MFA Policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "MFA Required",
+                        "name": "required",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Role"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/rolepage/{role_id}/{page_id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add Role Page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RolePages"
+                ],
+                "summary": "Add Page to Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page ID",
+                        "name": "page_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Role Page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Add Page",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page ID",
+                        "name": "page_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PagePost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/roleparent/{role_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops the role inheriting the permissions of the parent role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Delete Parent Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Parent Role ID",
+                        "name": "parent_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RoleGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Makes the role inherit the permissions of a parent role of the same app, a parent already inheriting the role is rejected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Add Parent Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Parent Role ID",
+                        "name": "parent_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RoleGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/roleuser/{role_id}/{user_id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add Role User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RoleUsers"
+                ],
+                "summary": "Add User to Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Role User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Add User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserPost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Signed in sessions of the token's user, the session of the token is marked current",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Get Sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Session"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Signs out one session of the token's user, its access and refresh tokens stop working",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Delete Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "Refresh": []
+                    }
+                ],
+                "description": "Get Users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get Users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponsePagination"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserGet"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Put User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Put User",
+                "parameters": [
+                    {
+                        "description": "Password User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UserPassword"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reset Password, emails a reset link and forces a change at next login instead of setting the password",
+                        "name": "reset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/passwords.Violation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Add a new User",
+                "parameters": [
+                    {
+                        "description": "Add User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserPost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/passwords.Violation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get User by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Activate/Deactivate User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Activate/Deactivate User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disabled",
+                        "name": "status",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Remove User by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Patch User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Patch User",
+                "parameters": [
+                    {
+                        "description": "Patch User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPost"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserPost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/userapikeys/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "API keys of the user with their roles, expiry and last use",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Get User API Keys",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.APIKey"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/userapikeys/{user_id}/{key_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes an api key of the user, it stops working immediately",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Delete User API Key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "API Key ID",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/userlock/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lifts the lockout set after too many failed logins and clears the failure counter of the user's email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unlock User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/usermfa/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes the MFA enrollment of a user who lost the authenticator, the user enrolls again on next login if a policy requires it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Reset User MFA",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/userrole/{user_id}/{role_id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add User Role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "UserRoles"
+                ],
+                "summary": "Add Role to User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete User Role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Add Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RolePost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/usersessions/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Signed in sessions of the user with ip, user agent, app and last seen time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Get User Sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Session"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Signs out every session of the user, revoking all access and refresh tokens issued so far",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke User Sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/usersessions/{user_id}/{session_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Signs out one session of the user, its access and refresh tokens stop working",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Revoke User Session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        },
+        "/useruuid": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get user by UUID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get User by UUID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "uuid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App UUID",
+                        "name": "app_uuid",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/common.ResponseHTTP"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserNoRlnGet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/common.ResponseHTTP"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "common.ResponseHTTP": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "details": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "common.ResponsePagination": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "details": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/models.APIKey"
+                },
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.AppEndpointsMeta": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "array",
+                "items": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.AppFeaturesMeta": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "array",
+                "items": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.AppMeta": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.roles"
+                    }
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.AuthorizeRequest": {
+            "type": "object",
+            "properties": {
+                "app_uuid": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.EmailVerificationPost": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.EndPointDropDown": {
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.FeatureDropDown": {
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ImpersonationResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "impersonation": {
+                    "$ref": "#/definitions/models.Impersonation"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.InvitationAcceptPost": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.LoginPost": {
+            "type": "object",
+            "required": [
+                "grant_type"
+            ],
+            "properties": {
+                "app_uuid": {
+                    "description": "the tokens are scoped to the app, their aud is the app uuid and only its roles are included, defaults to blue-admin's app",
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 6
+                },
+                "grant_type": {
+                    "type": "string",
+                    "example": "authorization_code"
                 },
-                "success": {
-                    "type": "boolean"
+                "password": {
+                    "type": "string",
+                    "maxLength": 128
                 },
-                "total": {
-                    "type": "integer"
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "common.ResponsePagination": {
+        "controllers.LogoutPost": {
             "type": "object",
             "properties": {
-                "data": {},
-                "details": {
+                "refresh_token": {
                     "type": "string"
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "pages": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "success": {
-                    "type": "boolean"
-                },
-                "total": {
-                    "type": "integer"
                 }
             }
         },
-        "controllers.AppEndpointsMeta": {
+        "controllers.MFAChallengeResponse": {
             "type": "object",
-            "additionalProperties": {
-                "type": "array",
-                "items": {
+            "properties": {
+                "enrollment_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
                     "type": "string"
                 }
             }
         },
-        "controllers.AppFeaturesMeta": {
+        "controllers.MFACodePost": {
             "type": "object",
-            "additionalProperties": {
-                "type": "array",
-                "items": {
-                    "type": "string"
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
                 }
             }
         },
-        "controllers.AppMeta": {
+        "controllers.MFAEnrollResponse": {
             "type": "object",
             "properties": {
-                "name": {
+                "otpauth_uri": {
                     "type": "string"
                 },
-                "roles": {
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.MFARecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.roles"
+                        "type": "string"
                     }
-                },
-                "uuid": {
-                    "type": "string"
                 }
             }
         },
-        "controllers.EndPointDropDown": {
+        "controllers.MagicLinkPost": {
             "type": "object",
             "required": [
-                "id",
-                "name"
+                "app_uuid",
+                "email"
             ],
             "properties": {
-                "id": {
-                    "type": "integer"
+                "app_uuid": {
+                    "type": "string"
                 },
-                "name": {
+                "email": {
+                    "type": "string",
+                    "example": "someone@domain.com"
+                }
+            }
+        },
+        "controllers.OAuthError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "error_description": {
                     "type": "string"
                 }
             }
         },
-        "controllers.FeatureDropDown": {
+        "controllers.OIDCAuthorizePost": {
             "type": "object",
             "required": [
-                "id",
-                "name"
+                "client_id",
+                "redirect_uri"
             ],
             "properties": {
-                "id": {
+                "client_id": {
+                    "type": "string"
+                },
+                "code_challenge": {
+                    "type": "string"
+                },
+                "code_challenge_method": {
+                    "type": "string",
+                    "example": "S256"
+                },
+                "nonce": {
+                    "type": "string"
+                },
+                "redirect_uri": {
+                    "type": "string"
+                },
+                "response_type": {
+                    "type": "string",
+                    "example": "code"
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "openid email profile"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.OIDCAuthorizeResponse": {
+            "type": "object",
+            "properties": {
+                "redirect_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.OIDCTokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
                     "type": "integer"
                 },
+                "id_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.OIDCUserInfo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
+                },
+                "sub": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.LoginPost": {
+        "controllers.PasswordChangeResponse": {
+            "type": "object",
+            "properties": {
+                "reset_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.PasswordForgotPost": {
             "type": "object",
             "required": [
-                "grant_type"
+                "email"
             ],
             "properties": {
                 "email": {
                     "type": "string",
-                    "maxLength": 32,
-                    "minLength": 6
-                },
-                "grant_type": {
-                    "type": "string",
-                    "example": "authorization_code"
-                },
+                    "example": "someone@domain.com"
+                }
+            }
+        },
+        "controllers.PasswordResetPost": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
                 "password": {
                     "type": "string"
                 },
@@ -4474,90 +7918,260 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "expires_in": {
+                    "type": "integer"
+                },
                 "refresh_token": {
                     "type": "string"
                 },
-                "token_type": {
-                    "type": "string"
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UserPassword": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "someone@domain.com"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.endpoints": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "route_path": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.features": {
+            "type": "object",
+            "properties": {
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.endpoints"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.roles": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.features"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "messages.EmailMessage": {
+            "type": "object",
+            "required": [
+                "emails",
+                "message",
+                "subject"
+            ],
+            "properties": {
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKey": {
+            "description": "Personal access token of a user for scripts, carries a subset of the user's roles, only the sha256 of the key is stored",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.UserPassword": {
+        "models.APIKeyPost": {
+            "description": "APIKeyPost type information, expires_in_days defaults to API_KEY_LIFE_TIME_DAYS",
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "name",
+                "role_ids"
             ],
             "properties": {
-                "email": {
+                "expires_in_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
                     "type": "string",
-                    "example": "someone@domain.com"
+                    "maxLength": 100
                 },
-                "password": {
-                    "type": "string"
+                "role_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "controllers.endpoints": {
+        "models.App": {
+            "description": "App type information",
             "type": "object",
             "properties": {
-                "method": {
+                "active": {
+                    "type": "boolean"
+                },
+                "description": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer"
+                },
+                "magic_link_enabled": {
+                    "description": "users may sign in to the app with a link emailed to them instead of a password",
+                    "type": "boolean"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
-                "route_path": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.features": {
-            "type": "object",
-            "properties": {
-                "endpoints": {
+                "roles": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.endpoints"
+                        "$ref": "#/definitions/models.Role"
                     }
                 },
-                "name": {
+                "token_policy": {
+                    "description": "lifetimes of the tokens issued for logins to the app",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AppTokenPolicy"
+                        }
+                    ]
+                },
+                "uuid": {
                     "type": "string"
                 }
             }
         },
-        "controllers.roles": {
+        "models.AppClient": {
+            "description": "Client of an App for the client_credentials and OpenID Connect code grants, only the sha256 of the secret is stored RedirectURIs are the space separated uris allowed for the OpenID Connect code flow Public clients (browser or native apps) can not keep a secret, they use the code flow with PKCE only",
             "type": "object",
             "properties": {
-                "features": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.features"
-                    }
+                "active": {
+                    "type": "boolean"
+                },
+                "app_id": {
+                    "type": "integer"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
                 },
                 "name": {
                     "type": "string"
+                },
+                "public": {
+                    "type": "boolean"
+                },
+                "redirect_uris": {
+                    "type": "string"
                 }
             }
         },
-        "messages.EmailMessage": {
+        "models.AppClientPost": {
+            "description": "AppClientPost type information",
             "type": "object",
             "required": [
-                "emails",
-                "message",
-                "subject"
+                "name"
             ],
             "properties": {
-                "emails": {
+                "name": {
+                    "type": "string"
+                },
+                "public": {
+                    "description": "only set when the client is created, public clients get no secret",
+                    "type": "boolean"
+                },
+                "redirect_uris": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
-                },
-                "message": {
+                }
+            }
+        },
+        "models.AppClientSecret": {
+            "description": "Client credentials, the secret is only returned when created or rotated",
+            "type": "object",
+            "properties": {
+                "client_id": {
                     "type": "string"
                 },
-                "subject": {
+                "client_secret": {
                     "type": "string"
                 }
             }
@@ -4575,6 +8189,12 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "magic_link_enabled": {
+                    "type": "boolean"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -4584,6 +8204,9 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.Role"
                     }
                 },
+                "token_policy": {
+                    "$ref": "#/definitions/models.AppTokenPolicy"
+                },
                 "uuid": {
                     "type": "string"
                 }
@@ -4604,6 +8227,65 @@ const docTemplate = `{
                 }
             }
         },
+        "models.AppTokenPolicy": {
+            "description": "Token and session lifetimes in minutes of logins to the app, zero falls back to the global default",
+            "type": "object",
+            "properties": {
+                "access_token_life_time": {
+                    "type": "integer"
+                },
+                "refresh_token_life_time": {
+                    "type": "integer"
+                },
+                "session_idle_timeout": {
+                    "type": "integer"
+                },
+                "session_max_life_time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AppTokenPolicyGet": {
+            "description": "Configured token policy of the app with its MFA requirement",
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "integer"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "token_policy": {
+                    "$ref": "#/definitions/models.AppTokenPolicy"
+                }
+            }
+        },
+        "models.AppTokenPolicyPut": {
+            "description": "AppTokenPolicyPut type information, lifetimes are in minutes and zero uses the global default",
+            "type": "object",
+            "properties": {
+                "access_token_life_time": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 0
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "refresh_token_life_time": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "session_idle_timeout": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "session_max_life_time": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "models.Endpoint": {
             "description": "App type information",
             "type": "object",
@@ -4713,8 +8395,11 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "role": {
-                    "type": "number"
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
                 }
             }
         },
@@ -4725,53 +8410,193 @@ const docTemplate = `{
                 "active": {
                     "type": "boolean"
                 },
-                "description": {
-                    "type": "string"
+                "description": {
+                    "type": "string"
+                },
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Endpoint"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                }
+            }
+        },
+        "models.FeaturePost": {
+            "description": "FeaturePost type information",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeaturePut": {
+            "description": "FeaturePut type information",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeatureRole": {
+            "description": "Grant of a feature to a role, the join row of Feature.Roles with the condition requests must meet to use it",
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string"
+                },
+                "feature_id": {
+                    "type": "integer"
+                },
+                "role_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FeatureRoleCondition": {
+            "description": "Condition of a feature grant, empty to allow every request, see utils.ParseCondition",
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Impersonation": {
+            "description": "Audit record of a token issued to a privileged user to act as another user, the requests made with it are published as security events",
+            "type": "object",
+            "properties": {
+                "actor_email": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImpersonationPost": {
+            "description": "ImpersonationPost type information, the reason is kept in the audit record",
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "models.Invitation": {
+            "description": "Invitation of an email to an app with preselected roles, accepted through the emailed link",
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "app_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invited_by": {
+                    "type": "integer"
                 },
-                "endpoints": {
+                "roles": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Endpoint"
+                        "$ref": "#/definitions/models.Role"
                     }
                 },
-                "id": {
+                "user_id": {
                     "type": "integer"
-                },
-                "name": {
-                    "type": "string"
                 }
             }
         },
-        "models.FeaturePost": {
-            "description": "FeaturePost type information",
+        "models.InvitationPost": {
+            "description": "InvitationPost type information",
             "type": "object",
+            "required": [
+                "app_id",
+                "email"
+            ],
             "properties": {
-                "active": {
-                    "type": "boolean"
+                "app_id": {
+                    "type": "integer"
                 },
-                "description": {
+                "email": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
-                }
-            }
-        },
-        "models.FeaturePut": {
-            "description": "FeaturePut type information",
-            "type": "object",
-            "properties": {
-                "active": {
-                    "type": "boolean"
-                },
-                "description": {
-                    "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
+                "role_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
@@ -4886,6 +8711,80 @@ const docTemplate = `{
                 }
             }
         },
+        "models.PasswordPolicy": {
+            "description": "Password rules of an app, they tighten the global policy for users with roles of the app",
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "integer"
+                },
+                "deny_common": {
+                    "type": "boolean"
+                },
+                "history_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_age_days": {
+                    "type": "integer"
+                },
+                "min_length": {
+                    "type": "integer"
+                },
+                "require_digit": {
+                    "type": "boolean"
+                },
+                "require_lower": {
+                    "type": "boolean"
+                },
+                "require_symbol": {
+                    "type": "boolean"
+                },
+                "require_upper": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PasswordPolicyPut": {
+            "description": "PasswordPolicyPut type information",
+            "type": "object",
+            "properties": {
+                "deny_common": {
+                    "type": "boolean"
+                },
+                "history_count": {
+                    "type": "integer",
+                    "maximum": 24,
+                    "minimum": 0
+                },
+                "max_age_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_length": {
+                    "type": "integer",
+                    "maximum": 128,
+                    "minimum": 0
+                },
+                "require_digit": {
+                    "type": "boolean"
+                },
+                "require_lower": {
+                    "type": "boolean"
+                },
+                "require_symbol": {
+                    "type": "boolean"
+                },
+                "require_upper": {
+                    "type": "boolean"
+                }
+            }
+        },
         "models.Role": {
             "description": "App type information",
             "type": "object",
@@ -4908,6 +8807,9 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "mfa_required": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -4917,6 +8819,13 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.Page"
                     }
                 },
+                "parents": {
+                    "description": "roles of the same app whose permissions the role inherits",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
                 "users": {
                     "type": "array",
                     "items": {
@@ -4947,9 +8856,18 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "mfa_required": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
+                "parents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
                 "users": {
                     "type": "array",
                     "items": {
@@ -4995,10 +8913,58 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Session": {
+            "description": "A login of the user, refreshed tokens stay in the session (the refresh token family) until it expires or is revoked ClientID is the OpenID Connect client the session was started for, only that client can refresh it",
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "integer"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "mfa": {
+                    "type": "boolean"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.User": {
             "description": "App type information",
             "type": "object",
             "properties": {
+                "auth_source": {
+                    "type": "string"
+                },
                 "date_registered": {
                     "type": "string"
                 },
@@ -5008,21 +8974,40 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "email_verified_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "locked_until": {
+                    "type": "string"
+                },
+                "mfa_enabled": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
                 "password": {
                     "type": "string"
                 },
+                "password_change_required": {
+                    "description": "set by an admin reset, the next login has to choose a new password",
+                    "type": "boolean"
+                },
+                "password_changed_at": {
+                    "type": "string"
+                },
                 "roles": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Role"
                     }
                 },
+                "status": {
+                    "type": "string"
+                },
                 "uuid": {
                     "type": "string"
                 }
@@ -5032,6 +9017,9 @@ const docTemplate = `{
             "description": "UserGet type information",
             "type": "object",
             "properties": {
+                "auth_source": {
+                    "type": "string"
+                },
                 "date_registered": {
                     "type": "string"
                 },
@@ -5044,9 +9032,18 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "locked_until": {
+                    "type": "string"
+                },
+                "mfa_enabled": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
+                "password_change_required": {
+                    "type": "boolean"
+                },
                 "roles": {
                     "type": "array",
                     "items": {
@@ -5099,6 +9096,147 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "passwords.Policy": {
+            "type": "object",
+            "properties": {
+                "deny_common": {
+                    "type": "boolean"
+                },
+                "history_count": {
+                    "description": "number of previous passwords that can not be reused, 0 disables the history check",
+                    "type": "integer"
+                },
+                "max_age_days": {
+                    "description": "days after which the password has to be changed, 0 disables expiry",
+                    "type": "integer"
+                },
+                "min_length": {
+                    "type": "integer"
+                },
+                "require_digit": {
+                    "type": "boolean"
+                },
+                "require_lower": {
+                    "type": "boolean"
+                },
+                "require_symbol": {
+                    "type": "boolean"
+                },
+                "require_upper": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "passwords.Violation": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.ActorClaim": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "utils.AuthorizationDecision": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "feature": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "route_path": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.TokenIntrospection": {
+            "type": "object",
+            "properties": {
+                "act": {
+                    "$ref": "#/definitions/utils.ActorClaim"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "aud": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "iat": {
+                    "type": "integer"
+                },
+                "iss": {
+                    "type": "string"
+                },
+                "jti": {
+                    "type": "string"
+                },
+                "mfa": {
+                    "type": "boolean"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "sid": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
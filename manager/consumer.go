@@ -2,6 +2,7 @@ package manager
 
 import (
 	"blue-admin.com/messages"
+	"blue-admin.com/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +18,9 @@ var (
 )
 
 func startconsumer() {
-	messages.RabbitConsumer("esb", env)
+	messages.EmailSender = utils.SendEmailConsumer
+	// each consumer blocks, the esb one runs in the background
+	go messages.RabbitConsumer("esb", env)
 	messages.RabbitConsumer("email", env)
 }
 
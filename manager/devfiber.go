@@ -19,7 +19,6 @@ import (
 	"blue-admin.com/controllers"
 	"blue-admin.com/database"
 	_ "blue-admin.com/docs"
-	"blue-admin.com/models"
 	"blue-admin.com/observe"
 	"blue-admin.com/utils"
 	"github.com/ansrivas/fiberprometheus/v2"
@@ -35,6 +34,7 @@ import (
 	"github.com/gofiber/swagger"
 	"github.com/spf13/cobra"
 	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
 )
 
 var (
@@ -58,6 +58,16 @@ var (
 	protectedURLs = []*regexp.Regexp{
 		regexp.MustCompile("^/api/v1/login"),
 		regexp.MustCompile("^/api/v1/checklogin"),
+		regexp.MustCompile("^/api/v1/logout"),
+		regexp.MustCompile("^/api/v1/oidc"),
+		regexp.MustCompile("^/api/v1/mfa"),
+		regexp.MustCompile("^/api/v1/password/"),
+		regexp.MustCompile("^/api/v1/magiclink"),
+		regexp.MustCompile("^/api/v1/authorize"),
+		regexp.MustCompile("^/api/v1/onboarding"),
+		regexp.MustCompile("^/api/v1/sessions"),
+		regexp.MustCompile("^/api/v1/apikeys"),
+		regexp.MustCompile("^/api/v1/impersonate/"),
 		regexp.MustCompile("^/api/v1/pics"),
 		regexp.MustCompile("^/lmetrics"),
 		regexp.MustCompile("^/docs"),
@@ -92,10 +102,6 @@ func dbsessioninjection(ctx *fiber.Ctx) error {
 	return ctx.Next()
 }
 
-func NextFunc(contx *fiber.Ctx) error {
-	return nil
-}
-
 // this is path filter which wavies token requirement for provided paths
 func authFilter(c *fiber.Ctx) bool {
 	originalURL := strings.ToLower(c.OriginalURL())
@@ -109,22 +115,41 @@ func authFilter(c *fiber.Ctx) bool {
 	return false
 }
 
+// api keys may be sent in X-API-KEY instead of X-APP-TOKEN
+func apiKeyHeader(contx *fiber.Ctx) error {
+	if contx.Get("X-APP-TOKEN") == "" {
+		if api_key := contx.Get("X-API-KEY"); api_key != "" {
+			contx.Request().Header.Set("X-APP-TOKEN", api_key)
+		}
+	}
+	return contx.Next()
+}
+
+// requests are authorized by their method and path against the endpoints' route templates
+// and the conditions of the grants, see utils.ParseCondition
 func NextRoute(contx *fiber.Ctx, key string) (bool, error) {
-	contx.Next()
-	route_name := contx.Route().Name + "_" + strings.ToLower(contx.Route().Method)
+	request := utils.ConditionRequest{IP: contx.IP(), Time: time.Now()}
 
-	if key == "anonymous" && models.Endpoints_JSON[route_name] == "Anonymous" {
+	if key == "anonymous" && utils.Permissions.Allowed(contx.Method(), contx.Path(), []string{"Anonymous"}, request) {
 		return true, nil
 	} else {
 
-		//  first validating the token
-		claims, err := utils.ParseJWTToken(key)
+		//  first validating the token, expired, revoked or other apps' tokens are rejected
+		db, _ := contx.Locals("db").(*gorm.DB)
+		claims, err := utils.TokenClaims(db, contx.UserContext(), utils.BlueAdminAudience(), key)
 		if err != nil {
-			fmt.Println(err)
+			return false, err
 		}
 
-		// check if the token have the desired role for the route
-		role_test := utils.CheckValueExistsInSlice(claims.Roles, models.Endpoints_JSON[route_name])
+		// tokens of a signed out session are rejected by the parse, the last seen time is throttled
+		if db != nil {
+			utils.TouchSession(db, contx.UserContext(), claims.SessionID)
+		}
+
+		// any of the roles resolved for the token allowed on the route passes, the same as the decision api
+		request.Claims = claims
+		role_test := utils.Permissions.Allowed(contx.Method(), contx.Path(), claims.Roles, request)
+		utils.AuditImpersonatedRequest(claims, contx.Method(), contx.OriginalURL(), contx.IP(), role_test)
 		if role_test {
 			return true, nil
 		}
@@ -151,6 +176,8 @@ func fiber_run(env string) {
 
 	//  lodaing privilge data
 	utils.GetAppFeatures()
+	// reloading it when another instance or prefork child changes it
+	utils.SubscribePermissionChanges()
 	//  starting scheduler files
 	schd := bluetasks.ScheduledTasks()
 	defer schd.Stop()
@@ -293,107 +320,185 @@ func SetupRoutes(app *fiber.App) {
 	// database session injection to local context
 	app.Use(dbsessioninjection)
 
+	// public signing keys for token verification
+	app.Get("/.well-known/jwks.json", controllers.GetJWKS).Name("jwks")
+	app.Get("/.well-known/openid-configuration", controllers.GetOpenIDConfiguration).Name("openid_configuration")
+
 	// Role Middleware
-	gapp := app.Group("/api/v1", keyauth.New(keyauth.Config{
+	gapp := app.Group("/api/v1", apiKeyHeader, keyauth.New(keyauth.Config{
 		Next:      authFilter,
 		KeyLookup: "header:X-APP-TOKEN",
 		Validator: NextRoute,
 	}))
 
-	gapp.Get("/role", NextFunc).Name("get_all_roles").Get("/role", controllers.GetRoles)
-	gapp.Get("/role/:role_id", NextFunc).Name("get_one_roles").Get("/role/:role_id", controllers.GetRoleByID)
-	gapp.Post("/role", NextFunc).Name("post_role").Post("/role", controllers.PostRole)
-	gapp.Patch("/role/:role_id", NextFunc).Name("patch_role").Patch("/role/:role_id", controllers.PatchRole)
-	gapp.Delete("/role/:role_id", NextFunc).Name("delete_role").Delete("/role/:role_id", controllers.DeleteRole)
-	gapp.Get("/droproles", NextFunc).Name("drop_roles").Get("/droproles", controllers.GetDropDownRoles)
-	gapp.Put("/role/:role_id", NextFunc).Name("activate_deactivate_role").Put("/role/:role_id", controllers.ActivateDeactivateRoles)
-	gapp.Get("/role_endpoints", NextFunc).Name("roles_endpoints").Get("/role_endpoints", controllers.GetRoleEndpointsID)
-
-	gapp.Post("/userrole/:user_id/:role_id", NextFunc).Name("add_userrole").Post("/userrole/:user_id/:role_id", controllers.AddUserRoles)
-	gapp.Delete("/userrole/:user_id/:role_id", NextFunc).Name("delete_userrole").Delete("/userrole/:user_id/:role_id", controllers.DeleteUserRoles)
-	gapp.Patch("/featurerole/:feature_id", NextFunc).Name("add_featurerole").Patch("/featurerole/:feature_id", controllers.AddFeatureRoles)
-	gapp.Delete("/featurerole/:feature_id", NextFunc).Name("delete_featurerole").Delete("/featurerole/:feature_id", controllers.DeleteFeatureRoles)
-
-	gapp.Get("/app", NextFunc).Name("get_all_apps").Get("/app", controllers.GetApps)
-	gapp.Get("/app/:app_id", NextFunc).Name("get_one_apps").Get("/app/:app_id", controllers.GetAppByID)
-	gapp.Get("/appruid/:app_uuid", NextFunc).Name("get_app_roles_uuid").Get("/appruid/:app_uuid", controllers.GetAppRoleUUID)
-	gapp.Get("/approleuuid/:app_uuid", NextFunc).Name("get_app_roles_all_uuid").Get("/approleuuid/:app_uuid", controllers.GetAppRoleAllUUID)
-	gapp.Post("/app", NextFunc).Name("post_app").Post("/app", controllers.PostApp)
-	gapp.Patch("/app/:app_id", NextFunc).Name("patch_app").Patch("/app/:app_id", controllers.PatchApp)
-	gapp.Delete("/app/:app_id", NextFunc).Name("delete_app").Delete("/app/:app_id", controllers.DeleteApp).Name("delete_app")
-
-	gapp.Patch("/approle/:role_id", NextFunc).Name("add_roleapp").Patch("/approle/:role_id", controllers.AddRoleApps)
-	gapp.Delete("/approle/:role_id", NextFunc).Name("delete_roleapp").Delete("/approle/:role_id", controllers.DeleteRoleApps)
-
-	gapp.Get("/user", NextFunc).Name("get_all_users").Get("/user", controllers.GetUsers)
-	gapp.Get("/user/:user_id", NextFunc).Name("get_one_users").Get("/user/:user_id", controllers.GetUserByID)
-	gapp.Get("/useruuid", NextFunc).Name("get_one_user_uuid").Get("/useruuid", controllers.GetUserByUUID)
-	gapp.Get("/appuser", NextFunc).Name("get_appusers_uuid").Get("/appuser", controllers.GetAppUsers)
-	gapp.Get("/dropappusers", NextFunc).Name("get_app_drop_users_uuid").Get("/dropappusers", controllers.GetAppDropUsers)
-	gapp.Get("/appuser/:user_id", NextFunc).Name("get_one_appuser_by_app_id").Get("appuser/:user_id", controllers.GetAppUserByID)
-	gapp.Post("/user", NextFunc).Name("post_user").Post("/user", controllers.PostUser)
-	gapp.Patch("/user/:user_id", NextFunc).Name("patch_user").Patch("/user/:user_id", controllers.PatchUser)
-	gapp.Delete("/user/:user_id", NextFunc).Name("delete_user").Delete("/user/:user_id", controllers.DeleteUser)
-	gapp.Delete("/appuser/:user_id", NextFunc).Name("delete_app_user").Delete("/appuser/:user_id", controllers.DeleteAppUser)
-	gapp.Put("/user/:user_id", NextFunc).Name("activate_deactivate_user").Put("/user/:user_id", controllers.ActivateDeactivateUser)
-	gapp.Put("/user", NextFunc).Name("change_reset_password").Put("/user", controllers.ChangePassword)
-
-	gapp.Post("/roleuser/:role_id/:user_id", NextFunc).Name("add_roleuser").Post("/roleuser/:role_id/:user_id", controllers.AddRoleUsers)
-	gapp.Delete("/roleuser/:role_id/:user_id", NextFunc).Name("delete_roleuser").Delete("/roleuser/:role_id/:user_id", controllers.DeleteRoleUsers)
-	gapp.Post("/approleuser/:role_id/:user_id", NextFunc).Name("add_approleuser").Post("/approleuser/:role_id/:user_id", controllers.AddAppsRoleUsers)
-	gapp.Delete("/approleuser/:role_id/:user_id", NextFunc).Name("delete_approleuser").Delete("/approleuser/:role_id/:user_id", controllers.DeleteAppRoleUsers)
-
-	gapp.Get("/feature", NextFunc).Name("get_all_features").Get("/feature", controllers.GetFeatures)
-	gapp.Get("/feature/:feature_id", NextFunc).Name("get_one_features").Get("/feature/:feature_id", controllers.GetFeatureByID)
-	gapp.Post("/feature", NextFunc).Name("post_feature").Post("/feature", controllers.PostFeature)
-	gapp.Patch("/feature/:feature_id", NextFunc).Name("patch_feature").Patch("/feature/:feature_id", controllers.PatchFeature)
-	gapp.Get("/appfeatureuuid/:app_uuid", NextFunc).Name("get_app_feature_all_uuid").Get("/appfeatureuuid/:app_uuid", controllers.GetAppFeaturesAllUUID)
-	gapp.Delete("/feature/:feature_id", NextFunc).Name("delete_feature").Delete("/feature/:feature_id", controllers.DeleteFeature)
-	gapp.Put("/feature/:feature_id", NextFunc).Name("activate_deactivate_features").Put("/feature/:feature_id", controllers.ActivateDeactivateFeature)
-	gapp.Get("/featuredrop", NextFunc).Name("drop_features").Get("/featuredrop", controllers.GetDropFeatures)
-
-	gapp.Patch("/endpointfeature/:endpoint_id", NextFunc).Name("add_endpointfeature").Patch("/endpointfeature/:endpoint_id", controllers.AddEndpointFeatures)
-	gapp.Delete("/endpointfeature/:endpoint_id", NextFunc).Name("delete_endpointfeature").Delete("/endpointfeature/:endpoint_id", controllers.DeleteEndpointFeatures)
-
-	gapp.Get("/endpoint", NextFunc).Name("get_all_endpoints").Get("/endpoint", controllers.GetEndpoints)
-	gapp.Get("/endpoint/:endpoint_id", NextFunc).Name("get_one_endpoint").Get("/endpoint/:endpoint_id", controllers.GetEndpointByID)
-	gapp.Get("/appendpointuuid/:app_uuid", NextFunc).Name("get_app_endpoint_all_uuid").Get("/appendpointuuid/:app_uuid", controllers.GetAppEndpointsAllUUID)
-	gapp.Post("/endpoint", NextFunc).Name("post_endpoint").Post("/endpoint", controllers.PostEndpoint)
-	gapp.Patch("/endpoint/:endpoint_id", NextFunc).Name("patch_endpoint").Patch("/endpoint/:endpoint_id", controllers.PatchEndpoint)
-	gapp.Delete("/endpoint/:endpoint_id", NextFunc).Name("delete_endpoint").Delete("/endpoint/:endpoint_id", controllers.DeleteEndpoint).Name("delete_endpoint")
-
-	gapp.Get("/page", NextFunc).Name("get_all_pages").Get("/page", controllers.GetPages)
-	gapp.Get("/page/:page_id", NextFunc).Name("get_one_pages").Get("/page/:page_id", controllers.GetPageByID)
-	gapp.Get("/apppagesuuid/:app_uuid", NextFunc).Name("get_app_pages_all_uuid").Get("/apppagesuuid/:app_uuid", controllers.GetAppPagesAllUUID)
-	gapp.Post("/page", NextFunc).Name("post_page").Post("/page", controllers.PostPage)
-	gapp.Patch("/page/:page_id", NextFunc).Name("patch_page").Patch("/page/:page_id", controllers.PatchPage)
-	gapp.Delete("/page/:page_id", NextFunc).Name("delete_page").Delete("/page/:page_id", controllers.DeletePage).Name("delete_page")
-
-	gapp.Post("/rolepage/:role_id/:page_id", NextFunc).Name("add_rolepage").Post("/rolepage/:role_id/:page_id", controllers.AddRolePages)
-	gapp.Delete("/rolepage/:role_id/:page_id", NextFunc).Name("delete_rolepage").Delete("/rolepage/:role_id/:page_id", controllers.DeleteRolePages)
+	gapp.Get("/role", controllers.GetRoles)
+	gapp.Get("/role/:role_id", controllers.GetRoleByID)
+	gapp.Post("/role", controllers.PostRole)
+	gapp.Patch("/role/:role_id", controllers.PatchRole)
+	gapp.Delete("/role/:role_id", controllers.DeleteRole)
+	gapp.Get("/droproles", controllers.GetDropDownRoles)
+	gapp.Put("/role/:role_id", controllers.ActivateDeactivateRoles)
+	gapp.Put("/rolemfa/:role_id", controllers.RoleMFAPolicy)
+	gapp.Get("/role_endpoints", controllers.GetRoleEndpointsID)
+
+	gapp.Post("/userrole/:user_id/:role_id", controllers.AddUserRoles)
+	gapp.Delete("/userrole/:user_id/:role_id", controllers.DeleteUserRoles)
+	gapp.Patch("/featurerole/:feature_id", controllers.AddFeatureRoles)
+	gapp.Delete("/featurerole/:feature_id", controllers.DeleteFeatureRoles)
+	gapp.Get("/featurerole/:feature_id", controllers.GetFeatureRoles)
+	gapp.Put("/featurerole/:feature_id", controllers.PutFeatureRoleCondition)
+	gapp.Patch("/roleparent/:role_id", controllers.AddRoleParents)
+	gapp.Delete("/roleparent/:role_id", controllers.DeleteRoleParents)
+
+	gapp.Get("/app", controllers.GetApps)
+	gapp.Get("/app/:app_id", controllers.GetAppByID)
+	gapp.Get("/appruid/:app_uuid", controllers.GetAppRoleUUID)
+	gapp.Get("/approleuuid/:app_uuid", controllers.GetAppRoleAllUUID)
+	gapp.Post("/app", controllers.PostApp)
+	gapp.Patch("/app/:app_id", controllers.PatchApp)
+	gapp.Delete("/app/:app_id", controllers.DeleteApp)
+
+	gapp.Put("/appmfa/:app_id", controllers.AppMFAPolicy)
+	gapp.Put("/appmagiclink/:app_id", controllers.AppMagicLinkPolicy)
+	gapp.Get("/apptokenpolicy/:app_id", controllers.GetAppTokenPolicy)
+	gapp.Put("/apptokenpolicy/:app_id", controllers.PutAppTokenPolicy)
+	gapp.Get("/passwordpolicy", controllers.GetPasswordPolicy)
+	gapp.Get("/apppasswordpolicy/:app_id", controllers.GetAppPasswordPolicy)
+	gapp.Put("/apppasswordpolicy/:app_id", controllers.PutAppPasswordPolicy)
+	gapp.Delete("/apppasswordpolicy/:app_id", controllers.DeleteAppPasswordPolicy)
+	gapp.Get("/appclient/:app_id", controllers.GetAppClients)
+	gapp.Post("/appclient/:app_id", controllers.PostAppClient)
+	gapp.Patch("/appclient/:client_id", controllers.PatchAppClient)
+	gapp.Put("/appclientsecret/:client_id", controllers.RotateAppClientSecret)
+	gapp.Put("/appclientstatus/:client_id", controllers.ActivateDeactivateAppClient)
+	gapp.Delete("/appclient/:client_id", controllers.DeleteAppClient)
+
+	gapp.Patch("/approle/:role_id", controllers.AddRoleApps)
+	gapp.Delete("/approle/:role_id", controllers.DeleteRoleApps)
+
+	gapp.Get("/user", controllers.GetUsers)
+	gapp.Get("/user/:user_id", controllers.GetUserByID)
+	gapp.Get("/useruuid", controllers.GetUserByUUID)
+	gapp.Get("/appuser", controllers.GetAppUsers)
+	gapp.Get("/dropappusers", controllers.GetAppDropUsers)
+	gapp.Get("/appuser/:user_id", controllers.GetAppUserByID)
+	gapp.Post("/user", controllers.PostUser)
+	gapp.Patch("/user/:user_id", controllers.PatchUser)
+	gapp.Delete("/user/:user_id", controllers.DeleteUser)
+	gapp.Delete("/appuser/:user_id", controllers.DeleteAppUser)
+	gapp.Put("/user/:user_id", controllers.ActivateDeactivateUser)
+	gapp.Put("/user", controllers.ChangePassword)
+	gapp.Get("/usersessions/:user_id", controllers.GetUserSessions)
+	gapp.Delete("/usersessions/:user_id", controllers.RevokeUserSessions)
+	gapp.Delete("/usersessions/:user_id/:session_id", controllers.RevokeUserSession)
+	gapp.Delete("/usermfa/:user_id", controllers.ResetUserMFA)
+	gapp.Delete("/userlock/:user_id", controllers.UnlockUser)
+	gapp.Get("/userapikeys/:user_id", controllers.GetUserAPIKeys)
+	gapp.Delete("/userapikeys/:user_id/:key_id", controllers.DeleteUserAPIKey)
+	gapp.Get("/impersonations/:user_id", controllers.GetUserImpersonations)
+	gapp.Post("/emailverification/:user_id", controllers.PostEmailVerification)
+
+	gapp.Post("/invitation", controllers.PostInvitation)
+	gapp.Get("/invitation/:app_id", controllers.GetInvitations)
+	gapp.Delete("/invitation/:invitation_id", controllers.DeleteInvitation)
+
+	gapp.Post("/roleuser/:role_id/:user_id", controllers.AddRoleUsers)
+	gapp.Delete("/roleuser/:role_id/:user_id", controllers.DeleteRoleUsers)
+	gapp.Post("/approleuser/:role_id/:user_id", controllers.AddAppsRoleUsers)
+	gapp.Delete("/approleuser/:role_id/:user_id", controllers.DeleteAppRoleUsers)
+
+	gapp.Get("/feature", controllers.GetFeatures)
+	gapp.Get("/feature/:feature_id", controllers.GetFeatureByID)
+	gapp.Post("/feature", controllers.PostFeature)
+	gapp.Patch("/feature/:feature_id", controllers.PatchFeature)
+	gapp.Get("/appfeatureuuid/:app_uuid", controllers.GetAppFeaturesAllUUID)
+	gapp.Delete("/feature/:feature_id", controllers.DeleteFeature)
+	gapp.Put("/feature/:feature_id", controllers.ActivateDeactivateFeature)
+	gapp.Get("/featuredrop", controllers.GetDropFeatures)
+
+	gapp.Patch("/endpointfeature/:endpoint_id", controllers.AddEndpointFeatures)
+	gapp.Delete("/endpointfeature/:endpoint_id", controllers.DeleteEndpointFeatures)
+
+	gapp.Get("/endpoint", controllers.GetEndpoints)
+	gapp.Get("/endpoint/:endpoint_id", controllers.GetEndpointByID)
+	gapp.Get("/appendpointuuid/:app_uuid", controllers.GetAppEndpointsAllUUID)
+	gapp.Post("/endpoint", controllers.PostEndpoint)
+	gapp.Patch("/endpoint/:endpoint_id", controllers.PatchEndpoint)
+	gapp.Delete("/endpoint/:endpoint_id", controllers.DeleteEndpoint)
+
+	gapp.Get("/page", controllers.GetPages)
+	gapp.Get("/page/:page_id", controllers.GetPageByID)
+	gapp.Get("/apppagesuuid/:app_uuid", controllers.GetAppPagesAllUUID)
+	gapp.Post("/page", controllers.PostPage)
+	gapp.Patch("/page/:page_id", controllers.PatchPage)
+	gapp.Delete("/page/:page_id", controllers.DeletePage)
+
+	gapp.Post("/rolepage/:role_id/:page_id", controllers.AddRolePages)
+	gapp.Delete("/rolepage/:role_id/:page_id", controllers.DeleteRolePages)
 
 	// adding endpoints
-	gapp.Get("/checklogin", NextFunc).Name("check_login").Get("/checklogin", controllers.CheckLogin)
+	gapp.Get("/checklogin", controllers.CheckLogin)
 	gapp.Post("/login", controllers.PostLogin)
+	gapp.Post("/logout", controllers.PostLogout)
+
+	// MFA enrollment, the handlers accept an access token or an enrollment challenge token
+	gapp.Post("/mfa/enroll", controllers.PostMFAEnroll)
+	gapp.Post("/mfa/confirm", controllers.PostMFAConfirm)
+	gapp.Post("/mfa/recoverycodes", controllers.PostMFARecoveryCodes)
+	gapp.Delete("/mfa", controllers.DeleteMFA)
+
+	// Own sessions of the signed in user, the access token is checked by the handlers
+	gapp.Get("/sessions", controllers.GetSessions)
+	gapp.Delete("/sessions/:session_id", controllers.DeleteSession)
+
+	// Own api keys of the signed in user, only a user access token can manage them
+	gapp.Get("/apikeys", controllers.GetAPIKeys)
+	gapp.Post("/apikeys", controllers.PostAPIKey)
+	gapp.Delete("/apikeys/:key_id", controllers.DeleteAPIKey)
+
+	// Impersonation, the handler checks the token's user holds one of the IMPERSONATION_ROLES
+	gapp.Post("/impersonate/:user_id", controllers.PostImpersonation)
+
+	// Password reset, the reset token is checked by the handler
+	gapp.Post("/password/forgot", controllers.PostPasswordForgot)
+	gapp.Post("/password/reset", controllers.PostPasswordReset)
+
+	// Magic link request, the link's token is exchanged at /login with the magic_link grant
+	gapp.Post("/magiclink", controllers.PostMagicLink)
+
+	// Invitation acceptance and email verification, the signed link token is checked by the handlers
+	gapp.Post("/onboarding/invitation", controllers.PostInvitationAccept)
+	gapp.Post("/onboarding/verify", controllers.PostVerifyEmail)
+
+	// Policy decision point for app clients, the client credentials are checked by the handler
+	gapp.Post("/authorize", controllers.PostAuthorize)
+
+	// OpenID Connect provider, clients and tokens are checked by the handlers
+	gapp.Get("/oidc/authorize", controllers.GetOIDCAuthorize)
+	gapp.Post("/oidc/authorize", controllers.PostOIDCAuthorize)
+	gapp.Post("/oidc/token", controllers.PostOIDCToken)
+	gapp.Get("/oidc/userinfo", controllers.GetOIDCUserInfo)
+	gapp.Post("/oidc/userinfo", controllers.GetOIDCUserInfo)
+	gapp.Post("/oidc/introspect", controllers.PostOIDCIntrospect)
 
-	gapp.Get("/endpointdrop", NextFunc).Name("drop_endpoints").Get("/endpointdrop", controllers.GetDropEndPoints)
-	gapp.Get("/appsdrop", NextFunc).Name("drop_sppd").Get("/appsdrop", controllers.GetDropApps)
+	gapp.Get("/endpointdrop", controllers.GetDropEndPoints)
+	gapp.Get("/appsdrop", controllers.GetDropApps)
 
 	// adding email endpoint
-	gapp.Get("/email", NextFunc).Name("send_email").Get("/email", controllers.SendEmail).Name("send_email")
+	gapp.Get("/email", controllers.SendEmail)
 
-	gapp.Get("/jwtsalt", NextFunc).Name("get_all_jwtsalts").Get("/jwtsalt", controllers.GetJWTSalts)
+	gapp.Get("/jwtsalt", controllers.GetJWTSalts)
 
 	// Client matrix
-	gapp.Get("/clientmatrix/:app_uuid", NextFunc).Name("get_client_matrix").Get("/clientmatrix/:app_uuid", controllers.GetClientMatrix)
-	gapp.Get("/clientmatrixpath/:app_uuid", NextFunc).Name("get_client_matrix").Get("/clientmatrixpath/:app_uuid", controllers.GetClientMatrixPath)
+	gapp.Get("/clientmatrix/:app_uuid", controllers.GetClientMatrix)
+	gapp.Get("/clientmatrixpath/:app_uuid", controllers.GetClientMatrixPath)
 
 	// dashboard
-	gapp.Get("/dashboard", NextFunc).Name("dashboard_one").Get("/dashboard", controllers.GetDashBoardGrouped)
-	gapp.Get("/dashboardends", NextFunc).Name("dashboard_two").Get("/dashboardends", controllers.GetAppEndpoitnsGroupedBy)
-	gapp.Get("/dashboardfeat", NextFunc).Name("dashboard_three").Get("/dashboardfeat", controllers.GetAppFeaturesGroupedBy)
-	gapp.Get("/dashboardpages", NextFunc).Name("dashboard_four").Get("/dashboardpages", controllers.GetAppPages)
-	gapp.Get("/dashboardroles", NextFunc).Name("dashboard_five").Get("/dashboardroles", controllers.GetAppRoles)
-	gapp.Get("/dashboardrolespage", NextFunc).Name("dashboard_six").Get("/dashboardrolespage", controllers.GetAppPagesInRoles)
+	gapp.Get("/dashboard", controllers.GetDashBoardGrouped)
+	gapp.Get("/dashboardends", controllers.GetAppEndpoitnsGroupedBy)
+	gapp.Get("/dashboardfeat", controllers.GetAppFeaturesGroupedBy)
+	gapp.Get("/dashboardpages", controllers.GetAppPages)
+	gapp.Get("/dashboardroles", controllers.GetAppRoles)
+	gapp.Get("/dashboardrolespage", controllers.GetAppPagesInRoles)
 
 }
@@ -1,5 +1,7 @@
 package messages
 
+import "time"
+
 //  request object def for the consumer
 // only messages with this struct will be proccessed correctly
 // when sent to specfic queue, ( morel like interface for communtication between publisher and consumer )
@@ -16,3 +18,20 @@ type EmailMessage struct {
 	Subject string   `json:"subject" validate:"required"`
 	Message string   `json:"message" validate:"required"`
 }
+
+// security relevant events, for example account lockouts, published for other services to audit or alert on
+type SecurityEvent struct {
+	Type      string            `json:"type"`
+	UserID    uint              `json:"user_id,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	IP        string            `json:"ip,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// published on the permissions exchange after a change to apps, roles, features or endpoints,
+// every process reloads its permission matrix unless it already loaded the version
+type PermissionsChanged struct {
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -24,6 +24,7 @@ func QeueConnect(queue_name string) (*amqp.Connection, *amqp.Channel, error) {
 	connection, err := amqp.DialTLS(con_str, tlsConfig)
 	if err != nil {
 		fmt.Printf("connectin to %v failed due to : %v \n", con_str, err)
+		return nil, nil, err
 	}
 
 	// creating a channel to create a queue
@@ -32,6 +33,8 @@ func QeueConnect(queue_name string) (*amqp.Connection, *amqp.Channel, error) {
 	channel, err := connection.Channel()
 	if err != nil {
 		fmt.Printf("connectin to channel failed due to : %v\n", err)
+		connection.Close()
+		return nil, nil, err
 	}
 
 	// With the instance and declare Queues that we can
@@ -49,7 +52,45 @@ func QeueConnect(queue_name string) (*amqp.Connection, *amqp.Channel, error) {
 		connection.Close() // Close the connection if queue declaration fails
 		channel.Close()    // Close the channel
 		fmt.Printf("creating queue to %v failed due to : %v\n",con_str, err)
+		return nil, nil, err
 	}
 	return connection, channel, nil
 
 }
+
+// Connects like QeueConnect but declares a fanout exchange, every queue bound to it gets a copy of each message
+func ExchangeConnect(exchange_name string) (*amqp.Connection, *amqp.Channel, error) {
+	con_str := configs.AppConfig.Get("RABBIT_URI")
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true, // Set to false for production use
+	}
+
+	connection, err := amqp.DialTLS(con_str, tlsConfig)
+	if err != nil {
+		fmt.Printf("connectin to %v failed due to : %v \n", con_str, err)
+		return nil, nil, err
+	}
+	channel, err := connection.Channel()
+	if err != nil {
+		fmt.Printf("connectin to channel failed due to : %v\n", err)
+		connection.Close()
+		return nil, nil, err
+	}
+
+	err = channel.ExchangeDeclare(
+		exchange_name, // exchange name
+		"fanout",      // kind
+		true,          // durable
+		false,         // auto delete
+		false,         // internal
+		false,         // no wait
+		nil,           // arguments
+	)
+	if err != nil {
+		channel.Close()
+		connection.Close()
+		fmt.Printf("creating exchange %v failed due to : %v\n", exchange_name, err)
+		return nil, nil, err
+	}
+	return connection, channel, nil
+}
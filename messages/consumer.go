@@ -21,7 +21,8 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 )
 
-type sample_message struct{}
+// Delivers BULK_MAIL messages, set by the consumer command (utils.SendEmailConsumer)
+var EmailSender func(ctx context.Context, msg string, sub string, emails []string)
 
 func RabbitConsumer(queue_name string, env string) {
 
@@ -76,13 +77,16 @@ func RabbitConsumer(queue_name string, env string) {
 
 			switch msg.Type {
 			case "BULK_MAIL": // make sure provide the type in the published message so to switch
-				var message sample_message
+				var message EmailMessage
 				err := json.Unmarshal(msg.Body, &message)
 				if err != nil {
 					fmt.Println("Failed to unmarshal message:", err)
 					msg.Reject(true)
 					break
 				}
+				if EmailSender != nil {
+					EmailSender(ctx, message.Message, message.Subject, message.Emails)
+				}
 				msg.Ack(true)
 			case "REQUEST":
 				//  Parsing Request object
@@ -152,3 +156,53 @@ func RabbitConsumer(queue_name string, env string) {
 	fmt.Println("Waiting for messages...")
 	select {}
 }
+
+// Subscribes this process to the permissions exchange with its own exclusive queue and hands every
+// change to the handler, returns once the connection is lost so the caller can subscribe again
+func PermissionsSubscriber(exchange_name string, handler func(PermissionsChanged)) error {
+	connection, channel, err := ExchangeConnect(exchange_name)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	defer channel.Close()
+
+	// server named queue removed with the connection, a process only needs changes made while it runs
+	queue, err := channel.QueueDeclare(
+		"",    // queue name
+		false, // durable
+		true,  // auto delete
+		true,  // exclusive
+		false, // no wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return err
+	}
+	if err := channel.QueueBind(queue.Name, "", exchange_name, false, nil); err != nil {
+		return err
+	}
+
+	msgs, err := channel.Consume(
+		queue.Name, // queue
+		"",         // consumer
+		true,       // auto ack
+		true,       // exclusive
+		false,      // no local
+		false,      // no wait
+		nil,        // args
+	)
+	if err != nil {
+		return err
+	}
+
+	for msg := range msgs {
+		var change PermissionsChanged
+		if err := json.Unmarshal(msg.Body, &change); err != nil {
+			fmt.Println("Failed to unmarshal message:", err)
+			continue
+		}
+		handler(change)
+	}
+	return fmt.Errorf("subscription to %v closed", exchange_name)
+}
@@ -12,7 +12,10 @@ type SampleMessage struct{}
 func PublishMessageQueue(posted_message RequestObject, queue_name string) error {
 
 	//   connection and channels from rabbitmq
-	connection, channel, _ := QeueConnect(queue_name)
+	connection, channel, err := QeueConnect(queue_name)
+	if err != nil {
+		return err
+	}
 	defer connection.Close()
 	defer channel.Close()
 
@@ -42,7 +45,10 @@ func PublishMessageQueue(posted_message RequestObject, queue_name string) error
 func PublishEmailQueue(posted_message EmailMessage, queue_name string) error {
 
 	//   connection and channels from rabbitmq
-	connection, channel, _ := QeueConnect(queue_name)
+	connection, channel, err := QeueConnect(queue_name)
+	if err != nil {
+		return err
+	}
 	defer connection.Close()
 	defer channel.Close()
 
@@ -68,3 +74,68 @@ func PublishEmailQueue(posted_message EmailMessage, queue_name string) error {
 	}
 	return nil
 }
+
+func PublishEventQueue(posted_event SecurityEvent, queue_name string) error {
+
+	//   connection and channels from rabbitmq
+	connection, channel, err := QeueConnect(queue_name)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	defer channel.Close()
+
+	// Create a message to publish.
+	event_message, _ := json.Marshal(posted_event)
+	message := amqp.Publishing{
+		ContentType: "application/json",
+		Body:        []byte(event_message),
+		Type:        "SECURITY_EVENT",
+	}
+
+	//send to rabbit app module qeue using channel
+	// Attempt to publish a message to the queue.
+	if err := channel.Publish(
+		"",         // exchange
+		queue_name, // queue name
+		false,      // mandatory
+		false,      // immediate
+		message,    // message to publish
+	); err != nil {
+		fmt.Println(err.Error())
+		return err
+	}
+	return nil
+}
+
+func PublishPermissionsChanged(posted_change PermissionsChanged, exchange_name string) error {
+
+	//   connection and channels from rabbitmq
+	connection, channel, err := ExchangeConnect(exchange_name)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	defer channel.Close()
+
+	// Create a message to publish.
+	change_message, _ := json.Marshal(posted_change)
+	message := amqp.Publishing{
+		ContentType: "application/json",
+		Body:        []byte(change_message),
+		Type:        "PERMISSIONS_CHANGED",
+	}
+
+	// broadcast to every process subscribed to the exchange
+	if err := channel.Publish(
+		exchange_name, // exchange
+		"",            // routing key, ignored by fanout exchanges
+		false,         // mandatory
+		false,         // immediate
+		message,       // message to publish
+	); err != nil {
+		fmt.Println(err.Error())
+		return err
+	}
+	return nil
+}
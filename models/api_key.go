@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKey Database model info
+// @Description Personal access token of a user for scripts, carries a subset of the user's roles, only the sha256 of the key is stored
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Name       string     `gorm:"not null;" json:"name"`
+	Prefix     string     `gorm:"not null;" json:"prefix"`
+	KeyHash    string     `gorm:"not null; unique;" json:"-"`
+	UserID     uint       `gorm:"not null; index;" json:"user_id"`
+	Roles      []Role     `gorm:"many2many:api_key_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"roles,omitempty"`
+	ExpiresAt  time.Time  `gorm:"not null;" json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
+
+// APIKeyPost model info
+// @Description APIKeyPost type information, expires_in_days defaults to API_KEY_LIFE_TIME_DAYS
+type APIKeyPost struct {
+	Name          string `json:"name" validate:"required,max=100"`
+	RoleIDs       []uint `json:"role_ids" validate:"required,min=1"`
+	ExpiresInDays int    `json:"expires_in_days" validate:"min=0"`
+}
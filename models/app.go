@@ -14,6 +14,39 @@ type App struct {
 	Active      bool   `gorm:"constraint:not null;" json:"active"`
 	Description string `gorm:"not null;" json:"description,omitempty"`
 	Roles       []Role `gorm:"association_foreignkey:AppID constraint:OnUpdate:SET NULL OnDelete:SET NULL" json:"roles,omitempty"`
+	MFARequired bool   `gorm:"constraint:not null; default:false;" json:"mfa_required"`
+
+	// lifetimes of the tokens issued for logins to the app
+	TokenPolicy AppTokenPolicy `gorm:"embedded" json:"token_policy"`
+	// users may sign in to the app with a link emailed to them instead of a password
+	MagicLinkEnabled bool `gorm:"constraint:not null; default:false;" json:"magic_link_enabled"`
+}
+
+// AppTokenPolicy model info
+// @Description Token and session lifetimes in minutes of logins to the app, zero falls back to the global default
+type AppTokenPolicy struct {
+	AccessTokenLifeTime  int `gorm:"constraint:not null; default:0;" json:"access_token_life_time"`
+	RefreshTokenLifeTime int `gorm:"constraint:not null; default:0;" json:"refresh_token_life_time"`
+	SessionIdleTimeout   int `gorm:"constraint:not null; default:0;" json:"session_idle_timeout"`
+	SessionMaxLifeTime   int `gorm:"constraint:not null; default:0;" json:"session_max_life_time"`
+}
+
+// AppTokenPolicyPut model info
+// @Description AppTokenPolicyPut type information, lifetimes are in minutes and zero uses the global default
+type AppTokenPolicyPut struct {
+	AccessTokenLifeTime  int  `json:"access_token_life_time" validate:"min=0,max=1440"`
+	RefreshTokenLifeTime int  `json:"refresh_token_life_time" validate:"min=0"`
+	SessionIdleTimeout   int  `json:"session_idle_timeout" validate:"min=0"`
+	SessionMaxLifeTime   int  `json:"session_max_life_time" validate:"min=0"`
+	MFARequired          bool `json:"mfa_required"`
+}
+
+// AppTokenPolicyGet model info
+// @Description Configured token policy of the app with its MFA requirement
+type AppTokenPolicyGet struct {
+	AppID       uint           `json:"app_id"`
+	TokenPolicy AppTokenPolicy `json:"token_policy"`
+	MFARequired bool           `json:"mfa_required"`
 }
 
 func (app *App) BeforeCreate(tx *gorm.DB) (err error) {
@@ -40,6 +73,10 @@ type AppGet struct {
 	Active      bool   `gorm:"default:true; constraint:not null;" json:"active"`
 	Description string `gorm:"not null;" json:"description,omitempty"`
 	Roles       []Role `gorm:"association_foreignkey:AppID constraint:OnUpdate:SET NULL OnDelete:SET NULL" json:"roles,omitempty"`
+	MFARequired bool   `json:"mfa_required"`
+
+	TokenPolicy      AppTokenPolicy `gorm:"embedded" json:"token_policy"`
+	MagicLinkEnabled bool           `json:"magic_link_enabled"`
 }
 
 // AppPut model info
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AppClient Database model info
+// @Description Client of an App for the client_credentials and OpenID Connect code grants, only the sha256 of the secret is stored
+// @Description RedirectURIs are the space separated uris allowed for the OpenID Connect code flow
+// @Description Public clients (browser or native apps) can not keep a secret, they use the code flow with PKCE only
+type AppClient struct {
+	ID           uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Name         string       `gorm:"not null;" json:"name,omitempty"`
+	ClientID     string       `gorm:"not null; unique;" json:"client_id"`
+	SecretHash   string       `gorm:"not null;" json:"-"`
+	Active       bool         `gorm:"constraint:not null;" json:"active"`
+	AppID        uint         `gorm:"not null; index;" json:"app_id"`
+	RedirectURIs string       `json:"redirect_uris"`
+	Public       bool         `gorm:"constraint:not null; default:false;" json:"public"`
+	CreatedAt    time.Time    `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+	LastUsedAt   sql.NullTime `json:"last_used_at" swaggertype:"string"`
+}
+
+func (app_client *AppClient) BeforeCreate(tx *gorm.DB) (err error) {
+	gen, _ := uuid.NewV7()
+	app_client.ClientID = gen.String()
+	return
+}
+
+// AppClientPost model info
+// @Description AppClientPost type information
+type AppClientPost struct {
+	Name         string   `json:"name,omitempty" validate:"required"`
+	RedirectURIs []string `json:"redirect_uris,omitempty" validate:"dive,url"`
+	// only set when the client is created, public clients get no secret
+	Public bool `json:"public,omitempty"`
+}
+
+// AppClientSecret model info
+// @Description Client credentials, the secret is only returned when created or rotated
+type AppClientSecret struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
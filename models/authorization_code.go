@@ -0,0 +1,24 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AuthorizationCode Database model info
+// @Description OpenID Connect authorization code bound to a client, redirect uri and PKCE challenge, only the sha256 of the code is stored
+type AuthorizationCode struct {
+	ID            uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	CodeHash      string       `gorm:"not null; unique;" json:"-"`
+	ClientID      string       `gorm:"not null; index;" json:"client_id"`
+	UserID        uint         `gorm:"not null;" json:"user_id"`
+	RedirectURI   string       `gorm:"not null;" json:"redirect_uri"`
+	Scope         string       `gorm:"not null;" json:"scope"`
+	Nonce         string       `json:"nonce"`
+	CodeChallenge string       `gorm:"not null;" json:"-"`
+	AuthTime      time.Time    `gorm:"not null;" json:"auth_time"`
+	MFA           bool         `gorm:"constraint:not null; default:false;" json:"mfa"`
+	ExpiresAt     time.Time    `gorm:"not null;" json:"expires_at"`
+	UsedAt        sql.NullTime `json:"used_at" swaggertype:"string"`
+	CreatedAt     time.Time    `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
@@ -1,18 +1,14 @@
 package models
 
-import (
-	"database/sql"
-)
-
 // Feature Database model info
 // @Description App type information
 type Feature struct {
-	ID          uint          `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
-	Name        string        `gorm:"not null; unique;" json:"name,omitempty"`
-	Description string        `gorm:"not null;" json:"description,omitempty"`
-	Active      bool          `gorm:"constraint:not null;" json:"active"`
-	RoleID      sql.NullInt64 `gorm:"foreignkey:RoleID OnDelete:SET NULL" json:"role,omitempty" swaggertype:"number"`
-	Endpoints   []Endpoint    `gorm:"association_foreignkey:FeatureID constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"endpoints,omitempty"`
+	ID          uint       `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Name        string     `gorm:"not null; unique;" json:"name,omitempty"`
+	Description string     `gorm:"not null;" json:"description,omitempty"`
+	Active      bool       `gorm:"constraint:not null;" json:"active"`
+	Roles       []Role     `gorm:"many2many:feature_roles; constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"roles,omitempty"`
+	Endpoints   []Endpoint `gorm:"association_foreignkey:FeatureID constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"endpoints,omitempty"`
 }
 
 // FeaturePost model info
@@ -30,6 +26,7 @@ type FeatureGet struct {
 	Name        string     `gorm:"not null; unique;" json:"name,omitempty"`
 	Description string     `gorm:"not null;" json:"description,omitempty"`
 	Active      bool       `gorm:"constraint:not null;" json:"active"`
+	Roles       []Role     `gorm:"many2many:feature_roles; constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"roles,omitempty"`
 	Endpoints   []Endpoint `gorm:"association_foreignkey:FeatureID constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"endpoints,omitempty"`
 }
 
@@ -0,0 +1,15 @@
+package models
+
+// FeatureRole Database model info
+// @Description Grant of a feature to a role, the join row of Feature.Roles with the condition requests must meet to use it
+type FeatureRole struct {
+	FeatureID uint   `gorm:"primaryKey" json:"feature_id"`
+	RoleID    uint   `gorm:"primaryKey" json:"role_id"`
+	Condition string `gorm:"column:grant_condition; not null; default:'';" json:"condition"`
+}
+
+// FeatureRoleCondition model info
+// @Description Condition of a feature grant, empty to allow every request, see utils.ParseCondition
+type FeatureRoleCondition struct {
+	Condition string `json:"condition"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Impersonation Database model info
+// @Description Audit record of a token issued to a privileged user to act as another user, the requests made with it are published as security events
+type Impersonation struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	TokenID    string    `gorm:"not null; unique;" json:"token_id"`
+	ActorID    uint      `gorm:"not null; index;" json:"actor_id"`
+	ActorEmail string    `gorm:"not null;" json:"actor_email"`
+	UserID     uint      `gorm:"not null; index;" json:"user_id"`
+	UserEmail  string    `gorm:"not null;" json:"user_email"`
+	Reason     string    `gorm:"not null;" json:"reason"`
+	IP         string    `json:"ip"`
+	ExpiresAt  time.Time `gorm:"not null;" json:"expires_at"`
+	CreatedAt  time.Time `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
+
+// ImpersonationPost model info
+// @Description ImpersonationPost type information, the reason is kept in the audit record
+type ImpersonationPost struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -6,6 +6,7 @@ import (
 
 	"blue-admin.com/configs"
 	"blue-admin.com/database"
+	"gorm.io/gorm"
 )
 
 func InitDatabase() {
@@ -21,15 +22,56 @@ func InitDatabase() {
 			&Endpoint{},
 			&Page{},
 			&JWTSalt{},
+			&RefreshToken{},
+			&RevokedToken{},
+			&UserRevocation{},
+			&SigningKey{},
+			&AppClient{},
+			&AuthorizationCode{},
+			&RecoveryCode{},
+			&LoginAttempt{},
+			&PasswordResetToken{},
+			&PasswordPolicy{},
+			&PasswordHistory{},
+			&Invitation{},
+			&Session{},
+			&APIKey{},
+			&Impersonation{},
+			&MagicLinkToken{},
+			&PermissionVersion{},
+			&FeatureRole{},
 		); err != nil {
 			log.Fatalln(err)
 		}
+		if err := migrateFeatureRoles(database); err != nil {
+			log.Fatalln(err)
+		}
 		fmt.Println("Database Migrated")
 	} else {
 		panic(err)
 	}
 }
 
+// Features used to belong to a single role through features.role_id, the role is
+// copied to the feature_roles join table before the column is dropped
+func migrateFeatureRoles(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&Feature{}, "role_id") {
+		return nil
+	}
+	if err := db.Exec(`INSERT INTO feature_roles (feature_id, role_id)
+		SELECT features.id, features.role_id FROM features
+		WHERE features.role_id IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM feature_roles WHERE feature_roles.feature_id = features.id AND feature_roles.role_id = features.role_id)`).Error; err != nil {
+		return err
+	}
+	if db.Migrator().HasConstraint(&Feature{}, "fk_roles_features") {
+		if err := db.Migrator().DropConstraint(&Feature{}, "fk_roles_features"); err != nil {
+			return err
+		}
+	}
+	return db.Migrator().DropColumn(&Feature{}, "role_id")
+}
+
 func CleanDatabase() {
 	configs.NewEnvFile("./configs")
 	database, err := database.ReturnSession()
@@ -45,6 +87,24 @@ func CleanDatabase() {
 			&Endpoint{},
 			&Page{},
 			&JWTSalt{},
+			&RefreshToken{},
+			&RevokedToken{},
+			&UserRevocation{},
+			&SigningKey{},
+			&AppClient{},
+			&AuthorizationCode{},
+			&RecoveryCode{},
+			&LoginAttempt{},
+			&PasswordResetToken{},
+			&PasswordPolicy{},
+			&PasswordHistory{},
+			&Invitation{},
+			&Session{},
+			&APIKey{},
+			&Impersonation{},
+			&MagicLinkToken{},
+			&PermissionVersion{},
+			&FeatureRole{},
 		)
 		fmt.Println("Database Cleaned")
 		// Reset autoincrement values
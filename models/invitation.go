@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Invitation Database model info
+// @Description Invitation of an email to an app with preselected roles, accepted through the emailed link
+type Invitation struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Email      string     `gorm:"not null; index;" json:"email"`
+	AppID      uint       `gorm:"not null; index;" json:"app_id"`
+	UserID     uint       `gorm:"not null; index;" json:"user_id"`
+	Roles      []Role     `gorm:"many2many:invitation_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"roles,omitempty"`
+	InvitedBy  uint       `json:"invited_by"`
+	ExpiresAt  time.Time  `gorm:"not null;" json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
+
+// InvitationPost model info
+// @Description InvitationPost type information
+type InvitationPost struct {
+	Name    string `json:"name"`
+	Email   string `json:"email" validate:"required,email"`
+	AppID   uint   `json:"app_id" validate:"required"`
+	RoleIDs []uint `json:"role_ids"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LoginAttempt Database model info
+// @Description Failed login counter of an email ("email:<address>") or a client ip ("ip:<address>")
+type LoginAttempt struct {
+	ID            uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Identifier    string       `gorm:"not null; unique;" json:"identifier"`
+	Failures      int          `gorm:"constraint:not null; default:0;" json:"failures"`
+	LastFailureAt time.Time    `gorm:"not null;" json:"last_failure_at"`
+	BlockedUntil  sql.NullTime `json:"blocked_until" swaggertype:"string"`
+}
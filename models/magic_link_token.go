@@ -0,0 +1,19 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// MagicLinkToken Database model info
+// @Description Single use passwordless login token bound to the app it was requested for, only the sha256 of the token is stored
+type MagicLinkToken struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	TokenHash string       `gorm:"not null; unique;" json:"-"`
+	UserID    uint         `gorm:"not null; index;" json:"user_id"`
+	AppID     uint         `gorm:"not null; index;" json:"app_id"`
+	IP        string       `json:"ip"`
+	ExpiresAt time.Time    `gorm:"not null;" json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at" swaggertype:"string"`
+	CreatedAt time.Time    `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordPolicy Database model info
+// @Description Password rules of an app, they tighten the global policy for users with roles of the app
+type PasswordPolicy struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	AppID         uint      `gorm:"not null; unique;" json:"app_id"`
+	MinLength     int       `gorm:"constraint:not null; default:0;" json:"min_length"`
+	RequireUpper  bool      `gorm:"constraint:not null; default:false;" json:"require_upper"`
+	RequireLower  bool      `gorm:"constraint:not null; default:false;" json:"require_lower"`
+	RequireDigit  bool      `gorm:"constraint:not null; default:false;" json:"require_digit"`
+	RequireSymbol bool      `gorm:"constraint:not null; default:false;" json:"require_symbol"`
+	DenyCommon    bool      `gorm:"constraint:not null; default:false;" json:"deny_common"`
+	HistoryCount  int       `gorm:"constraint:not null; default:0;" json:"history_count"`
+	MaxAgeDays    int       `gorm:"constraint:not null; default:0;" json:"max_age_days"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PasswordPolicyPut model info
+// @Description PasswordPolicyPut type information
+type PasswordPolicyPut struct {
+	MinLength     int  `json:"min_length" validate:"min=0,max=128"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	DenyCommon    bool `json:"deny_common"`
+	HistoryCount  int  `json:"history_count" validate:"min=0,max=24"`
+	MaxAgeDays    int  `json:"max_age_days" validate:"min=0"`
+}
+
+// PasswordHistory Database model info
+// @Description Hashes of the previous passwords of a user, checked so they are not reused
+type PasswordHistory struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	UserID       uint      `gorm:"not null; index;" json:"user_id"`
+	PasswordHash string    `gorm:"not null;" json:"-"`
+	CreatedAt    time.Time `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
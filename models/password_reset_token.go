@@ -0,0 +1,17 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PasswordResetToken Database model info
+// @Description Single use password reset token, only the sha256 of the token is stored
+type PasswordResetToken struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	TokenHash string       `gorm:"not null; unique;" json:"-"`
+	UserID    uint         `gorm:"not null; index;" json:"user_id"`
+	ExpiresAt time.Time    `gorm:"not null;" json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at" swaggertype:"string"`
+	CreatedAt time.Time    `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
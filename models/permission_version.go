@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+// PermissionVersion Database model info
+// @Description Version of the permission matrix, a single row bumped whenever apps, roles, features or endpoints change so every instance reloads its copy
+type PermissionVersion struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement:false" json:"id,omitempty"`
+	Version   int64     `gorm:"not null; default:0;" json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RecoveryCode Database model info
+// @Description One time MFA recovery code of a user, only the sha256 of the code is stored
+type RecoveryCode struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	UserID    uint         `gorm:"not null; index;" json:"user_id"`
+	CodeHash  string       `gorm:"not null; unique;" json:"-"`
+	UsedAt    sql.NullTime `json:"used_at" swaggertype:"string"`
+	CreatedAt time.Time    `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RefreshToken Database model info
+// @Description Persisted opaque refresh token, only the sha256 of the token is stored
+type RefreshToken struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	TokenHash string       `gorm:"not null; unique;" json:"-"`
+	FamilyID  string       `gorm:"not null; index;" json:"family_id"`
+	UserID    uint         `gorm:"not null; index;" json:"user_id"`
+	ExpiresAt time.Time    `gorm:"not null;" json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at" swaggertype:"string"`
+	Revoked   bool         `gorm:"constraint:not null;" json:"revoked"`
+	MFA       bool         `gorm:"constraint:not null; default:false;" json:"mfa"`
+	CreatedAt time.Time    `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
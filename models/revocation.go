@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// RevokedToken Database model info
+// @Description Access token id (jti) revoked before its expiry
+type RevokedToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	JTI       string    `gorm:"not null; unique;" json:"jti"`
+	UserID    uint      `gorm:"not null; index;" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;" json:"expires_at"`
+	CreatedAt time.Time `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+}
+
+// UserRevocation Database model info
+// @Description Access tokens of the user issued before NotBefore are rejected
+type UserRevocation struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	UserID    uint      `gorm:"not null; unique;" json:"user_id"`
+	NotBefore time.Time `gorm:"not null;" json:"not_before"`
+}
@@ -12,9 +12,12 @@ type Role struct {
 	Description string        `gorm:"not null;" json:"description,omitempty"`
 	Active      bool          `gorm:"default:true; constraint:not null;" json:"active"`
 	Users       []User        `gorm:"many2many:user_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"users,omitempty"`
-	Features    []Feature     `gorm:"foreignkey:RoleID; constraint:OnUpdate:CASCADE; OnDelete:SET NULL;" json:"features,omitempty"`
+	Features    []Feature     `gorm:"many2many:feature_roles; constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"features,omitempty"`
 	Pages       []Page        `gorm:"many2many:page_roles; constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"pages,omitempty"`
 	AppID       sql.NullInt64 `gorm:"foreignkey:AppID OnDelete:SET NULL" json:"app,omitempty" swaggertype:"number"`
+	MFARequired bool          `gorm:"constraint:not null; default:false;" json:"mfa_required"`
+	// roles of the same app whose permissions the role inherits
+	Parents []Role `gorm:"many2many:role_parents; joinForeignKey:RoleID; joinReferences:ParentID; constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"parents,omitempty"`
 }
 
 // RolePost model info
@@ -34,7 +37,9 @@ type RoleGet struct {
 	Active      bool          `gorm:"constraint:not null;" json:"active"`
 	AppID       sql.NullInt64 `gorm:"foreignkey:AppID OnDelete:SET NULL" json:"app,omitempty" swaggertype:"number"`
 	Users       []User        `gorm:"many2many:user_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"users,omitempty"`
-	Features    []Feature     `gorm:"foreignkey:RoleID; constraint:OnUpdate:CASCADE; OnDelete:SET NULL;" json:"features,omitempty"`
+	Features    []Feature     `gorm:"many2many:feature_roles; constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"features,omitempty"`
+	Parents     []Role        `gorm:"many2many:role_parents; joinForeignKey:RoleID; joinReferences:ParentID; constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"parents,omitempty"`
+	MFARequired bool          `json:"mfa_required"`
 }
 
 // RolePut model info
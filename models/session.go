@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Session Database model info
+// @Description A login of the user, refreshed tokens stay in the session (the refresh token family) until it expires or is revoked
+// @Description ClientID is the OpenID Connect client the session was started for, only that client can refresh it
+type Session struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	SessionID  string     `gorm:"not null; unique;" json:"session_id"`
+	UserID     uint       `gorm:"not null; index;" json:"user_id"`
+	AppID      *uint      `gorm:"index;" json:"app_id"`
+	ClientID   string     `gorm:"index;" json:"client_id,omitempty"`
+	IP         string     `json:"ip"`
+	UserAgent  string     `json:"user_agent"`
+	MFA        bool       `gorm:"constraint:not null; default:false;" json:"mfa"`
+	CreatedAt  time.Time  `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+	LastSeenAt time.Time  `gorm:"not null; index;" json:"last_seen_at"`
+	ExpiresAt  *time.Time `gorm:"index;" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	Current    bool       `gorm:"-" json:"current,omitempty"`
+}
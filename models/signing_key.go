@@ -0,0 +1,19 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SigningKey Database model info
+// @Description Asymmetric JWT signing key, retired keys stay published until PublishUntil
+type SigningKey struct {
+	ID           uint         `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Kid          string       `gorm:"not null; unique;" json:"kid"`
+	Algorithm    string       `gorm:"not null;" json:"algorithm"`
+	PrivateKey   string       `gorm:"not null;" json:"-"`
+	PublicKey    string       `gorm:"not null;" json:"public_key"`
+	Active       bool         `gorm:"constraint:not null;" json:"active"`
+	CreatedAt    time.Time    `gorm:"constraint:not null; default:current_timestamp;" json:"created_at"`
+	PublishUntil sql.NullTime `json:"publish_until" swaggertype:"string"`
+}
@@ -3,28 +3,55 @@ package models
 import (
 	"time"
 
+	"blue-admin.com/passwords"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Onboarding states of a user, invited users accept an invitation before they can log in,
+// pending users still have to verify their email
+const (
+	UserStatusInvited = "invited"
+	UserStatusPending = "pending"
+	UserStatusActive  = "active"
+)
+
+// Backends a user can log in with, directory users have no usable local password
+const (
+	AuthSourceLocal = "local"
+	AuthSourceLDAP  = "ldap"
+)
+
 // User Database model info
 // @Description App type information
 type User struct {
-	ID            uint      `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
-	Name          string    `gorm:"not null;" json:"name,omitempty"`
-	Email         string    `gorm:"not null; unique;" json:"email,omitempty"`
-	Password      string    `gorm:"not null;" json:"password,omitempty"`
-	DateRegistred time.Time `gorm:"constraint:not null; default:current_timestamp;" json:"date_registered,omitempty"`
-	Disabled      bool      `gorm:"constraint:not null;" json:"disabled"`
-	UUID          string    `gorm:"constraint:not null; unique; type:string;" json:"uuid"`
-	Roles         []Role    `gorm:"many2many:user_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"roles,omitempty"`
+	ID            uint       `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Name          string     `gorm:"not null;" json:"name,omitempty"`
+	Email         string     `gorm:"not null; unique;" json:"email,omitempty"`
+	Password      string     `gorm:"not null;" json:"password,omitempty"`
+	DateRegistred time.Time  `gorm:"constraint:not null; default:current_timestamp;" json:"date_registered,omitempty"`
+	Disabled      bool       `gorm:"constraint:not null;" json:"disabled"`
+	UUID          string     `gorm:"constraint:not null; unique; type:string;" json:"uuid"`
+	Roles         []Role     `gorm:"many2many:user_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"roles,omitempty"`
+	MFAEnabled    bool       `gorm:"constraint:not null; default:false;" json:"mfa_enabled"`
+	MFASecret     string     `json:"-"`
+	MFALastStep   int64      `gorm:"constraint:not null; default:0;" json:"-"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	// set by an admin reset, the next login has to choose a new password
+	PasswordChangeRequired bool       `gorm:"constraint:not null; default:false;" json:"password_change_required"`
+	PasswordChangedAt      *time.Time `json:"password_changed_at"`
+	Status                 string     `gorm:"not null; default:active;" json:"status"`
+	EmailVerifiedAt        *time.Time `json:"email_verified_at"`
+	AuthSource             string     `gorm:"not null; default:local;" json:"auth_source"`
 }
 
 func (user *User) BeforeCreate(tx *gorm.DB) (err error) {
 	gen, _ := uuid.NewV7()
 	id := gen.String()
 	user.UUID = id
-	user.Password = hashfunc(user.Password)
+	user.Password, err = passwords.Hash(user.Password)
+	changed_at := time.Now().UTC()
+	user.PasswordChangedAt = &changed_at
 	return
 }
 
@@ -40,13 +67,17 @@ type UserPost struct {
 // UserGet model info
 // @Description UserGet type information
 type UserGet struct {
-	ID            uint      `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
-	Name          string    `gorm:"not null;" json:"name,omitempty"`
-	Email         string    `gorm:"not null; unique;" json:"email,omitempty"`
-	DateRegistred time.Time `gorm:"constraint:not null; default:current_timestamp;" json:"date_registered,omitempty"`
-	Disabled      bool      `gorm:"constraint:not null;" json:"disabled"`
-	UUID          string    `gorm:"constraint:not null; unique; type:string;" json:"uuid,omitempty"`
-	Roles         []Role    `gorm:"many2many:user_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"roles,omitempty"`
+	ID                     uint       `gorm:"primaryKey;autoIncrement:true" json:"id,omitempty"`
+	Name                   string     `gorm:"not null;" json:"name,omitempty"`
+	Email                  string     `gorm:"not null; unique;" json:"email,omitempty"`
+	DateRegistred          time.Time  `gorm:"constraint:not null; default:current_timestamp;" json:"date_registered,omitempty"`
+	Disabled               bool       `gorm:"constraint:not null;" json:"disabled"`
+	UUID                   string     `gorm:"constraint:not null; unique; type:string;" json:"uuid,omitempty"`
+	Roles                  []Role     `gorm:"many2many:user_roles; constraint:OnUpdate:CASCADE; OnDelete:CASCADE;" json:"roles,omitempty"`
+	MFAEnabled             bool       `json:"mfa_enabled"`
+	LockedUntil            *time.Time `json:"locked_until"`
+	PasswordChangeRequired bool       `json:"password_change_required"`
+	AuthSource             string     `json:"auth_source"`
 }
 
 // UserGet model info
@@ -0,0 +1,59 @@
+package passwords
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"blue-admin.com/configs"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher uses the standard modular crypt format $2a$<cost>$<salt+hash>
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher reads the cost from BCRYPT_COST
+func NewBcryptHasher() *BcryptHasher {
+	cost, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("BCRYPT_COST", "12"))
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = 12
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+func (h *BcryptHasher) Name() string {
+	return "bcrypt"
+}
+
+func (h *BcryptHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (h *BcryptHasher) Verify(encoded string, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+	return cost != h.Cost
+}
@@ -0,0 +1,67 @@
+package passwords
+
+import (
+	"errors"
+	"strings"
+
+	"blue-admin.com/configs"
+)
+
+var ErrUnknownFormat = errors.New("unknown password hash format")
+
+// Hasher hashes passwords into a self describing encoded string
+// (algorithm, parameters and per password salt) and verifies them back
+type Hasher interface {
+	Name() string
+	Hash(password string) (string, error)
+	Verify(encoded string, password string) (bool, error)
+	// reports whether encoded was produced with other parameters than the current ones
+	NeedsRehash(encoded string) bool
+	// reports whether encoded was produced by this hasher
+	Recognizes(encoded string) bool
+}
+
+// hashers in the order they are tried when identifying an encoded hash
+// legacy must be the last one as it only checks the shape of the value
+func hashers() []Hasher {
+	return []Hasher{
+		NewArgon2idHasher(),
+		NewBcryptHasher(),
+		LegacySHA512Hasher{},
+	}
+}
+
+// Default returns the hasher configured by PASSWORD_HASHER (argon2id or bcrypt)
+func Default() Hasher {
+	switch strings.ToLower(configs.AppConfig.GetOrDefault("PASSWORD_HASHER", "argon2id")) {
+	case "bcrypt":
+		return NewBcryptHasher()
+	default:
+		return NewArgon2idHasher()
+	}
+}
+
+// Hash hashes password with the configured default hasher
+func Hash(password string) (string, error) {
+	return Default().Hash(password)
+}
+
+// Verify checks password against encoded in any of the supported formats
+func Verify(encoded string, password string) (bool, error) {
+	for _, hasher := range hashers() {
+		if hasher.Recognizes(encoded) {
+			return hasher.Verify(encoded, password)
+		}
+	}
+	return false, ErrUnknownFormat
+}
+
+// NeedsRehash reports whether encoded should be replaced by a fresh hash from the
+// default hasher, either because it is a legacy/other algorithm hash or its parameters are outdated
+func NeedsRehash(encoded string) bool {
+	hasher := Default()
+	if !hasher.Recognizes(encoded) {
+		return true
+	}
+	return hasher.NeedsRehash(encoded)
+}
@@ -0,0 +1,73 @@
+package passwords
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := &Argon2idHasher{Memory: 8 * 1024, Iterations: 1, Parallelism: 1}
+
+	encoded, err := hasher.Hash("password123")
+	require.NoError(t, err, "Hashing should not return an error")
+	assert.True(t, hasher.Recognizes(encoded), "Hasher should recognize its own format")
+	assert.Contains(t, encoded, "$m=8192,t=1,p=1$", "Encoded hash should carry its parameters")
+
+	match, err := hasher.Verify(encoded, "password123")
+	require.NoError(t, err)
+	assert.True(t, match, "Passwords should match")
+
+	match, err = hasher.Verify(encoded, "wrongpassword")
+	require.NoError(t, err)
+	assert.False(t, match, "Passwords should not match")
+
+	assert.False(t, hasher.NeedsRehash(encoded), "Same parameters should not need rehash")
+	stronger := &Argon2idHasher{Memory: 16 * 1024, Iterations: 1, Parallelism: 1}
+	assert.True(t, stronger.NeedsRehash(encoded), "Changed parameters should need rehash")
+
+	_, err = hasher.Verify("$argon2id$v=19$broken", "password123")
+	assert.Error(t, err, "Malformed hash should return an error")
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := &BcryptHasher{Cost: 4}
+
+	encoded, err := hasher.Hash("password123")
+	require.NoError(t, err, "Hashing should not return an error")
+	assert.True(t, hasher.Recognizes(encoded), "Hasher should recognize its own format")
+
+	match, err := hasher.Verify(encoded, "password123")
+	require.NoError(t, err)
+	assert.True(t, match, "Passwords should match")
+
+	match, err = hasher.Verify(encoded, "wrongpassword")
+	require.NoError(t, err)
+	assert.False(t, match, "Passwords should not match")
+
+	assert.True(t, (&BcryptHasher{Cost: 5}).NeedsRehash(encoded), "Changed cost should need rehash")
+}
+
+func TestLegacyHash(t *testing.T) {
+	password := "password123"
+	expectedHash := "d81a4bcb5018ed48fd54d83f7f3d164a1fd2e2c4039fc99b297e7fbe23af373d7309cebdc3a928c9dd01ec51f70d50d1a4ec98ef398fd13034b58760c60e79f6"
+
+	assert.Equal(t, expectedHash, LegacyHash(password, "test_salt"), "Legacy hash should match the expected hash")
+
+	match, err := Verify(expectedHash, password)
+	require.NoError(t, err)
+	assert.True(t, match, "Legacy hash should verify")
+
+	// hashes created by the old models.hashfunc without SECRETE_SALT
+	match, err = Verify(LegacyHash(password, ""), password)
+	require.NoError(t, err)
+	assert.True(t, match, "Unsalted legacy hash should verify")
+
+	assert.True(t, NeedsRehash(expectedHash), "Legacy hash should need rehash")
+}
+
+func TestVerifyUnknownFormat(t *testing.T) {
+	_, err := Verify("plain-text", "plain-text")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,59 @@
+package passwords
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"blue-admin.com/configs"
+)
+
+// LegacySHA512Hasher only verifies the hex encoded SHA-512(password + SECRETE_SALT)
+// hashes stored before the encoded formats were introduced, it never produces new ones
+type LegacySHA512Hasher struct{}
+
+func (LegacySHA512Hasher) Name() string {
+	return "sha512"
+}
+
+func (LegacySHA512Hasher) Recognizes(encoded string) bool {
+	if len(encoded) != sha512.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(encoded)
+	return err == nil
+}
+
+func (LegacySHA512Hasher) Hash(password string) (string, error) {
+	return "", ErrUnknownFormat
+}
+
+func (LegacySHA512Hasher) Verify(encoded string, password string) (bool, error) {
+	for _, salt := range legacySalts() {
+		if subtle.ConstantTimeCompare([]byte(encoded), []byte(LegacyHash(password, salt))) == 1 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (LegacySHA512Hasher) NeedsRehash(encoded string) bool {
+	return true
+}
+
+// LegacyHash is the former single pass SHA-512 over password and salt
+func LegacyHash(password string, salt string) string {
+	hasher := sha512.New()
+	hasher.Write(append([]byte(password), []byte(salt)...))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// utils.HashFunc fell back to "test_salt" when SECRETE_SALT was unset
+// while models.hashfunc used the empty salt, so both have to be tried
+func legacySalts() []string {
+	salt := configs.AppConfig.Get("SECRETE_SALT")
+	if salt == "" {
+		return []string{"test_salt", ""}
+	}
+	return []string{salt}
+}
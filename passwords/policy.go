@@ -0,0 +1,183 @@
+package passwords
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"blue-admin.com/configs"
+)
+
+// Upper bound for any policy, long inputs only make hashing expensive
+const MaxLength = 128
+
+// Policy passwords are checked against when they are set
+type Policy struct {
+	MinLength     int  `json:"min_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	DenyCommon    bool `json:"deny_common"`
+	// number of previous passwords that can not be reused, 0 disables the history check
+	HistoryCount int `json:"history_count"`
+	// days after which the password has to be changed, 0 disables expiry
+	MaxAgeDays int `json:"max_age_days"`
+}
+
+// Violation of a single rule, reported per field so clients can show it next to the input
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PolicyError carries every rule the password broke
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "password does not meet the policy: " + strings.Join(messages, ", ")
+}
+
+func policyInt(key string, fallback int) int {
+	value, err := strconv.Atoi(configs.AppConfig.GetOrDefault(key, strconv.Itoa(fallback)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func policyBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(configs.AppConfig.GetOrDefault(key, strconv.FormatBool(fallback)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// GlobalPolicy reads the policy applied to every user from the PASSWORD_* settings
+func GlobalPolicy() Policy {
+	return Policy{
+		MinLength:     policyInt("PASSWORD_MIN_LENGTH", 8),
+		RequireUpper:  policyBool("PASSWORD_REQUIRE_UPPER", false),
+		RequireLower:  policyBool("PASSWORD_REQUIRE_LOWER", false),
+		RequireDigit:  policyBool("PASSWORD_REQUIRE_DIGIT", false),
+		RequireSymbol: policyBool("PASSWORD_REQUIRE_SYMBOL", false),
+		DenyCommon:    policyBool("PASSWORD_DENY_COMMON", true),
+		HistoryCount:  policyInt("PASSWORD_HISTORY_COUNT", 5),
+		MaxAgeDays:    policyInt("PASSWORD_MAX_AGE_DAYS", 0),
+	}
+}
+
+// Merge returns the stricter setting of both policies for every rule
+func (p Policy) Merge(other Policy) Policy {
+	merged := Policy{
+		MinLength:     max(p.MinLength, other.MinLength),
+		RequireUpper:  p.RequireUpper || other.RequireUpper,
+		RequireLower:  p.RequireLower || other.RequireLower,
+		RequireDigit:  p.RequireDigit || other.RequireDigit,
+		RequireSymbol: p.RequireSymbol || other.RequireSymbol,
+		DenyCommon:    p.DenyCommon || other.DenyCommon,
+		HistoryCount:  max(p.HistoryCount, other.HistoryCount),
+		MaxAgeDays:    p.MaxAgeDays,
+	}
+	if other.MaxAgeDays > 0 && (merged.MaxAgeDays == 0 || other.MaxAgeDays < merged.MaxAgeDays) {
+		merged.MaxAgeDays = other.MaxAgeDays
+	}
+	return merged
+}
+
+// Check validates the composition of the password, the history is checked by the caller
+// as it needs the stored hashes
+func (p Policy) Check(password string) error {
+	violations := []Violation{}
+	add := func(rule string, message string) {
+		violations = append(violations, Violation{Field: "password", Rule: rule, Message: message})
+	}
+
+	length := len([]rune(password))
+	if length < p.MinLength {
+		add("min_length", fmt.Sprintf("must be at least %v characters", p.MinLength))
+	}
+	if length > MaxLength {
+		add("max_length", fmt.Sprintf("must be at most %v characters", MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			upper = true
+		case unicode.IsLower(char):
+			lower = true
+		case unicode.IsDigit(char):
+			digit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		add("upper", "must contain an upper case letter")
+	}
+	if p.RequireLower && !lower {
+		add("lower", "must contain a lower case letter")
+	}
+	if p.RequireDigit && !digit {
+		add("digit", "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		add("symbol", "must contain a symbol")
+	}
+	if p.DenyCommon && IsCommon(password) {
+		add("common", "is too common")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+var (
+	denyListOnce sync.Once
+	denyList     map[string]struct{}
+)
+
+// IsCommon reports whether the password is on the deny list read from PASSWORD_DENYLIST_FILE,
+// one password per line, compared case insensitively
+func IsCommon(password string) bool {
+	denyListOnce.Do(func() {
+		denyList = loadDenyList(configs.AppConfig.GetOrDefault("PASSWORD_DENYLIST_FILE", "configs/common_passwords.txt"))
+	})
+	_, found := denyList[strings.ToLower(password)]
+	return found
+}
+
+func loadDenyList(path string) map[string]struct{} {
+	deny_list := make(map[string]struct{})
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("password deny list not loaded: %v\n", err)
+		return deny_list
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line != "" && !strings.HasPrefix(line, "#") {
+			deny_list[line] = struct{}{}
+		}
+	}
+	return deny_list
+}
@@ -0,0 +1,43 @@
+package passwords
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	denyListOnce.Do(func() {})
+	denyList = loadDenyList("../configs/common_passwords.txt")
+
+	policy := Policy{MinLength: 10, RequireUpper: true, RequireDigit: true, RequireSymbol: true, DenyCommon: true}
+	assert.NoError(t, policy.Check("Correct-Horse-9"), "Strong password should pass")
+
+	err := policy.Check("short")
+	var policy_err *PolicyError
+	require.True(t, errors.As(err, &policy_err), "Violations should come back as a PolicyError")
+	rules := []string{}
+	for _, violation := range policy_err.Violations {
+		assert.Equal(t, "password", violation.Field)
+		rules = append(rules, violation.Rule)
+	}
+	assert.ElementsMatch(t, []string{"min_length", "upper", "digit", "symbol"}, rules)
+
+	err = Policy{MinLength: 6, DenyCommon: true}.Check("Password123")
+	require.True(t, errors.As(err, &policy_err))
+	assert.Equal(t, "common", policy_err.Violations[0].Rule, "Deny list should be case insensitive")
+}
+
+func TestPolicyMerge(t *testing.T) {
+	global := Policy{MinLength: 8, HistoryCount: 5, MaxAgeDays: 0}
+	app := Policy{MinLength: 12, RequireSymbol: true, HistoryCount: 3, MaxAgeDays: 90}
+
+	merged := global.Merge(app)
+	assert.Equal(t, 12, merged.MinLength)
+	assert.True(t, merged.RequireSymbol)
+	assert.Equal(t, 5, merged.HistoryCount)
+	assert.Equal(t, 90, merged.MaxAgeDays, "Expiry should apply when any policy sets it")
+	assert.Equal(t, 30, merged.Merge(Policy{MaxAgeDays: 30}).MaxAgeDays, "Shorter expiry should win")
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"blue-admin.com/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The route validator and the decision api resolve a token's roles alike, so one token gets the same decision from both
+func TestAuthorizationSameDecision(t *testing.T) {
+	// creating database for test
+	models.InitDatabase()
+	defer models.CleanDatabase()
+	setupUserTestApp()
+
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	ctx := context.Background()
+	admin_app := models.App{Name: "blue-admin", Description: "admin", Active: true}
+	require.NoError(t, db.Create(&admin_app).Error)
+	t.Setenv("APP_ID", admin_app.UUID)
+
+	app_id := sql.NullInt64{Int64: int64(admin_app.ID), Valid: true}
+	reader := models.Role{Name: "apps_reader", Description: "reader", Active: true, AppID: app_id}
+	guest := models.Role{Name: "apps_guest", Description: "guest", Active: true, AppID: app_id}
+	require.NoError(t, db.Create(&reader).Error)
+	require.NoError(t, db.Create(&guest).Error)
+	apps := models.Feature{Name: "apps_listing", Description: "apps", Active: true, Roles: []models.Role{reader}}
+	require.NoError(t, db.Create(&apps).Error)
+	feature_id := sql.NullInt64{Int64: int64(apps.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "get_apps_get", RoutePath: "/api/v1/app", Method: "GET", Description: "apps", FeatureID: feature_id}).Error)
+	require.NoError(t, utils.Permissions.Reload(db, ctx))
+
+	testAuthorizationSameDecision := []struct {
+		name        string        //name of string
+		description string        // description of the test case
+		userRoles   []models.Role // roles the user holds
+		tokenRoles  []string      // roles claim of the token
+		allowed     bool          // expected decision of both
+	}{
+		{
+			name:        "granted role",
+			description: "both allow, when the user holds a granted role",
+			userRoles:   []models.Role{reader},
+			tokenRoles:  []string{"apps_reader"},
+			allowed:     true,
+		},
+		{
+			name:        "stale roles claim",
+			description: "both deny, when the token claims a role the user no longer holds",
+			userRoles:   []models.Role{guest},
+			tokenRoles:  []string{"apps_reader"},
+			allowed:     false,
+		},
+		{
+			name:        "role granted after the login",
+			description: "both allow, when the user was given a granted role after the token was issued",
+			userRoles:   []models.Role{guest, reader},
+			tokenRoles:  []string{"apps_guest"},
+			allowed:     true,
+		},
+	}
+
+	for index, test := range testAuthorizationSameDecision {
+		t.Run(test.name, func(t *testing.T) {
+			user := models.User{Name: test.name, Email: test.name + "@mail.com", Password: "default@123", Status: models.UserStatusActive, Roles: test.userRoles}
+			require.NoError(t, db.Create(&user).Error)
+			token, err := utils.CreateJWTToken(user.Email, user.UUID, int(user.ID), test.tokenRoles, 5)
+			require.NoError(t, err, "test case %v", index)
+
+			decision, err := utils.Authorize(db, ctx, admin_app, utils.AuthorizationRequest{Token: token, Method: "GET", Path: "/api/v1/app?page=1&size=10"})
+			require.NoError(t, err)
+			assert.Equalf(t, test.allowed, decision.Allowed, test.description)
+
+			req := httptest.NewRequest("GET", groupPath+"/app?page=1&size=10", nil)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-APP-TOKEN", token)
+			resp, _ := TestApp.Test(req)
+			assert.Equalf(t, test.allowed, resp.StatusCode != 401, test.description+", got HTTP status %v", resp.StatusCode)
+		})
+	}
+}
@@ -129,7 +129,7 @@ func TestAppsLogin(t *testing.T) {
 						GrantType: "token_decode",
 						Email:     "superuser@mail.com",
 						Password:  "default@123",
-						Token:     response.Data.RefreshToken,
+						Token:     response.Data.AccessToken,
 					}
 
 					post_data_string, _ := json.Marshal(post_data_token)
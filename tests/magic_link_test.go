@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"blue-admin.com/controllers"
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"blue-admin.com/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type magicLinkPost struct {
+	GrantType string `json:"grant_type"`
+	Token     string `json:"token"`
+	AppUUID   string `json:"app_uuid,omitempty"`
+}
+
+// Links of an app other than blue-admin are redeemed without app_uuid, a link presented
+// for the wrong app is refused without being used up
+func TestMagicLinkLogin(t *testing.T) {
+	// creating database for test
+	models.InitDatabase()
+	defer models.CleanDatabase()
+	setupUserTestApp()
+
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	admin_app := models.App{Name: "blue-admin", Description: "admin", Active: true}
+	require.NoError(t, db.Create(&admin_app).Error)
+	t.Setenv("APP_ID", admin_app.UUID)
+	tools_app := models.App{Name: "tools", Description: "internal tools", Active: true, MagicLinkEnabled: true}
+	require.NoError(t, db.Create(&tools_app).Error)
+	user := models.User{Name: "linked", Email: "linked@mail.com", Password: "default@123", Status: models.UserStatusActive}
+	require.NoError(t, db.Create(&user).Error)
+
+	token, err := utils.IssueMagicLinkToken(db, context.Background(), user.ID, tools_app.ID, "")
+	require.NoError(t, err)
+
+	testMagicLinkLogin := []struct {
+		name         string        //name of string
+		description  string        // description of the test case
+		postData     magicLinkPost // expects post data to the uri
+		expectedCode int           // expected HTTP status code
+	}{
+		{
+			name:         "magic link for another app",
+			description:  "get HTTP status 401, when app_uuid names another app than the link's",
+			postData:     magicLinkPost{GrantType: "magic_link", Token: token, AppUUID: admin_app.UUID},
+			expectedCode: 401,
+		},
+		{
+			name:         "magic link without app uuid",
+			description:  "get HTTP status 202, when the link's app is used",
+			postData:     magicLinkPost{GrantType: "magic_link", Token: token},
+			expectedCode: 202,
+		},
+		{
+			name:         "magic link used twice",
+			description:  "get HTTP status 401, when the link was already used",
+			postData:     magicLinkPost{GrantType: "magic_link", Token: token},
+			expectedCode: 401,
+		},
+	}
+
+	for _, test := range testMagicLinkLogin {
+		t.Run(test.name, func(t *testing.T) {
+			post_data, _ := json.Marshal(test.postData)
+			req := httptest.NewRequest("POST", groupPath+"/login", bytes.NewReader(post_data))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := TestApp.Test(req)
+
+			// Verify, if the status code is as expected
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+
+			if resp.StatusCode == 202 {
+				var response struct {
+					Data controllers.TokenResponse `json:"data"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+				claims, err := utils.ParseJWTToken(response.Data.AccessToken)
+				require.NoError(t, err)
+				assert.True(t, utils.AudienceAllowed(claims, tools_app.UUID), "The session should be scoped to the link's app")
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Routes sharing a prefix with the routes that validate their own token must still require one
+var testUnauthenticatedRoutes = []struct {
+	name         string //name of string
+	description  string // description of the test case
+	method       string // request method
+	route        string // route path to test
+	expectedCode int    // expected HTTP status code
+}{
+	{
+		name:         "get password policy without token",
+		description:  "get HTTP status 401, when no token is sent",
+		method:       "GET",
+		route:        groupPath + "/passwordpolicy",
+		expectedCode: 401,
+	},
+	{
+		name:         "get password policy upper case without token",
+		description:  "get HTTP status 401, when no token is sent",
+		method:       "GET",
+		route:        groupPath + "/PasswordPolicy",
+		expectedCode: 401,
+	},
+}
+
+func TestUnauthenticatedRoutes(t *testing.T) {
+	setupUserTestApp()
+
+	for _, test := range testUnauthenticatedRoutes {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, nil)
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := TestApp.Test(req)
+
+			// Verify, if the status code is as expected
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		})
+	}
+}
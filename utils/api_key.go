@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// API keys are told apart from JWTs by their prefix
+const (
+	APIKeyPrefix  = "bpat_"
+	APIKeySubject = "API Key"
+)
+
+var (
+	ErrAPIKeyInvalid = errors.New("invalid or expired api key")
+	ErrAPIKeyRoles   = errors.New("api key roles must be active roles of the user")
+)
+
+var apiKeysSeen = &lastSeenThrottle{seen: make(map[string]time.Time)}
+
+// Default and longest api key lifetime in days from API_KEY_LIFE_TIME_DAYS and API_KEY_MAX_LIFE_TIME_DAYS
+func apiKeyLifeTime(days int) time.Duration {
+	max_days, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("API_KEY_MAX_LIFE_TIME_DAYS", "365"))
+	if max_days <= 0 {
+		max_days = 365
+	}
+	if days <= 0 {
+		days, _ = strconv.Atoi(configs.AppConfig.GetOrDefault("API_KEY_LIFE_TIME_DAYS", "90"))
+	}
+	if days <= 0 || days > max_days {
+		days = max_days
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// Creates a named api key carrying the given roles, which must be active roles of the user.
+// The key itself is only returned here, the prefix is kept so the owner can tell keys apart.
+func IssueAPIKey(db *gorm.DB, ctx context.Context, user_id uint, name string, role_ids []uint, expires_in_days int) (models.APIKey, string, error) {
+	var roles []models.Role
+	if res := db.WithContext(ctx).Model(&models.Role{}).
+		Joins("INNER JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ? AND roles.id IN ? AND roles.active = ?", user_id, role_ids, true).
+		Find(&roles); res.Error != nil {
+		return models.APIKey{}, "", res.Error
+	}
+	if len(roles) == 0 || len(roles) != len(role_ids) {
+		return models.APIKey{}, "", ErrAPIKeyRoles
+	}
+
+	token, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	key := APIKeyPrefix + token
+
+	now := time.Now().UTC()
+	api_key := models.APIKey{
+		Name:      name,
+		Prefix:    key[:len(APIKeyPrefix)+6],
+		KeyHash:   HashOpaqueToken(key),
+		UserID:    user_id,
+		Roles:     roles,
+		ExpiresAt: now.Add(apiKeyLifeTime(expires_in_days)),
+		CreatedAt: now,
+	}
+	if err := db.WithContext(ctx).Create(&api_key).Error; err != nil {
+		return models.APIKey{}, "", err
+	}
+	return api_key, key, nil
+}
+
+// Resolves an api key to the claims of its user, the roles are the key's roles the user still holds
+// so roles removed from the user are dropped from the key as well, keys are only valid for blue-admin's api
+func AuthenticateAPIKey(db *gorm.DB, ctx context.Context, key string) (UserClaim, error) {
+	if !IsAPIKey(key) {
+		return UserClaim{}, ErrAPIKeyInvalid
+	}
+
+	now := time.Now().UTC()
+	var api_key models.APIKey
+	if res := db.WithContext(ctx).Model(&models.APIKey{}).Where("key_hash = ? AND expires_at > ?", HashOpaqueToken(key), now).First(&api_key); res.Error != nil {
+		return UserClaim{}, ErrAPIKeyInvalid
+	}
+
+	var user models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND disabled = ? AND status <> ?", api_key.UserID, false, models.UserStatusInvited).First(&user); res.Error != nil {
+		return UserClaim{}, ErrAPIKeyInvalid
+	}
+
+	roles := make([]string, 0, 20)
+	if res := db.WithContext(ctx).Model(&models.Role{}).
+		Joins("INNER JOIN api_key_roles ON api_key_roles.role_id = roles.id").
+		Joins("INNER JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("api_key_roles.api_key_id = ? AND user_roles.user_id = ? AND roles.active = ?", api_key.ID, user.ID, true).
+		Pluck("roles.name", &roles); res.Error != nil {
+		return UserClaim{}, res.Error
+	}
+
+	if apiKeysSeen.due(api_key.KeyHash, now, sessionLastSeenInterval()) {
+		db.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", api_key.ID).Update("last_used_at", now)
+	}
+
+	return UserClaim{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   APIKeySubject,
+			Audience:  jwt.ClaimStrings{BlueAdminAudience()},
+			ExpiresAt: jwt.NewNumericDate(api_key.ExpiresAt),
+		},
+		Email:  user.Email,
+		Roles:  roles,
+		UUID:   user.UUID,
+		UserID: int(user.ID),
+	}, nil
+}
+
+// Removes an api key of the user
+func DeleteAPIKey(db *gorm.DB, ctx context.Context, user_id uint, key_id uint) error {
+	var api_key models.APIKey
+	if res := db.WithContext(ctx).Model(&models.APIKey{}).Where("id = ? AND user_id = ?", key_id, user_id).First(&api_key); res.Error != nil {
+		return res.Error
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&api_key).Association("Roles").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&api_key).Error
+	})
+}
+
+// Removes expired api keys, run by the scheduler
+func CleanExpiredAPIKeys() {
+	db, _ := database.ReturnSession()
+	var expired_ids []uint
+	db.Model(&models.APIKey{}).Where("expires_at < ?", time.Now().UTC()).Pluck("id", &expired_ids)
+	if len(expired_ids) > 0 {
+		db.Exec("DELETE FROM api_key_roles WHERE api_key_id IN ?", expired_ids)
+		db.Where("id IN ?", expired_ids).Delete(&models.APIKey{})
+	}
+	apiKeysSeen.expire(time.Now().UTC().Add(-time.Hour))
+}
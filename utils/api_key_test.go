@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	db := memoryDB(t, &models.User{}, &models.Role{}, &models.APIKey{})
+	ctx := context.Background()
+
+	reader := models.Role{Name: "reader", Description: "reader", Active: true}
+	writer := models.Role{Name: "writer", Description: "writer", Active: true}
+	other := models.Role{Name: "other", Description: "other", Active: true}
+	require.NoError(t, db.Create(&reader).Error)
+	require.NoError(t, db.Create(&writer).Error)
+	require.NoError(t, db.Create(&other).Error)
+	user := models.User{Name: "scripts", Email: "scripts@mail.com", Password: "password", Roles: []models.Role{reader, writer}}
+	require.NoError(t, db.Create(&user).Error)
+
+	_, _, err := IssueAPIKey(db, ctx, user.ID, "ci", []uint{reader.ID, other.ID}, 0)
+	assert.ErrorIs(t, err, ErrAPIKeyRoles, "Roles the user does not hold should be refused")
+
+	api_key, key, err := IssueAPIKey(db, ctx, user.ID, "ci", []uint{reader.ID, writer.ID}, 30)
+	require.NoError(t, err, "Issuing should not return an error")
+	assert.True(t, IsAPIKey(key))
+	assert.NotEqual(t, key, api_key.KeyHash, "Only the hash should be stored")
+	assert.WithinDuration(t, time.Now().UTC().Add(30*24*time.Hour), api_key.ExpiresAt, time.Minute)
+
+	claims, err := AuthenticateAPIKey(db, ctx, key)
+	require.NoError(t, err, "Key should authenticate")
+	assert.Equal(t, int(user.ID), claims.UserID)
+	assert.ElementsMatch(t, []string{"reader", "writer"}, claims.Roles)
+
+	var used models.APIKey
+	db.First(&used, api_key.ID)
+	assert.NotNil(t, used.LastUsedAt, "Last use should be recorded")
+
+	// roles taken from the user are dropped from the key too
+	require.NoError(t, db.Model(&user).Association("Roles").Delete(&writer))
+	claims, err = AuthenticateAPIKey(db, ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reader"}, claims.Roles)
+
+	_, err = AuthenticateAPIKey(db, ctx, key+"x")
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid, "Unknown keys should be refused")
+
+	db.Model(&models.APIKey{}).Where("id = ?", api_key.ID).Update("expires_at", time.Now().UTC().Add(-time.Minute))
+	_, err = AuthenticateAPIKey(db, ctx, key)
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid, "Expired keys should be refused")
+
+	assert.Error(t, DeleteAPIKey(db, ctx, user.ID+1, api_key.ID), "Keys of other users can not be deleted")
+	require.NoError(t, DeleteAPIKey(db, ctx, user.ID, api_key.ID))
+	var remaining int64
+	db.Model(&models.APIKey{}).Count(&remaining)
+	assert.Zero(t, remaining)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+var ErrClientInvalid = errors.New("invalid client credentials")
+
+// Generates a client secret and the sha256 stored in place of it
+func NewClientSecret() (string, string, error) {
+	secret, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, HashOpaqueToken(secret), nil
+}
+
+// Returns the active client with its active app
+func FindClient(db *gorm.DB, ctx context.Context, client_id string) (models.AppClient, models.App, error) {
+	var app_client models.AppClient
+	if res := db.WithContext(ctx).Model(&models.AppClient{}).Where("client_id = ? AND active = ?", client_id, true).First(&app_client); res.Error != nil {
+		return models.AppClient{}, models.App{}, ErrClientInvalid
+	}
+
+	var app models.App
+	if res := db.WithContext(ctx).Model(&models.App{}).Where("id = ? AND active = ?", app_client.AppID, true).First(&app); res.Error != nil {
+		return models.AppClient{}, models.App{}, ErrClientInvalid
+	}
+	return app_client, app, nil
+}
+
+// Constant time comparison of the presented secret with the stored hash
+func ClientSecretMatch(app_client models.AppClient, client_secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(app_client.SecretHash), []byte(HashOpaqueToken(client_secret))) == 1
+}
+
+// Reports whether the redirect uri was registered for the client, uris are compared exactly
+func RedirectURIAllowed(app_client models.AppClient, redirect_uri string) bool {
+	for _, allowed := range strings.Fields(app_client.RedirectURIs) {
+		if allowed == redirect_uri {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifies the client credentials and returns the client with the active roles of its app,
+// public clients have no secret and can not authenticate
+func AuthenticateClient(db *gorm.DB, ctx context.Context, client_id string, client_secret string) (models.AppClient, models.App, []string, error) {
+	app_client, app, err := FindClient(db, ctx, client_id)
+	if err != nil {
+		return models.AppClient{}, models.App{}, nil, err
+	}
+	if app_client.Public || !ClientSecretMatch(app_client, client_secret) {
+		return models.AppClient{}, models.App{}, nil, ErrClientInvalid
+	}
+
+	roles := make([]string, 0, 20)
+	if res := db.WithContext(ctx).Model(&models.Role{}).Where("app_id = ? AND active = ?", app.ID, true).Order("id").Pluck("name", &roles); res.Error != nil {
+		return models.AppClient{}, models.App{}, nil, res.Error
+	}
+
+	db.WithContext(ctx).Model(&models.AppClient{}).Where("id = ?", app_client.ID).UpdateColumn("last_used_at", sql.NullTime{Time: time.Now().UTC(), Valid: true})
+	return app_client, app, roles, nil
+}
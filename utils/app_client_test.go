@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateClient(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.Role{}, &models.AppClient{})
+	ctx := context.Background()
+
+	app := models.App{Name: "billing", Description: "billing service", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	other := models.App{Name: "other", Description: "other service", Active: true}
+	require.NoError(t, db.Create(&other).Error)
+	require.NoError(t, db.Create(&models.Role{Name: "billing_reader", Description: "reader", Active: true, AppID: sql.NullInt64{Int64: int64(app.ID), Valid: true}}).Error)
+	require.NoError(t, db.Create(&models.Role{Name: "other_reader", Description: "reader", Active: true, AppID: sql.NullInt64{Int64: int64(other.ID), Valid: true}}).Error)
+
+	secret, secret_hash, err := NewClientSecret()
+	require.NoError(t, err, "Generating secret should not return an error")
+	app_client := models.AppClient{Name: "worker", SecretHash: secret_hash, Active: true, AppID: app.ID}
+	require.NoError(t, db.Create(&app_client).Error)
+	assert.NotEqual(t, secret, app_client.SecretHash, "Secret should not be stored in clear")
+
+	client, client_app, roles, err := AuthenticateClient(db, ctx, app_client.ClientID, secret)
+	require.NoError(t, err, "Valid credentials should be accepted")
+	assert.Equal(t, app_client.ID, client.ID)
+	assert.Equal(t, app.UUID, client_app.UUID)
+	assert.Equal(t, []string{"billing_reader"}, roles, "Roles should come from the client's app only")
+
+	_, _, _, err = AuthenticateClient(db, ctx, app_client.ClientID, "wrong")
+	assert.ErrorIs(t, err, ErrClientInvalid, "Wrong secret should be rejected")
+
+	// public clients only use the code flow with PKCE, even the stored secret does not authenticate them
+	public_client := models.AppClient{Name: "spa", SecretHash: secret_hash, Active: true, AppID: app.ID, Public: true}
+	require.NoError(t, db.Create(&public_client).Error)
+	_, _, _, err = AuthenticateClient(db, ctx, public_client.ClientID, secret)
+	assert.ErrorIs(t, err, ErrClientInvalid, "Public clients should not authenticate with a secret")
+
+	db.Model(&models.AppClient{}).Where("id = ?", app_client.ID).UpdateColumn("active", false)
+	_, _, _, err = AuthenticateClient(db, ctx, app_client.ClientID, secret)
+	assert.ErrorIs(t, err, ErrClientInvalid, "Inactive clients should be rejected")
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+var ErrAppInactive = errors.New("app not found or inactive")
+
+// Audience blue-admin's own api accepts, the uuid of this deployment's app from APP_ID
+func BlueAdminAudience() string {
+	return configs.AppConfig.Get("APP_ID")
+}
+
+// Tokens are only accepted by the app named in their aud claim, tokens without one are refused
+func AudienceAllowed(claims UserClaim, app_uuid string) bool {
+	return app_uuid != "" && slices.Contains(claims.Audience, app_uuid)
+}
+
+// Active app a login asked to be scoped to by its uuid
+func FindActiveApp(db *gorm.DB, ctx context.Context, app_uuid string) (models.App, error) {
+	var app models.App
+	if res := db.WithContext(ctx).Model(&models.App{}).Where("uuid = ? AND active = ?", app_uuid, true).First(&app); res.Error != nil {
+		return models.App{}, ErrAppInactive
+	}
+	return app, nil
+}
+
+// Names of the roles that belong to the app
+func AppRoleNames(roles []models.Role, app_id uint) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role.AppID.Valid && uint(role.AppID.Int64) == app_id {
+			names = append(names, role.Name)
+		}
+	}
+	return names
+}
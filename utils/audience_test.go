@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppScopedTokens(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.Role{}, &models.Session{})
+	ctx := context.Background()
+
+	billing := models.App{Name: "billing", Description: "billing service", Active: true}
+	require.NoError(t, db.Create(&billing).Error)
+	shop := models.App{Name: "shop", Description: "shop service", Active: true}
+	require.NoError(t, db.Create(&shop).Error)
+
+	_, err := FindActiveApp(db, ctx, billing.UUID)
+	require.NoError(t, err)
+	_, err = FindActiveApp(db, ctx, "unknown")
+	assert.ErrorIs(t, err, ErrAppInactive)
+
+	roles := []models.Role{
+		{Name: "billing_reader", AppID: sql.NullInt64{Int64: int64(billing.ID), Valid: true}},
+		{Name: "shop_reader", AppID: sql.NullInt64{Int64: int64(shop.ID), Valid: true}},
+		{Name: "global"},
+	}
+	assert.Equal(t, []string{"billing_reader"}, AppRoleNames(roles, billing.ID), "Only the app's roles should be kept")
+
+	scoped, err := StartSession(db, ctx, 1, &billing.ID, "", "", "", false)
+	require.NoError(t, err)
+	unscoped, err := StartSession(db, ctx, 1, nil, "", "", "", false)
+	require.NoError(t, err)
+
+	session_policy, err := LoadSessionPolicy(db, ctx, scoped.SessionID)
+	require.NoError(t, err)
+	require.NotNil(t, session_policy.App)
+	assert.Equal(t, billing.UUID, session_policy.App.UUID)
+	session_policy, err = LoadSessionPolicy(db, ctx, unscoped.SessionID)
+	require.NoError(t, err)
+	assert.Nil(t, session_policy.App, "Sessions without an app should not be scoped")
+
+	db.Model(&models.App{}).Where("id = ?", billing.ID).UpdateColumn("active", false)
+	_, err = LoadSessionPolicy(db, ctx, scoped.SessionID)
+	assert.ErrorIs(t, err, ErrAppInactive, "Sessions of deactivated apps should not be refreshed")
+
+	assert.False(t, AudienceAllowed(UserClaim{}, billing.UUID), "Tokens without an audience should be refused")
+	scoped_claims := UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{billing.UUID}}}
+	assert.True(t, AudienceAllowed(scoped_claims, billing.UUID))
+	assert.False(t, AudienceAllowed(scoped_claims, shop.UUID), "Tokens of another app should be refused")
+}
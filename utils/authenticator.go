@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Returned when the backend does not know the user or the password is wrong,
+// the next backend is tried and the attempt counts towards the lockout
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Authenticator checks an email and password against a user store and returns the local user
+// the tokens are issued for, backends outside the database provision the user on first login
+type Authenticator interface {
+	Name() string
+	Authenticate(db *gorm.DB, ctx context.Context, email string, password string) (models.User, error)
+}
+
+// Backends available to AUTHENTICATORS, a comma separated list tried in order, defaults to local
+var authenticatorBackends = map[string]func() Authenticator{
+	models.AuthSourceLocal: func() Authenticator { return LocalAuthenticator{} },
+	models.AuthSourceLDAP:  func() Authenticator { return NewLDAPAuthenticator() },
+}
+
+func Authenticators() ([]Authenticator, error) {
+	authenticators := make([]Authenticator, 0, 2)
+	for _, name := range strings.Split(configs.AppConfig.GetOrDefault("AUTHENTICATORS", models.AuthSourceLocal), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		backend, ok := authenticatorBackends[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown authenticator %v", name)
+		}
+		authenticators = append(authenticators, backend())
+	}
+	return authenticators, nil
+}
+
+// Tries the configured backends in order, the user is returned with its associations loaded.
+// ErrInvalidCredentials means no backend accepted the password, other errors are backend failures.
+func AuthenticateUser(db *gorm.DB, ctx context.Context, email string, password string) (models.User, error) {
+	authenticators, err := Authenticators()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var backend_err error
+	for _, authenticator := range authenticators {
+		user, err := authenticator.Authenticate(db, ctx, email, password)
+		if errors.Is(err, ErrInvalidCredentials) {
+			continue
+		}
+		if err != nil {
+			// an unreachable backend should not hide a match in the next one
+			backend_err = fmt.Errorf("%v authenticator: %w", authenticator.Name(), err)
+			continue
+		}
+
+		if res := db.WithContext(ctx).Model(&models.User{}).Preload(clause.Associations).Where("id = ?", user.ID).First(&user); res.Error != nil {
+			return models.User{}, res.Error
+		}
+		return user, nil
+	}
+	if backend_err != nil {
+		return models.User{}, backend_err
+	}
+	return models.User{}, ErrInvalidCredentials
+}
+
+// Users kept in the database with their password hash
+type LocalAuthenticator struct{}
+
+func (LocalAuthenticator) Name() string {
+	return models.AuthSourceLocal
+}
+
+func (LocalAuthenticator) Authenticate(db *gorm.DB, ctx context.Context, email string, password string) (models.User, error) {
+	var user models.User
+	res := db.WithContext(ctx).Model(&models.User{}).
+		Where("email = ? AND disabled = ? AND status <> ? AND auth_source = ?", email, false, models.UserStatusInvited, models.AuthSourceLocal).
+		First(&user)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return models.User{}, ErrInvalidCredentials
+	}
+	if res.Error != nil {
+		return models.User{}, res.Error
+	}
+	if !PasswordsMatch(user.Password, password) {
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	// silently upgrading legacy or outdated hashes now that we have the plain password
+	if PasswordNeedsRehash(user.Password) {
+		if hashed_password, err := HashFunc(password); err == nil {
+			db.WithContext(ctx).Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("password", hashed_password)
+		}
+	}
+	return user, nil
+}
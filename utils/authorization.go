@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAuthorizationOtherApp = errors.New("clients can only authorize requests to their own app")
+	ErrAuthorizationTarget   = errors.New("either an endpoint name or a method and path are required")
+	ErrTokenOtherApp         = errors.New("the token was issued for another app")
+	ErrInactiveSubject       = errors.New("unknown or inactive subject")
+)
+
+// Request to the policy decision point, the subject is the token's bearer, the user with the
+// Subject uuid, or anonymous when neither is given. The request is named by its Endpoint or
+// by its Method and Path, IP is the client address the conditions of the grants are checked against.
+type AuthorizationRequest struct {
+	Token    string `json:"token,omitempty"`
+	Subject  string `json:"subject,omitempty"`
+	AppUUID  string `json:"app_uuid,omitempty"`
+	Method   string `json:"method,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	IP       string `json:"ip,omitempty"`
+}
+
+// JWTs are checked by their signature, api keys are looked up in the database
+func ValidateKey(db *gorm.DB, ctx context.Context, key string) (UserClaim, error) {
+	if IsAPIKey(key) {
+		if db == nil {
+			return UserClaim{}, errors.New("no database session to check the api key")
+		}
+		return AuthenticateAPIKey(db, ctx, key)
+	}
+	return ParseJWTToken(key)
+}
+
+// Claims of the active user for the app with the user's current roles in the app, the grants of the
+// roles they inherit are passed on by the matrix
+func appUserClaims(db *gorm.DB, ctx context.Context, app_uuid string, query string, value interface{}) (UserClaim, bool) {
+	tx := db.WithContext(ctx)
+	var user models.User
+	app_ids := tx.Model(&models.App{}).Select("id").Where("uuid = ?", app_uuid)
+	if res := tx.Model(&models.User{}).Preload("Roles", "app_id IN (?)", app_ids).Where(query+" AND disabled = ? AND status = ?", value, false, models.UserStatusActive).First(&user); res.Error != nil {
+		return UserClaim{}, false
+	}
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+	}
+	return UserClaim{Email: user.Email, UUID: user.UUID, UserID: int(user.ID), Roles: roles}, true
+}
+
+// Validates the key for the app and resolves the roles it is authorized with. The route validator and the
+// decision api both use it so a token gets the same decision from either. The roles claim of a user's token
+// is not trusted as is, the user's current roles in the app are used, limited to the key's roles for api
+// keys. Client tokens keep the roles of their app.
+func TokenClaims(db *gorm.DB, ctx context.Context, app_uuid string, key string) (UserClaim, error) {
+	claims, err := ValidateKey(db, ctx, key)
+	if err != nil {
+		return UserClaim{}, err
+	}
+	if !AudienceAllowed(claims, app_uuid) {
+		return UserClaim{}, ErrTokenOtherApp
+	}
+	if claims.ClientID != "" {
+		return claims, nil
+	}
+	if db == nil {
+		return UserClaim{}, errors.New("no database session to load the roles")
+	}
+
+	user_claims, ok := appUserClaims(db, ctx, app_uuid, "id = ?", claims.UserID)
+	if !ok {
+		return UserClaim{}, ErrInactiveSubject
+	}
+	if claims.Subject == APIKeySubject {
+		user_claims.Roles = slices.DeleteFunc(user_claims.Roles, func(role string) bool { return !slices.Contains(claims.Roles, role) })
+	}
+	claims.Roles = user_claims.Roles
+	return claims, nil
+}
+
+// Claims of the request's subject for the app, see TokenClaims
+func authorizationClaims(db *gorm.DB, ctx context.Context, app models.App, request AuthorizationRequest) (UserClaim, string) {
+	switch {
+	case request.Token != "":
+		claims, err := TokenClaims(db, ctx, app.UUID, request.Token)
+		switch {
+		case errors.Is(err, ErrTokenOtherApp), errors.Is(err, ErrInactiveSubject):
+			return UserClaim{}, err.Error()
+		case err != nil:
+			return UserClaim{}, "invalid, expired or revoked token"
+		}
+		return claims, ""
+	case request.Subject != "":
+		claims, ok := appUserClaims(db, ctx, app.UUID, "uuid = ?", request.Subject)
+		if !ok {
+			return UserClaim{}, ErrInactiveSubject.Error()
+		}
+		return claims, ""
+	}
+	return UserClaim{Roles: []string{"Anonymous"}}, ""
+}
+
+// Decides the request to the app with the same matrix and semantics as the route validator
+func Authorize(db *gorm.DB, ctx context.Context, app models.App, request AuthorizationRequest) (AuthorizationDecision, error) {
+	if request.Endpoint == "" && (request.Method == "" || request.Path == "") {
+		return AuthorizationDecision{}, ErrAuthorizationTarget
+	}
+	if request.AppUUID != "" && request.AppUUID != app.UUID {
+		return AuthorizationDecision{}, ErrAuthorizationOtherApp
+	}
+
+	claims, reason := authorizationClaims(db, ctx, app, request)
+	if reason != "" {
+		return AuthorizationDecision{Endpoint: request.Endpoint, Method: strings.ToUpper(request.Method), Reason: reason}, nil
+	}
+
+	condition_request := ConditionRequest{IP: request.IP, Time: time.Now(), Claims: claims}
+	if request.Endpoint != "" {
+		return Permissions.DecideEndpoint(app.UUID, request.Endpoint, claims.Roles, condition_request), nil
+	}
+	path, _, _ := strings.Cut(request.Path, "?")
+	return Permissions.Decide(app.UUID, request.Method, path, claims.Roles, condition_request), nil
+}
+
+// Authorizes the request for an app client, the decision is about the client's own app
+func AuthorizeForClient(db *gorm.DB, ctx context.Context, client_id string, client_secret string, request AuthorizationRequest) (AuthorizationDecision, error) {
+	_, app, _, err := AuthenticateClient(db, ctx, client_id, client_secret)
+	if err != nil {
+		return AuthorizationDecision{}, err
+	}
+	return Authorize(db, ctx, app, request)
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorize(t *testing.T) {
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	app := models.App{Name: "pdp", Description: "decision app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	secret, secret_hash, err := NewClientSecret()
+	require.NoError(t, err)
+	app_client := models.AppClient{Name: "pdp-gateway", SecretHash: secret_hash, Active: true, AppID: app.ID}
+	require.NoError(t, db.Create(&app_client).Error)
+
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+	clerk := models.Role{Name: "pdp_clerk", Description: "clerk", Active: true, AppID: app_id}
+	require.NoError(t, db.Create(&clerk).Error)
+	manager := models.Role{Name: "pdp_manager", Description: "manager", Active: true, AppID: app_id, Parents: []models.Role{clerk}}
+	require.NoError(t, db.Create(&manager).Error)
+	invoices := models.Feature{Name: "pdp_invoices", Description: "invoices", Active: true, Roles: []models.Role{clerk}}
+	require.NoError(t, db.Create(&invoices).Error)
+	feature_id := sql.NullInt64{Int64: int64(invoices.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "pdp_get_invoice", RoutePath: "/api/v1/invoices/:invoice_id", Method: "GET", Description: "invoice", FeatureID: feature_id}).Error)
+	require.NoError(t, db.Model(&models.FeatureRole{}).Where("feature_id = ? AND role_id = ?", invoices.ID, clerk.ID).Update("grant_condition", `ip in "10.0.0.0/8"`).Error)
+
+	user := models.User{Name: "Almaz", Email: "almaz@pdp.com", Password: "secret", Status: models.UserStatusActive, Roles: []models.Role{manager}}
+	require.NoError(t, db.Create(&user).Error)
+
+	token, err := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{app.UUID}}, Email: user.Email, UserID: int(user.ID), Roles: []string{"pdp_clerk"}}, 60)
+	require.NoError(t, err)
+	decision, err := AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Token: token, Method: "get", Path: "/api/v1/invoices/12?expand=lines", IP: "10.2.3.4"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed, decision.Reason)
+	assert.Equal(t, "pdp_manager", decision.Role, "The user's roles in the app should be used, not the token's roles claim")
+	assert.Equal(t, "pdp_invoices", decision.Feature)
+	assert.Equal(t, "pdp_get_invoice", decision.Endpoint)
+	assert.Equal(t, "/api/v1/invoices/:invoice_id", decision.RoutePath)
+
+	decision, err = AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Token: token, Endpoint: "pdp_get_invoice", IP: "192.168.1.4"})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "Requests that do not meet the grant's condition should be denied")
+	assert.Contains(t, decision.Reason, "conditions")
+
+	// subjects get their roles in the app, the manager inherits the clerk's grant
+	decision, err = AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Subject: user.UUID, AppUUID: app.UUID, Endpoint: "pdp_get_invoice", IP: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed, decision.Reason)
+	assert.Equal(t, "pdp_manager", decision.Role)
+	assert.Contains(t, decision.Reason, "inherited from pdp_clerk")
+
+	// a token and its subject get the same decision, roles of other apps and stale claims make no difference
+	outsider := models.Role{Name: "pdp_outsider", Description: "outsider", Active: true}
+	require.NoError(t, db.Create(&outsider).Error)
+	invoices_feature := models.Feature{Name: "pdp_outsider_invoices", Description: "invoices", Active: true, Roles: []models.Role{outsider}}
+	require.NoError(t, db.Create(&invoices_feature).Error)
+	require.NoError(t, db.Create(&models.Endpoint{Name: "pdp_delete_invoice", RoutePath: "/api/v1/invoices/:invoice_id", Method: "DELETE", Description: "invoice", FeatureID: sql.NullInt64{Int64: int64(invoices_feature.ID), Valid: true}}).Error)
+	require.NoError(t, InvalidatePermissions(db, ctx))
+	stale_token, err := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{app.UUID}}, Email: user.Email, UserID: int(user.ID), Roles: []string{"pdp_outsider"}}, 60)
+	require.NoError(t, err)
+	for _, endpoint := range []string{"pdp_get_invoice", "pdp_delete_invoice"} {
+		by_token, err := AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Token: stale_token, Endpoint: endpoint, IP: "10.0.0.1"})
+		require.NoError(t, err)
+		by_subject, err := AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Subject: user.UUID, Endpoint: endpoint, IP: "10.0.0.1"})
+		require.NoError(t, err)
+		assert.Equal(t, by_subject, by_token, "The token and the subject should get the same decision on %v", endpoint)
+	}
+
+	decision, err = AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Method: "GET", Path: "/api/v1/invoices/12", IP: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "Anonymous callers should be denied without a grant")
+
+	other_token, _ := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"another-app"}}, Roles: []string{"pdp_clerk"}}, 60)
+	decision, _ = AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Token: other_token, Endpoint: "pdp_get_invoice", IP: "10.0.0.1"})
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "the token was issued for another app", decision.Reason)
+
+	_, err = AuthorizeForClient(db, ctx, app_client.ClientID, "wrong", AuthorizationRequest{Endpoint: "pdp_get_invoice"})
+	assert.ErrorIs(t, err, ErrClientInvalid)
+	_, err = AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{AppUUID: "another-app", Endpoint: "pdp_get_invoice"})
+	assert.ErrorIs(t, err, ErrAuthorizationOtherApp)
+	_, err = AuthorizeForClient(db, ctx, app_client.ClientID, secret, AuthorizationRequest{Method: "GET"})
+	assert.ErrorIs(t, err, ErrAuthorizationTarget)
+}
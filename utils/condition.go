@@ -0,0 +1,423 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"blue-admin.com/configs"
+)
+
+// Conditions restrict the requests a feature grant allows. They are small boolean expressions
+// combining predicates with &&, || and !, grouped with parentheses:
+//
+//	ip in ["10.0.0.0/8", "192.168.1.10"]
+//	time in "08:00-18:00" && weekday in ["mon", "tue", "wed", "thu", "fri"]
+//	mfa
+//	user.email matches "*@example.com" || user.id in ["1", "7"]
+//
+// ip is the client address, time and weekday are read in CONDITION_TIME_ZONE (default UTC)
+// and a time range ending before it starts spans midnight. mfa is true for tokens that completed
+// a second factor. user.email, user.uuid, user.id and user.client_id are the token's claims,
+// compared with ==, !=, in or matches, a glob pattern.
+type Condition struct {
+	source string
+	root   conditionNode
+}
+
+// Request attributes conditions are evaluated against
+type ConditionRequest struct {
+	IP     string
+	Time   time.Time
+	Claims UserClaim
+}
+
+var ErrConditionSyntax = errors.New("invalid condition")
+
+var conditionWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+var conditionUserAttributes = []string{"user.email", "user.uuid", "user.id", "user.client_id"}
+
+type conditionNode interface {
+	eval(request ConditionRequest) bool
+}
+
+type conditionAnd struct{ left, right conditionNode }
+type conditionOr struct{ left, right conditionNode }
+type conditionNot struct{ node conditionNode }
+type conditionMFA struct{}
+type conditionIP struct{ networks []*net.IPNet }
+type conditionTime struct{ from, to int }
+type conditionWeekday struct{ days []time.Weekday }
+type conditionAttribute struct {
+	attribute string
+	operator  string
+	values    []string
+}
+
+func (node conditionAnd) eval(request ConditionRequest) bool {
+	return node.left.eval(request) && node.right.eval(request)
+}
+
+func (node conditionOr) eval(request ConditionRequest) bool {
+	return node.left.eval(request) || node.right.eval(request)
+}
+
+func (node conditionNot) eval(request ConditionRequest) bool {
+	return !node.node.eval(request)
+}
+
+func (node conditionMFA) eval(request ConditionRequest) bool {
+	return request.Claims.MFA
+}
+
+func (node conditionIP) eval(request ConditionRequest) bool {
+	ip := net.ParseIP(request.IP)
+	if ip == nil {
+		return false
+	}
+	for _, network := range node.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func conditionTimeZone() *time.Location {
+	location, err := time.LoadLocation(configs.AppConfig.GetOrDefault("CONDITION_TIME_ZONE", "UTC"))
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (node conditionTime) eval(request ConditionRequest) bool {
+	local := request.Time.In(conditionTimeZone())
+	minute := local.Hour()*60 + local.Minute()
+	if node.from <= node.to {
+		return minute >= node.from && minute < node.to
+	}
+	return minute >= node.from || minute < node.to
+}
+
+func (node conditionWeekday) eval(request ConditionRequest) bool {
+	return slices.Contains(node.days, request.Time.In(conditionTimeZone()).Weekday())
+}
+
+func (node conditionAttribute) eval(request ConditionRequest) bool {
+	var value string
+	switch node.attribute {
+	case "user.email":
+		value = request.Claims.Email
+	case "user.uuid":
+		value = request.Claims.UUID
+	case "user.id":
+		if request.Claims.UserID != 0 {
+			value = strconv.Itoa(request.Claims.UserID)
+		}
+	case "user.client_id":
+		value = request.Claims.ClientID
+	}
+
+	switch node.operator {
+	case "!=":
+		return value != node.values[0]
+	case "matches":
+		matched, _ := path.Match(node.values[0], value)
+		return value != "" && matched
+	}
+	return value != "" && slices.Contains(node.values, value)
+}
+
+// Parses and validates a condition, an empty condition allows every request and returns nil
+func ParseCondition(source string) (*Condition, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, nil
+	}
+	tokens, err := conditionTokens(source)
+	if err != nil {
+		return nil, err
+	}
+	parser := conditionParser{tokens: tokens}
+	root, err := parser.or()
+	if err != nil {
+		return nil, err
+	}
+	if !parser.done() {
+		return nil, parser.errorf("unexpected %q", parser.peek())
+	}
+	return &Condition{source: source, root: root}, nil
+}
+
+// Whether the request satisfies the condition, a nil condition is always satisfied
+func (condition *Condition) Allows(request ConditionRequest) bool {
+	if condition == nil {
+		return true
+	}
+	return condition.root.eval(request)
+}
+
+func (condition *Condition) String() string {
+	if condition == nil {
+		return ""
+	}
+	return condition.source
+}
+
+// strings are kept quoted so the parser can tell them from names
+func conditionTokens(source string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(source); {
+		char := source[i]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			i++
+		case char == '"':
+			end := strings.IndexByte(source[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string", ErrConditionSyntax)
+			}
+			tokens = append(tokens, source[i:i+end+2])
+			i += end + 2
+		case strings.HasPrefix(source[i:], "&&") || strings.HasPrefix(source[i:], "||") ||
+			strings.HasPrefix(source[i:], "==") || strings.HasPrefix(source[i:], "!="):
+			tokens = append(tokens, source[i:i+2])
+			i += 2
+		case strings.ContainsRune("()[],!", rune(char)):
+			tokens = append(tokens, string(char))
+			i++
+		case char == '_' || char == '.' || char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9':
+			start := i
+			for i < len(source) && (source[i] == '_' || source[i] == '.' || source[i] >= 'a' && source[i] <= 'z' ||
+				source[i] >= 'A' && source[i] <= 'Z' || source[i] >= '0' && source[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, strings.ToLower(source[start:i]))
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q", ErrConditionSyntax, char)
+		}
+	}
+	return tokens, nil
+}
+
+type conditionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (parser *conditionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %v", ErrConditionSyntax, fmt.Sprintf(format, args...))
+}
+
+func (parser *conditionParser) done() bool {
+	return parser.pos >= len(parser.tokens)
+}
+
+func (parser *conditionParser) peek() string {
+	if parser.done() {
+		return ""
+	}
+	return parser.tokens[parser.pos]
+}
+
+func (parser *conditionParser) next() string {
+	token := parser.peek()
+	parser.pos++
+	return token
+}
+
+func (parser *conditionParser) expect(token string) error {
+	if parser.done() {
+		return parser.errorf("expected %q at the end", token)
+	}
+	if got := parser.next(); got != token {
+		return parser.errorf("expected %q, got %q", token, got)
+	}
+	return nil
+}
+
+func (parser *conditionParser) or() (conditionNode, error) {
+	left, err := parser.and()
+	if err != nil {
+		return nil, err
+	}
+	for parser.peek() == "||" {
+		parser.next()
+		right, err := parser.and()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionOr{left, right}
+	}
+	return left, nil
+}
+
+func (parser *conditionParser) and() (conditionNode, error) {
+	left, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+	for parser.peek() == "&&" {
+		parser.next()
+		right, err := parser.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionAnd{left, right}
+	}
+	return left, nil
+}
+
+func (parser *conditionParser) unary() (conditionNode, error) {
+	switch parser.peek() {
+	case "!":
+		parser.next()
+		node, err := parser.unary()
+		if err != nil {
+			return nil, err
+		}
+		return conditionNot{node}, nil
+	case "(":
+		parser.next()
+		node, err := parser.or()
+		if err != nil {
+			return nil, err
+		}
+		return node, parser.expect(")")
+	}
+	return parser.predicate()
+}
+
+// a single quoted string or a bracketed list of them
+func (parser *conditionParser) values() ([]string, error) {
+	if parser.peek() != "[" {
+		value, err := parser.value()
+		return []string{value}, err
+	}
+	parser.next()
+	values := make([]string, 0)
+	for {
+		value, err := parser.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if parser.peek() != "," {
+			break
+		}
+		parser.next()
+	}
+	return values, parser.expect("]")
+}
+
+func (parser *conditionParser) value() (string, error) {
+	token := parser.next()
+	if len(token) < 2 || !strings.HasPrefix(token, `"`) {
+		return "", parser.errorf("expected a quoted value, got %q", token)
+	}
+	return token[1 : len(token)-1], nil
+}
+
+func (parser *conditionParser) predicate() (conditionNode, error) {
+	if parser.done() {
+		return nil, parser.errorf("unexpected end of condition")
+	}
+	name := parser.next()
+	switch {
+	case name == "mfa":
+		return conditionMFA{}, nil
+	case name == "ip":
+		if err := parser.expect("in"); err != nil {
+			return nil, err
+		}
+		values, err := parser.values()
+		if err != nil {
+			return nil, err
+		}
+		networks := make([]*net.IPNet, 0, len(values))
+		for _, value := range values {
+			// single addresses are networks of their own
+			if !strings.Contains(value, "/") {
+				if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+					value += "/32"
+				} else {
+					value += "/128"
+				}
+			}
+			_, network, err := net.ParseCIDR(value)
+			if err != nil {
+				return nil, parser.errorf("invalid ip range %q", value)
+			}
+			networks = append(networks, network)
+		}
+		return conditionIP{networks}, nil
+	case name == "time":
+		if err := parser.expect("in"); err != nil {
+			return nil, err
+		}
+		value, err := parser.value()
+		if err != nil {
+			return nil, err
+		}
+		from, to, found := strings.Cut(value, "-")
+		from_minute, from_err := conditionMinute(from)
+		to_minute, to_err := conditionMinute(to)
+		if !found || from_err != nil || to_err != nil || from_minute == to_minute {
+			return nil, parser.errorf("invalid time range %q, expected HH:MM-HH:MM", value)
+		}
+		return conditionTime{from_minute, to_minute}, nil
+	case name == "weekday":
+		if err := parser.expect("in"); err != nil {
+			return nil, err
+		}
+		values, err := parser.values()
+		if err != nil {
+			return nil, err
+		}
+		days := make([]time.Weekday, 0, len(values))
+		for _, value := range values {
+			day := slices.Index(conditionWeekdays, strings.ToLower(value))
+			if day < 0 {
+				return nil, parser.errorf("invalid weekday %q, expected one of %v", value, strings.Join(conditionWeekdays, ", "))
+			}
+			days = append(days, time.Weekday(day))
+		}
+		return conditionWeekday{days}, nil
+	case slices.Contains(conditionUserAttributes, name):
+		operator := parser.next()
+		switch operator {
+		case "==", "!=", "matches":
+			value, err := parser.value()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := path.Match(value, ""); operator == "matches" && err != nil {
+				return nil, parser.errorf("invalid pattern %q", value)
+			}
+			return conditionAttribute{name, operator, []string{value}}, nil
+		case "in":
+			values, err := parser.values()
+			if err != nil {
+				return nil, err
+			}
+			return conditionAttribute{name, operator, values}, nil
+		}
+		return nil, parser.errorf("expected ==, !=, in or matches after %v, got %q", name, operator)
+	}
+	return nil, parser.errorf("unknown attribute %q, expected ip, time, weekday, mfa or one of %v", name, strings.Join(conditionUserAttributes, ", "))
+}
+
+// minutes since midnight of a HH:MM time
+func conditionMinute(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCondition(t *testing.T) {
+	condition, err := ParseCondition("")
+	require.NoError(t, err)
+	assert.Nil(t, condition, "An empty condition should allow every request")
+	assert.True(t, condition.Allows(ConditionRequest{}))
+
+	for _, source := range []string{
+		`ip in "10.0.0.0/33"`,
+		`time in "8-18"`,
+		`weekday in ["monday"]`,
+		`user.name == "x"`,
+		`mfa &&`,
+		`(mfa`,
+		`ip in ["10.0.0.1",]`,
+		`user.email == unquoted`,
+		`mfa "extra"`,
+	} {
+		_, err := ParseCondition(source)
+		assert.ErrorIs(t, err, ErrConditionSyntax, source)
+	}
+}
+
+func TestConditionAllows(t *testing.T) {
+	// Monday 2024-01-08 09:30 UTC
+	monday := time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC)
+	request := ConditionRequest{IP: "10.1.2.3", Time: monday, Claims: UserClaim{Email: "abebe@example.com", UserID: 7, MFA: true}}
+
+	cases := map[string]bool{
+		`ip in "10.0.0.0/8"`:                                      true,
+		`ip in ["192.168.0.0/16", "10.1.2.3"]`:                    true,
+		`ip in "192.168.0.0/16"`:                                  false,
+		`time in "08:00-18:00"`:                                   true,
+		`time in "22:00-06:00"`:                                   false,
+		`weekday in ["sat", "sun"]`:                               false,
+		`weekday in ["mon", "tue"] && mfa`:                        true,
+		`!mfa`:                                                    false,
+		`user.email matches "*@example.com"`:                      true,
+		`user.id in ["1", "7"]`:                                   true,
+		`user.client_id == ""`:                                    false,
+		`user.email != "abebe@example.com" || mfa`:                true,
+		`(ip in "172.16.0.0/12" || mfa) && time in "09:00-10:00"`: true,
+	}
+	for source, allowed := range cases {
+		condition, err := ParseCondition(source)
+		require.NoError(t, err, source)
+		assert.Equal(t, allowed, condition.Allows(request), source)
+	}
+
+	overnight, _ := ParseCondition(`time in "22:00-06:00"`)
+	assert.True(t, overnight.Allows(ConditionRequest{Time: time.Date(2024, 1, 8, 23, 0, 0, 0, time.UTC)}), "Ranges should span midnight")
+
+	t.Setenv("CONDITION_TIME_ZONE", "Africa/Addis_Ababa")
+	morning, _ := ParseCondition(`time in "12:00-13:00"`)
+	assert.True(t, morning.Allows(request), "Times should be read in the configured time zone")
+}
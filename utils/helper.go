@@ -3,96 +3,180 @@ package utils
 import (
 	"context"
 	"log"
+	"slices"
+	"strings"
 
-	"blue-admin.com/configs"
 	"blue-admin.com/database"
-	"blue-admin.com/models"
 	"gorm.io/gorm"
 )
 
 type ResourceMatrix struct {
-	Name      string `gorm:"not null; unique;" json:"name,omitempty"`
-	RoleName  string `gorm:"not null; unique;" json:"role_name,omitempty"`
-	RoutePath string `gorm:"not null; unique;" json:"route_path,omitempty"`
+	Name        string `gorm:"not null; unique;" json:"name,omitempty"`
+	RoleName    string `gorm:"not null; unique;" json:"role_name,omitempty"`
+	RoutePath   string `gorm:"not null; unique;" json:"route_path,omitempty"`
+	Method      string `gorm:"not null;" json:"method,omitempty"`
+	FeatureName string `json:"feature_name,omitempty"`
+	Condition   string `gorm:"column:grant_condition;" json:"condition,omitempty"`
+	Granted     bool   `json:"granted,omitempty"`
 }
 
-var Endpoints_JSON = make(map[string]string)
+// Use of an endpoint by a role through a feature, requests have to meet the condition when one is set.
+// InheritedFrom names the role holding the grant when the role has it by inheriting that role.
+type RouteGrant struct {
+	Role          string `json:"role"`
+	Feature       string `json:"feature"`
+	Condition     string `json:"condition,omitempty"`
+	InheritedFrom string `json:"inherited_from,omitempty"`
+}
+
+// Grants on the endpoints sharing a method and route path template, Roles lists every role with a grant
+type RouteRoles struct {
+	Method    string       `json:"method"`
+	RoutePath string       `json:"route_path"`
+	Endpoints []string     `json:"endpoints"`
+	Roles     []string     `json:"roles"`
+	Grants    []RouteGrant `json:"grants"`
+}
+
+// Adds the grant unless the role already has the feature under the same condition
+func (route *RouteRoles) grant(grant RouteGrant) {
+	for _, existing := range route.Grants {
+		if existing.Role == grant.Role && existing.Feature == grant.Feature && existing.Condition == grant.Condition {
+			return
+		}
+	}
+	route.Roles = appendRole(route.Roles, grant.Role)
+	route.Grants = append(route.Grants, grant)
+}
 
+// Loads the permission matrix of this deployment's app at startup, see Permissions
 func GetAppFeatures() {
-	app_uuid := configs.AppConfig.Get("APP_ID")
 	db, _ := database.ReturnSession()
-	var role_matrix []ResourceMatrix
+	if err := Permissions.Reload(db, context.Background()); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+// an endpoint reached through several features of the same role lists the role once
+func appendRole(roles []string, role string) []string {
+	if slices.Contains(roles, role) {
+		return roles
+	}
+	return append(roles, role)
+}
+
+// Endpoint names of the app mapped to every role allowed on them, roles inheriting an allowed role included
+func GetAppFeaturesReturn(app_uuid string, db *gorm.DB, ctx context.Context) (map[string][]string, error) {
+
+	var role_matrix_list []ResourceMatrix
 
 	query_string := `SELECT endpoints.name,roles.name as role_name FROM apps
 		INNER JOIN roles ON apps.id = roles.app_id
-		INNER JOIN features ON features.role_id = roles.id
+		INNER JOIN feature_roles ON feature_roles.role_id = roles.id
+		INNER JOIN features ON features.id = feature_roles.feature_id
 		INNER JOIN endpoints ON features.id = endpoints.feature_id
 		WHERE apps.uuid = ?
 		  AND apps.active = true
 		  AND roles.active = true
 		  AND features.active = true
-					ORDER BY apps.id`
-	if res := db.Model(&models.App{}).Raw(query_string, app_uuid).Scan(&role_matrix); res.Error != nil {
-		log.Fatal(res.Error.Error())
-	}
+					ORDER BY apps.id, roles.name`
+	// app_id, _ := uuid.Parse(app_uuid)
+	// if res := db.WithContext(ctx).Model(&models.App{}).Preload(clause.Associations).Preload("Roles.Features").Where("active = ?", true).Preload("Roles.Features.Endpoints").Where("uuid = ?", app_uuid).First(&app); res.Error != nil {
+	if res := db.WithContext(ctx).Raw(query_string, app_uuid).Scan(&role_matrix_list); res.Error != nil {
 
-	for _, value := range role_matrix {
-		Endpoints_JSON[value.Name] = value.RoleName
+		return nil, res.Error
+	}
+	role_matrix := make(map[string][]string)
 
+	for _, value := range role_matrix_list {
+		role_matrix[value.Name] = appendRole(role_matrix[value.Name], value.RoleName)
+	}
+	if err := inheritRoleMatrix(db, ctx, app_uuid, role_matrix); err != nil {
+		return nil, err
 	}
+
+	return role_matrix, nil
 }
 
-func GetAppFeaturesReturn(app_uuid string, db *gorm.DB, ctx context.Context) (map[string]string, error) {
+// Route paths of the app mapped to every role allowed on them, roles inheriting an allowed role included
+func GetAppFeaturesReturnPath(app_uuid string, db *gorm.DB, ctx context.Context) (map[string][]string, error) {
 
 	var role_matrix_list []ResourceMatrix
 
-	query_string := `SELECT endpoints.name,roles.name as role_name FROM apps
+	query_string := `SELECT endpoints.route_path,roles.name as role_name FROM apps
 		INNER JOIN roles ON apps.id = roles.app_id
-		INNER JOIN features ON features.role_id = roles.id
+		INNER JOIN feature_roles ON feature_roles.role_id = roles.id
+		INNER JOIN features ON features.id = feature_roles.feature_id
 		INNER JOIN endpoints ON features.id = endpoints.feature_id
 		WHERE apps.uuid = ?
 		  AND apps.active = true
 		  AND roles.active = true
 		  AND features.active = true
-					ORDER BY apps.id`
+					ORDER BY apps.id, roles.name`
 	// app_id, _ := uuid.Parse(app_uuid)
 	// if res := db.WithContext(ctx).Model(&models.App{}).Preload(clause.Associations).Preload("Roles.Features").Where("active = ?", true).Preload("Roles.Features.Endpoints").Where("uuid = ?", app_uuid).First(&app); res.Error != nil {
 	if res := db.WithContext(ctx).Raw(query_string, app_uuid).Scan(&role_matrix_list); res.Error != nil {
 
 		return nil, res.Error
 	}
-	role_matrix := make(map[string]string)
+	role_matrix := make(map[string][]string)
 
 	for _, value := range role_matrix_list {
-		role_matrix[value.Name] = value.RoleName
+		role_matrix[value.RoutePath] = appendRole(role_matrix[value.RoutePath], value.RoleName)
+	}
+	if err := inheritRoleMatrix(db, ctx, app_uuid, role_matrix); err != nil {
+		return nil, err
 	}
 
 	return role_matrix, nil
 }
-func GetAppFeaturesReturnPath(app_uuid string, db *gorm.DB, ctx context.Context) (map[string]string, error) {
+
+// Method and route path templates of the app's endpoints, those of features granted to a role of the app,
+// with the grants of every role allowed on them, roles inheriting an allowed role included. Endpoints whose
+// grants are all on deactivated roles or features are listed without grants so they deny rather than fall
+// through to a wildcard endpoint of the app matching them.
+func GetAppRoutesReturn(app_uuid string, db *gorm.DB, ctx context.Context) ([]RouteRoles, error) {
 
 	var role_matrix_list []ResourceMatrix
 
-	query_string := `SELECT endpoints.route_path,roles.name as role_name FROM apps
+	query_string := `SELECT endpoints.name,endpoints.method,endpoints.route_path,features.name as feature_name,roles.name as role_name,feature_roles.grant_condition,
+		roles.active AND features.active as granted FROM apps
 		INNER JOIN roles ON apps.id = roles.app_id
-		INNER JOIN features ON features.role_id = roles.id
+		INNER JOIN feature_roles ON feature_roles.role_id = roles.id
+		INNER JOIN features ON features.id = feature_roles.feature_id
 		INNER JOIN endpoints ON features.id = endpoints.feature_id
 		WHERE apps.uuid = ?
 		  AND apps.active = true
-		  AND roles.active = true
-		  AND features.active = true
-					ORDER BY apps.id`
-	// app_id, _ := uuid.Parse(app_uuid)
-	// if res := db.WithContext(ctx).Model(&models.App{}).Preload(clause.Associations).Preload("Roles.Features").Where("active = ?", true).Preload("Roles.Features.Endpoints").Where("uuid = ?", app_uuid).First(&app); res.Error != nil {
+					ORDER BY apps.id, endpoints.route_path, endpoints.method, roles.name, features.name, endpoints.name`
 	if res := db.WithContext(ctx).Raw(query_string, app_uuid).Scan(&role_matrix_list); res.Error != nil {
 
 		return nil, res.Error
 	}
-	role_matrix := make(map[string]string)
 
+	// endpoints registered under several names share one entry
+	route_index := make(map[string]int)
+	routes := make([]RouteRoles, 0)
 	for _, value := range role_matrix_list {
-		role_matrix[value.RoutePath] = value.RoleName
+		key := strings.ToUpper(value.Method) + " " + value.RoutePath
+		index, ok := route_index[key]
+		if !ok {
+			index = len(routes)
+			route_index[key] = index
+			routes = append(routes, RouteRoles{Method: strings.ToUpper(value.Method), RoutePath: value.RoutePath})
+		}
+		routes[index].Endpoints = appendRole(routes[index].Endpoints, value.Name)
+		if value.Granted {
+			routes[index].grant(RouteGrant{Role: value.RoleName, Feature: value.FeatureName, Condition: value.Condition})
+		}
 	}
 
-	return role_matrix, nil
+	inheritors, err := roleInheritors(db, ctx, app_uuid)
+	if err != nil {
+		return nil, err
+	}
+	for index := range routes {
+		inheritGrants(&routes[index], inheritors)
+	}
+
+	return routes, nil
 }
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureRoleMatrix(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.Role{}, &models.Feature{}, &models.Endpoint{})
+	ctx := context.Background()
+
+	app := models.App{Name: "matrix", Description: "matrix app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+	editor := models.Role{Name: "editor", Description: "editor", Active: true, AppID: app_id}
+	viewer := models.Role{Name: "viewer", Description: "viewer", Active: true, AppID: app_id}
+	require.NoError(t, db.Create(&editor).Error)
+	require.NoError(t, db.Create(&viewer).Error)
+
+	reports := models.Feature{Name: "reports", Description: "reports", Active: true, Roles: []models.Role{editor, viewer}}
+	require.NoError(t, db.Create(&reports).Error)
+	feature_id := sql.NullInt64{Int64: int64(reports.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "get_reports_get", RoutePath: "/api/v1/reports", Method: "GET", Description: "reports", FeatureID: feature_id}).Error)
+
+	matrix, err := GetAppFeaturesReturn(app.UUID, db, ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"editor", "viewer"}, matrix["get_reports_get"], "Every role of the feature should be allowed")
+	path_matrix, err := GetAppFeaturesReturnPath(app.UUID, db, ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"editor", "viewer"}, path_matrix["/api/v1/reports"])
+
+	require.NoError(t, db.Model(&reports).Association("Roles").Delete(&viewer))
+	matrix, _ = GetAppFeaturesReturn(app.UUID, db, ctx)
+	assert.Equal(t, []string{"editor"}, matrix["get_reports_get"], "Removed roles should no longer be allowed")
+
+	assert.True(t, CheckAnyValueExistsInSlice([]string{"viewer", "editor"}, matrix["get_reports_get"]), "Any of the roles should pass")
+	assert.False(t, CheckAnyValueExistsInSlice([]string{"viewer"}, matrix["get_reports_get"]))
+	assert.True(t, CheckAnyValueExistsInSlice([]string{"superuser"}, nil), "Superuser should pass everywhere")
+}
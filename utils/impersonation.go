@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/messages"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const ImpersonationSubject = "Impersonation Token"
+
+// Security event types of impersonation
+const (
+	EventImpersonationStarted = "IMPERSONATION_STARTED"
+	EventImpersonatedRequest  = "IMPERSONATED_REQUEST"
+)
+
+var (
+	ErrImpersonationNotAllowed = errors.New("the token's user is not allowed to impersonate")
+	ErrImpersonationTarget     = errors.New("the user can not be impersonated")
+	ErrImpersonationRefused    = errors.New("impersonation tokens are not accepted here")
+)
+
+// Roles allowed to impersonate from IMPERSONATION_ROLES, a comma separated list defaulting to superuser
+func ImpersonationRoles() []string {
+	roles := make([]string, 0, 2)
+	for _, role := range strings.Split(configs.AppConfig.GetOrDefault("IMPERSONATION_ROLES", "superuser"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// Impersonation token lifetime in minutes from IMPERSONATION_LIFE_TIME, defaults to 15 minutes
+func impersonationLifeTime() int {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("IMPERSONATION_LIFE_TIME", "15"))
+	if life_time <= 0 {
+		life_time = 15
+	}
+	return life_time
+}
+
+func holdsImpersonationRole(roles []models.Role) bool {
+	allowed := ImpersonationRoles()
+	for _, role := range roles {
+		if role.Active && slices.Contains(allowed, role.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Issues a short lived access token for the target user, without a refresh token. The actor's
+// current roles are checked rather than the token's, impersonation tokens can not be chained
+// and privileged users can not be impersonated. The token is only valid for blue-admin's api and
+// carries the target's roles of its app. It stays in the actor's session so signing the actor out ends it too.
+func ImpersonateUser(db *gorm.DB, ctx context.Context, actor_claims UserClaim, target_id uint, reason string, ip string) (string, models.Impersonation, error) {
+	if actor_claims.UserID == 0 || actor_claims.ClientID != "" || actor_claims.Actor != nil {
+		return "", models.Impersonation{}, ErrImpersonationNotAllowed
+	}
+
+	var actor models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Preload("Roles").Where("id = ? AND disabled = ?", actor_claims.UserID, false).First(&actor); res.Error != nil {
+		return "", models.Impersonation{}, ErrImpersonationNotAllowed
+	}
+	if !holdsImpersonationRole(actor.Roles) {
+		return "", models.Impersonation{}, ErrImpersonationNotAllowed
+	}
+
+	var target models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Preload("Roles").Where("id = ? AND disabled = ? AND status <> ?", target_id, false, models.UserStatusInvited).First(&target); res.Error != nil {
+		return "", models.Impersonation{}, ErrImpersonationTarget
+	}
+	if target.ID == actor.ID || holdsImpersonationRole(target.Roles) {
+		return "", models.Impersonation{}, ErrImpersonationTarget
+	}
+
+	app, err := FindActiveApp(db, ctx, BlueAdminAudience())
+	if err != nil {
+		return "", models.Impersonation{}, err
+	}
+
+	life_time := impersonationLifeTime()
+	impersonation := models.Impersonation{
+		TokenID:    newTokenID(),
+		ActorID:    actor.ID,
+		ActorEmail: actor.Email,
+		UserID:     target.ID,
+		UserEmail:  target.Email,
+		Reason:     reason,
+		IP:         ip,
+		ExpiresAt:  time.Now().UTC().Add(time.Duration(life_time) * time.Minute),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := db.WithContext(ctx).Create(&impersonation).Error; err != nil {
+		return "", models.Impersonation{}, err
+	}
+
+	token, err := CreateClaimJWTToken(UserClaim{
+		RegisteredClaims: jwt.RegisteredClaims{ID: impersonation.TokenID, Subject: ImpersonationSubject, Audience: jwt.ClaimStrings{app.UUID}},
+		Email:            target.Email,
+		Roles:            AppRoleNames(target.Roles, app.ID),
+		UUID:             target.UUID,
+		UserID:           int(target.ID),
+		SessionID:        actor_claims.SessionID,
+		Actor: &ActorClaim{
+			Subject: actor.UUID,
+			Email:   actor.Email,
+			UserID:  int(actor.ID),
+		},
+	}, life_time)
+	if err != nil {
+		return "", models.Impersonation{}, err
+	}
+
+	EmitSecurityEvent(messages.SecurityEvent{
+		Type:   EventImpersonationStarted,
+		UserID: target.ID,
+		Email:  target.Email,
+		IP:     ip,
+		Details: map[string]string{
+			"actor_id":    strconv.Itoa(int(actor.ID)),
+			"actor_email": actor.Email,
+			"token_id":    impersonation.TokenID,
+			"reason":      reason,
+			"expires_at":  impersonation.ExpiresAt.Format(time.RFC3339),
+		},
+	})
+	return token, impersonation, nil
+}
+
+// Publishes a request made with an impersonation token against both the actor and the impersonated user
+func AuditImpersonatedRequest(claims UserClaim, method string, path string, ip string, allowed bool) {
+	if claims.Actor == nil {
+		return
+	}
+	EmitSecurityEvent(messages.SecurityEvent{
+		Type:   EventImpersonatedRequest,
+		UserID: uint(claims.UserID),
+		Email:  claims.Email,
+		IP:     ip,
+		Details: map[string]string{
+			"actor_id":    strconv.Itoa(claims.Actor.UserID),
+			"actor_email": claims.Actor.Email,
+			"token_id":    claims.ID,
+			"method":      method,
+			"path":        path,
+			"allowed":     strconv.FormatBool(allowed),
+		},
+	})
+}
+
+// Impersonations started by or targeting the user, newest first
+func UserImpersonations(db *gorm.DB, ctx context.Context, user_id uint) ([]models.Impersonation, error) {
+	impersonations := []models.Impersonation{}
+	err := db.WithContext(ctx).Model(&models.Impersonation{}).Where("actor_id = ? OR user_id = ?", user_id, user_id).Order("id desc").Find(&impersonations).Error
+	return impersonations, err
+}
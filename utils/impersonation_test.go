@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonation(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.User{}, &models.Role{}, &models.Impersonation{}, &models.Session{}, &models.RefreshToken{}, &models.UserRevocation{})
+	ctx := context.Background()
+	t.Setenv("IMPERSONATION_ROLES", "support, superuser")
+
+	// keeping the cache from reloading out of the memory database
+	Revocations.mu.Lock()
+	Revocations.loaded_at = time.Now()
+	Revocations.mu.Unlock()
+
+	app := models.App{Name: "blue-admin", Description: "admin", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	t.Setenv("APP_ID", app.UUID)
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+
+	support := models.Role{Name: "support", Description: "support", Active: true, AppID: app_id}
+	reader := models.Role{Name: "reader", Description: "reader", Active: true, AppID: app_id}
+	billing := models.Role{Name: "billing_reader", Description: "reader of another app", Active: true}
+	require.NoError(t, db.Create(&support).Error)
+	require.NoError(t, db.Create(&reader).Error)
+	require.NoError(t, db.Create(&billing).Error)
+	actor := models.User{Name: "actor", Email: "actor@mail.com", Password: "password", Roles: []models.Role{support}}
+	target := models.User{Name: "target", Email: "target@mail.com", Password: "password", Roles: []models.Role{reader, billing}}
+	other := models.User{Name: "other", Email: "other@mail.com", Password: "password", Roles: []models.Role{support}}
+	plain := models.User{Name: "plain", Email: "plain@mail.com", Password: "password", Roles: []models.Role{reader}}
+	for _, user := range []*models.User{&actor, &target, &other, &plain} {
+		require.NoError(t, db.Create(user).Error)
+	}
+	actor_claims := UserClaim{Email: actor.Email, UserID: int(actor.ID), Roles: []string{"support"}, SessionID: "actor-session"}
+
+	_, _, err := ImpersonateUser(db, ctx, UserClaim{Email: plain.Email, UserID: int(plain.ID), Roles: []string{"support"}}, target.ID, "ticket", "")
+	assert.ErrorIs(t, err, ErrImpersonationNotAllowed, "The actor's current roles should be checked, not the token's")
+	_, _, err = ImpersonateUser(db, ctx, actor_claims, other.ID, "ticket", "")
+	assert.ErrorIs(t, err, ErrImpersonationTarget, "Privileged users should not be impersonated")
+	_, _, err = ImpersonateUser(db, ctx, actor_claims, actor.ID, "ticket", "")
+	assert.ErrorIs(t, err, ErrImpersonationTarget)
+
+	token, impersonation, err := ImpersonateUser(db, ctx, actor_claims, target.ID, "ticket 42", "10.0.0.1")
+	require.NoError(t, err, "Impersonating should not return an error")
+	assert.Equal(t, "ticket 42", impersonation.Reason)
+	assert.WithinDuration(t, time.Now().UTC().Add(15*time.Minute), impersonation.ExpiresAt, time.Minute)
+
+	claims, err := ParseJWTToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int(target.ID), claims.UserID, "The token should be the target's")
+	assert.Equal(t, []string{"reader"}, claims.Roles, "The token should carry the target's roles of blue-admin's app")
+	assert.True(t, AudienceAllowed(claims, app.UUID), "The token should be scoped to blue-admin's app")
+	assert.Equal(t, impersonation.TokenID, claims.ID)
+	assert.Equal(t, "actor-session", claims.SessionID)
+	require.NotNil(t, claims.Actor, "The actor should be named in the act claim")
+	assert.Equal(t, int(actor.ID), claims.Actor.UserID)
+	assert.Equal(t, actor.Email, claims.Actor.Email)
+
+	_, _, err = ImpersonateUser(db, ctx, claims, plain.ID, "chained", "")
+	assert.ErrorIs(t, err, ErrImpersonationNotAllowed, "Impersonation tokens should not impersonate again")
+
+	impersonations, err := UserImpersonations(db, ctx, actor.ID)
+	require.NoError(t, err)
+	assert.Len(t, impersonations, 1)
+	impersonations, err = UserImpersonations(db, ctx, target.ID)
+	require.NoError(t, err)
+	assert.Len(t, impersonations, 1)
+
+	// signing the actor out everywhere ends the impersonation too
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	require.NoError(t, RevokeUserTokens(db, ctx, actor.ID))
+	_, err = ParseJWTToken(token)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+// Introspection Response of RFC 7662, only active is set for tokens that are not active
+type TokenIntrospection struct {
+	Active    bool        `json:"active"`
+	Scope     string      `json:"scope,omitempty"`
+	Roles     []string    `json:"roles,omitempty"`
+	ClientID  string      `json:"client_id,omitempty"`
+	Username  string      `json:"username,omitempty"`
+	TokenType string      `json:"token_type,omitempty"`
+	Exp       int64       `json:"exp,omitempty"`
+	Iat       int64       `json:"iat,omitempty"`
+	Sub       string      `json:"sub,omitempty"`
+	Aud       []string    `json:"aud,omitempty"`
+	Iss       string      `json:"iss,omitempty"`
+	Jti       string      `json:"jti,omitempty"`
+	SessionID string      `json:"sid,omitempty"`
+	MFA       bool        `json:"mfa,omitempty"`
+	Act       *ActorClaim `json:"act,omitempty"`
+}
+
+// Reports the state of an access token, api key or refresh token to the app of an authenticated client.
+// Besides the signature and expiry the revocations, the user or client still being enabled and
+// the audience are checked, a token with an audience not naming the app is reported inactive.
+func IntrospectToken(db *gorm.DB, ctx context.Context, app models.App, token string) TokenIntrospection {
+	switch {
+	case IsAPIKey(token):
+		claims, err := AuthenticateAPIKey(db, ctx, token)
+		if err != nil {
+			return TokenIntrospection{}
+		}
+		return userIntrospection(claims, "Bearer")
+	case strings.Count(token, ".") == 2:
+		return introspectAccessToken(db, ctx, app, token)
+	default:
+		return introspectRefreshToken(db, ctx, token)
+	}
+}
+
+func userIntrospection(claims UserClaim, token_type string) TokenIntrospection {
+	introspection := TokenIntrospection{
+		Active:    true,
+		Scope:     strings.Join(claims.Roles, " "),
+		Roles:     claims.Roles,
+		ClientID:  claims.ClientID,
+		Username:  claims.Email,
+		TokenType: token_type,
+		Sub:       claims.UUID,
+		Aud:       claims.Audience,
+		Iss:       claims.Issuer,
+		Jti:       claims.ID,
+		SessionID: claims.SessionID,
+		MFA:       claims.MFA,
+		Act:       claims.Actor,
+	}
+	if claims.ExpiresAt != nil {
+		introspection.Exp = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		introspection.Iat = claims.IssuedAt.Unix()
+	}
+	return introspection
+}
+
+func introspectAccessToken(db *gorm.DB, ctx context.Context, app models.App, token string) TokenIntrospection {
+	claims, err := ParseJWTToken(token)
+	if err != nil {
+		return TokenIntrospection{}
+	}
+	if !AudienceAllowed(claims, app.UUID) {
+		return TokenIntrospection{}
+	}
+
+	if claims.ClientID != "" {
+		if _, _, err := FindClient(db, ctx, claims.ClientID); err != nil {
+			return TokenIntrospection{}
+		}
+		introspection := userIntrospection(claims, "Bearer")
+		introspection.Sub = claims.ClientID
+		introspection.Username = ""
+		return introspection
+	}
+
+	var user models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND disabled = ? AND status <> ?", claims.UserID, false, models.UserStatusInvited).First(&user); res.Error != nil {
+		return TokenIntrospection{}
+	}
+	return userIntrospection(claims, "Bearer")
+}
+
+// refresh tokens are opaque, their record is read without using them up
+func introspectRefreshToken(db *gorm.DB, ctx context.Context, token string) TokenIntrospection {
+	var refresh_token models.RefreshToken
+	if res := db.WithContext(ctx).Model(&models.RefreshToken{}).Where("token_hash = ? AND revoked = ? AND used_at IS NULL AND expires_at > ?", HashOpaqueToken(token), false, time.Now().UTC()).First(&refresh_token); res.Error != nil {
+		return TokenIntrospection{}
+	}
+	if Revocations.IsSessionRevoked(refresh_token.FamilyID) {
+		return TokenIntrospection{}
+	}
+
+	var user models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND disabled = ? AND status <> ?", refresh_token.UserID, false, models.UserStatusInvited).First(&user); res.Error != nil {
+		return TokenIntrospection{}
+	}
+	return TokenIntrospection{
+		Active:    true,
+		Username:  user.Email,
+		TokenType: "refresh_token",
+		Exp:       refresh_token.ExpiresAt.Unix(),
+		Iat:       refresh_token.CreatedAt.Unix(),
+		Sub:       user.UUID,
+		Iss:       "Blue Admin",
+		SessionID: refresh_token.FamilyID,
+		MFA:       refresh_token.MFA,
+	}
+}
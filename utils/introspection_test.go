@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospectToken(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.Role{}, &models.AppClient{}, &models.User{}, &models.RefreshToken{})
+	ctx := context.Background()
+
+	app := models.App{Name: "billing", Description: "billing service", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	app_client := models.AppClient{Name: "worker", SecretHash: "hash", Active: true, AppID: app.ID}
+	require.NoError(t, db.Create(&app_client).Error)
+	user := models.User{Name: "u", Email: "u@mail.com", Password: "password"}
+	require.NoError(t, db.Create(&user).Error)
+
+	access, err := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{app.UUID}}, Email: user.Email, UUID: user.UUID, UserID: int(user.ID), Roles: []string{"reader", "writer"}, SessionID: "family"}, 60)
+	require.NoError(t, err)
+	introspection := IntrospectToken(db, ctx, app, access)
+	assert.True(t, introspection.Active, "A valid access token should be active")
+	assert.Equal(t, user.UUID, introspection.Sub)
+	assert.Equal(t, "reader writer", introspection.Scope)
+	assert.Equal(t, user.Email, introspection.Username)
+	assert.Equal(t, "family", introspection.SessionID)
+	assert.NotZero(t, introspection.Exp)
+
+	assert.Equal(t, TokenIntrospection{}, IntrospectToken(db, ctx, app, access+"x"), "Tampered tokens should only be inactive")
+
+	other_audience, err := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"another-app"}}, Email: user.Email, UserID: int(user.ID)}, 60)
+	require.NoError(t, err)
+	assert.False(t, IntrospectToken(db, ctx, app, other_audience).Active, "Tokens for another app should be inactive")
+	no_audience, err := CreateClaimJWTToken(UserClaim{Email: user.Email, UserID: int(user.ID)}, 60)
+	require.NoError(t, err)
+	assert.False(t, IntrospectToken(db, ctx, app, no_audience).Active, "Tokens without an audience should be inactive")
+	own_audience, err := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{app.UUID}}, Email: user.Email, UserID: int(user.ID)}, 60)
+	require.NoError(t, err)
+	introspection = IntrospectToken(db, ctx, app, own_audience)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, []string{app.UUID}, introspection.Aud)
+
+	client_token, err := CreateClientJWTToken(app_client.ClientID, app.UUID, []string{"billing_reader"}, 60)
+	require.NoError(t, err)
+	introspection = IntrospectToken(db, ctx, app, client_token)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, app_client.ClientID, introspection.ClientID)
+	assert.Equal(t, app_client.ClientID, introspection.Sub)
+
+	refresh, err := IssueRefreshToken(db, ctx, user.ID, "family", true)
+	require.NoError(t, err)
+	introspection = IntrospectToken(db, ctx, app, refresh)
+	assert.True(t, introspection.Active, "An unused refresh token should be active")
+	assert.Equal(t, "refresh_token", introspection.TokenType)
+	assert.True(t, introspection.MFA)
+	assert.True(t, IntrospectToken(db, ctx, app, refresh).Active, "Introspection should not use the refresh token up")
+
+	// disabled users and clients make their tokens inactive
+	db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("disabled", true)
+	assert.False(t, IntrospectToken(db, ctx, app, access).Active)
+	assert.False(t, IntrospectToken(db, ctx, app, refresh).Active)
+	db.Model(&models.AppClient{}).Where("id = ?", app_client.ID).UpdateColumn("active", false)
+	assert.False(t, IntrospectToken(db, ctx, app, client_token).Active)
+
+	expired, err := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{app.UUID}}, Email: user.Email, UserID: int(user.ID)}, -1)
+	require.NoError(t, err)
+	db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("disabled", false)
+	assert.False(t, IntrospectToken(db, ctx, app, expired).Active, "Expired tokens should be inactive")
+	assert.True(t, IntrospectToken(db, ctx, app, access).Active)
+
+	Revocations.mu.Lock()
+	Revocations.loaded_at = time.Now()
+	Revocations.mu.Unlock()
+	claims, _ := ParseJWTToken(access)
+	Revocations.addToken(claims.ID, time.Now().Add(time.Hour))
+	assert.False(t, IntrospectToken(db, ctx, app, access).Active, "Revoked tokens should be inactive")
+}
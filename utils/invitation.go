@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/messages"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	InvitationSubject        = "Invitation"
+	EmailVerificationSubject = "Email Verification"
+)
+
+var (
+	ErrInvitationInvalid        = errors.New("invalid or expired invitation")
+	ErrEmailVerificationInvalid = errors.New("invalid or expired email verification link")
+)
+
+// Claims of the signed links sent for invitations and email verification
+type OnboardingClaim struct {
+	jwt.RegisteredClaims
+	InvitationID uint   `json:"invitation_id,omitempty"`
+	UserID       uint   `json:"user_id"`
+	Email        string `json:"email"`
+}
+
+// Invitation lifetime in minutes from INVITATION_LIFE_TIME, defaults to 3 days
+func InvitationLifeTime() time.Duration {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("INVITATION_LIFE_TIME", "4320"))
+	if life_time <= 0 {
+		life_time = 4320
+	}
+	return time.Duration(life_time) * time.Minute
+}
+
+// Verification link lifetime in minutes from EMAIL_VERIFICATION_LIFE_TIME, defaults to 1 day
+func emailVerificationLifeTime() time.Duration {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("EMAIL_VERIFICATION_LIFE_TIME", "1440"))
+	if life_time <= 0 {
+		life_time = 1440
+	}
+	return time.Duration(life_time) * time.Minute
+}
+
+func createOnboardingToken(subject string, onboarding_claim OnboardingClaim, expires_at time.Time) (string, error) {
+	now := time.Now().UTC()
+	onboarding_claim.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        newTokenID(),
+		Issuer:    "Blue Admin",
+		Subject:   subject,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expires_at),
+	}
+	return signClaims(onboarding_claim)
+}
+
+func parseOnboardingToken(jwtToken string, subject string) (OnboardingClaim, error) {
+	response := OnboardingClaim{}
+	token, err := jwt.ParseWithClaims(jwtToken, &response, signingKeyFunc, signingMethods)
+	if err != nil || !token.Valid || response.Subject != subject {
+		return OnboardingClaim{}, errors.New("invalid onboarding token")
+	}
+	return response, nil
+}
+
+func CreateInvitationToken(invitation models.Invitation) (string, error) {
+	return createOnboardingToken(InvitationSubject, OnboardingClaim{
+		InvitationID: invitation.ID,
+		UserID:       invitation.UserID,
+		Email:        invitation.Email,
+	}, invitation.ExpiresAt)
+}
+
+func CreateEmailVerificationToken(user models.User) (string, error) {
+	return createOnboardingToken(EmailVerificationSubject, OnboardingClaim{
+		UserID: user.ID,
+		Email:  user.Email,
+	}, time.Now().UTC().Add(emailVerificationLifeTime()))
+}
+
+// Link sent to the invitee, INVITATION_URL is the page of the admin ui that posts the new password
+func InvitationLink(token string) string {
+	invitation_url := configs.AppConfig.GetOrDefault("INVITATION_URL", OIDCIssuer()+"/admin/invitation")
+	return invitation_url + "?" + url.Values{"token": {token}}.Encode()
+}
+
+// Link sent to confirm the address, EMAIL_VERIFICATION_URL is the page of the admin ui that posts the token
+func EmailVerificationLink(token string) string {
+	verification_url := configs.AppConfig.GetOrDefault("EMAIL_VERIFICATION_URL", OIDCIssuer()+"/admin/verify-email")
+	return verification_url + "?" + url.Values{"token": {token}}.Encode()
+}
+
+func SendInvitationEmail(invitation models.Invitation, app_name string, token string) error {
+	return messages.PublishEmailQueue(messages.EmailMessage{
+		Emails:  []string{invitation.Email},
+		Subject: fmt.Sprintf("Invitation to %v", app_name),
+		Message: fmt.Sprintf("You have been invited to %v, use the following link to set your password, it expires on %v: %v", app_name, invitation.ExpiresAt.Format(time.RFC1123), InvitationLink(token)),
+	}, configs.AppConfig.GetOrDefault("EMAIL_QUEUE", "email"))
+}
+
+func SendEmailVerification(user models.User, token string) error {
+	return messages.PublishEmailQueue(messages.EmailMessage{
+		Emails:  []string{user.Email},
+		Subject: "Verify your email",
+		Message: fmt.Sprintf("Use the following link to verify your email address: %v", EmailVerificationLink(token)),
+	}, configs.AppConfig.GetOrDefault("EMAIL_QUEUE", "email"))
+}
+
+// Returns the open invitation named by the signed token
+func FindInvitation(db *gorm.DB, ctx context.Context, token string) (models.Invitation, error) {
+	onboarding_claim, err := parseOnboardingToken(token, InvitationSubject)
+	if err != nil {
+		return models.Invitation{}, ErrInvitationInvalid
+	}
+
+	var invitation models.Invitation
+	if res := db.WithContext(ctx).Model(&models.Invitation{}).Preload("Roles").
+		Where("id = ? AND email = ? AND accepted_at IS NULL AND expires_at > ?", onboarding_claim.InvitationID, onboarding_claim.Email, time.Now().UTC()).
+		First(&invitation); res.Error != nil {
+		return models.Invitation{}, ErrInvitationInvalid
+	}
+	return invitation, nil
+}
+
+// Grants the invited roles, sets the chosen password and activates the user, the email is
+// verified by the invitee opening the link. The password is checked against the policies of
+// the invited roles so a rejected password leaves the invitation open.
+func AcceptInvitation(db *gorm.DB, ctx context.Context, invitation models.Invitation, name string, password string) (models.User, error) {
+	var user models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND status = ?", invitation.UserID, models.UserStatusInvited).First(&user); res.Error != nil {
+		return models.User{}, ErrInvitationInvalid
+	}
+
+	var user_out models.User
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(invitation.Roles) > 0 {
+			if err := tx.Model(&user).Association("Roles").Append(invitation.Roles); err != nil {
+				return err
+			}
+		}
+		if err := SetUserPassword(tx, ctx, user, password); err != nil {
+			return err
+		}
+
+		// conditional update so the invitation can only be accepted once
+		now := time.Now().UTC()
+		res := tx.Model(&models.Invitation{}).Where("id = ? AND accepted_at IS NULL", invitation.ID).Update("accepted_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrInvitationInvalid
+		}
+
+		updates := map[string]interface{}{
+			"status":            models.UserStatusActive,
+			"email_verified_at": now,
+		}
+		if name != "" {
+			updates["name"] = name
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", user.ID).First(&user_out).Error
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return user_out, nil
+}
+
+// Marks the email of the user named by the signed token verified and activates a pending user
+func VerifyEmail(db *gorm.DB, ctx context.Context, token string) (models.User, error) {
+	onboarding_claim, err := parseOnboardingToken(token, EmailVerificationSubject)
+	if err != nil {
+		return models.User{}, ErrEmailVerificationInvalid
+	}
+
+	// the address must not have changed since the link was sent
+	var user models.User
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND email = ?", onboarding_claim.UserID, onboarding_claim.Email).First(&user); res.Error != nil {
+		return models.User{}, ErrEmailVerificationInvalid
+	}
+	if user.Status == models.UserStatusInvited {
+		return models.User{}, ErrEmailVerificationInvalid
+	}
+
+	now := time.Now().UTC()
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"status":            models.UserStatusActive,
+		"email_verified_at": now,
+	}); res.Error != nil {
+		return models.User{}, res.Error
+	}
+	user.Status = models.UserStatusActive
+	user.EmailVerifiedAt = &now
+	return user, nil
+}
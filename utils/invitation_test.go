@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptInvitation(t *testing.T) {
+	t.Setenv("ARGON2_MEMORY", "1024")
+	t.Setenv("ARGON2_ITERATIONS", "1")
+	db := memoryDB(t, &models.User{}, &models.Role{}, &models.App{}, &models.Invitation{}, &models.PasswordPolicy{}, &models.PasswordHistory{}, &models.LoginAttempt{}, &models.RefreshToken{}, &models.UserRevocation{}, &models.Session{})
+	ctx := context.Background()
+
+	app := models.App{Name: "invite", Description: "invite app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	role := models.Role{Name: "invitee", Description: "invitee", Active: true, AppID: sql.NullInt64{Int64: int64(app.ID), Valid: true}}
+	require.NoError(t, db.Create(&role).Error)
+	user := models.User{Name: "invited", Email: "invited@mail.com", Password: "placeholder", Status: models.UserStatusInvited}
+	require.NoError(t, db.Create(&user).Error)
+	invitation := models.Invitation{Email: user.Email, AppID: app.ID, UserID: user.ID, Roles: []models.Role{role}, ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	require.NoError(t, db.Create(&invitation).Error)
+
+	token, err := CreateInvitationToken(invitation)
+	require.NoError(t, err)
+	_, err = ParseJWTToken(token)
+	assert.ErrorIs(t, err, ErrNotAccessToken, "Invitation token should not be an access token")
+
+	found, err := FindInvitation(db, ctx, token)
+	require.NoError(t, err, "Open invitation should be found")
+	_, err = AcceptInvitation(db, ctx, found, "Invited User", "short")
+	assert.Error(t, err, "Weak password should be rejected")
+
+	accepted, err := AcceptInvitation(db, ctx, found, "Invited User", "chosen-password")
+	require.NoError(t, err, "Accepting should not return an error")
+	assert.Equal(t, models.UserStatusActive, accepted.Status)
+	assert.NotNil(t, accepted.EmailVerifiedAt, "Email should be verified")
+	assert.Equal(t, "Invited User", accepted.Name)
+	assert.True(t, PasswordsMatch(accepted.Password, "chosen-password"))
+
+	var granted []models.Role
+	db.Model(&accepted).Association("Roles").Find(&granted)
+	require.Len(t, granted, 1, "Invited roles should be granted")
+	assert.Equal(t, role.ID, granted[0].ID)
+
+	_, err = FindInvitation(db, ctx, token)
+	assert.ErrorIs(t, err, ErrInvitationInvalid, "Invitation should be single use")
+}
+
+func TestVerifyEmail(t *testing.T) {
+	db := memoryDB(t, &models.User{})
+	ctx := context.Background()
+
+	user := models.User{Name: "pending", Email: "pending@mail.com", Password: "password", Status: models.UserStatusPending}
+	require.NoError(t, db.Create(&user).Error)
+
+	token, err := CreateEmailVerificationToken(user)
+	require.NoError(t, err)
+	verified, err := VerifyEmail(db, ctx, token)
+	require.NoError(t, err, "Verification should not return an error")
+	assert.Equal(t, models.UserStatusActive, verified.Status)
+
+	db.Model(&user).Update("email", "changed@mail.com")
+	_, err = VerifyEmail(db, ctx, token)
+	assert.ErrorIs(t, err, ErrEmailVerificationInvalid, "Link should not verify a changed address")
+}
@@ -0,0 +1,270 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/models"
+	"github.com/go-ldap/ldap/v3"
+	"gorm.io/gorm"
+)
+
+// Directory settings read from the LDAP_* configuration
+type LDAPConfig struct {
+	URL                string
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	NameAttribute      string
+	GroupAttribute     string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+	// lower cased group dn or cn to blue-admin role name
+	GroupRoles map[string]string
+}
+
+// Parses LDAP_GROUP_ROLES, rules are separated by ";" and map a group to a role with "=>",
+// a group given by its full dn matches that dn, a plain name matches the cn of any group
+//
+//	cn=admins,ou=groups,dc=example,dc=com=>superuser;staff=>reader
+func ParseLDAPGroupRoles(rules string) map[string]string {
+	group_roles := make(map[string]string)
+	for _, rule := range strings.Split(rules, ";") {
+		group, role, found := strings.Cut(rule, "=>")
+		group = strings.ToLower(strings.TrimSpace(group))
+		role = strings.TrimSpace(role)
+		if !found || group == "" || role == "" {
+			continue
+		}
+		group_roles[group] = role
+	}
+	return group_roles
+}
+
+func LDAPConfigFromEnv() LDAPConfig {
+	timeout, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("LDAP_TIMEOUT", "10"))
+	if timeout <= 0 {
+		timeout = 10
+	}
+	return LDAPConfig{
+		URL:                configs.AppConfig.Get("LDAP_URL"),
+		BindDN:             configs.AppConfig.Get("LDAP_BIND_DN"),
+		BindPassword:       configs.AppConfig.Get("LDAP_BIND_PASSWORD"),
+		BaseDN:             configs.AppConfig.Get("LDAP_BASE_DN"),
+		UserFilter:         configs.AppConfig.GetOrDefault("LDAP_USER_FILTER", "(mail=%s)"),
+		NameAttribute:      configs.AppConfig.GetOrDefault("LDAP_NAME_ATTRIBUTE", "cn"),
+		GroupAttribute:     configs.AppConfig.GetOrDefault("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		StartTLS:           configs.AppConfig.Get("LDAP_START_TLS") == "true",
+		InsecureSkipVerify: configs.AppConfig.Get("LDAP_INSECURE_SKIP_VERIFY") == "true",
+		Timeout:            time.Duration(timeout) * time.Second,
+		GroupRoles:         ParseLDAPGroupRoles(configs.AppConfig.Get("LDAP_GROUP_ROLES")),
+	}
+}
+
+// Authenticates by binding as the directory entry of the email, the local user is created on
+// first login and its name and mapped roles are updated on every login
+type LDAPAuthenticator struct {
+	Config LDAPConfig
+}
+
+func NewLDAPAuthenticator() *LDAPAuthenticator {
+	return &LDAPAuthenticator{Config: LDAPConfigFromEnv()}
+}
+
+func (authenticator *LDAPAuthenticator) Name() string {
+	return models.AuthSourceLDAP
+}
+
+func (authenticator *LDAPAuthenticator) dial() (*ldap.Conn, error) {
+	config := authenticator.Config
+	if config.URL == "" {
+		return nil, errors.New("LDAP_URL is not configured")
+	}
+
+	server_url, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	tls_config := &tls.Config{ServerName: server_url.Hostname(), InsecureSkipVerify: config.InsecureSkipVerify}
+
+	conn, err := ldap.DialURL(config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: config.Timeout}), ldap.DialWithTLSConfig(tls_config))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(config.Timeout)
+	if config.StartTLS {
+		if err := conn.StartTLS(tls_config); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (authenticator *LDAPAuthenticator) Authenticate(db *gorm.DB, ctx context.Context, email string, password string) (models.User, error) {
+	// an empty password would be an anonymous bind
+	if email == "" || password == "" {
+		return models.User{}, ErrInvalidCredentials
+	}
+	config := authenticator.Config
+
+	conn, err := authenticator.dial()
+	if err != nil {
+		return models.User{}, err
+	}
+	defer conn.Close()
+
+	// the service account finds the entry, without one the search runs anonymously
+	if config.BindDN != "" {
+		if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+			return models.User{}, err
+		}
+	}
+	search_request := ldap.NewSearchRequest(
+		config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(config.Timeout/time.Second), false,
+		fmt.Sprintf(config.UserFilter, ldap.EscapeFilter(email)),
+		[]string{config.NameAttribute, config.GroupAttribute},
+		nil,
+	)
+	result, err := conn.Search(search_request)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return models.User{}, err
+	}
+	if result == nil || len(result.Entries) != 1 {
+		return models.User{}, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return models.User{}, ErrInvalidCredentials
+		}
+		return models.User{}, err
+	}
+
+	name := entry.GetAttributeValue(config.NameAttribute)
+	if name == "" {
+		name = email
+	}
+	return ProvisionDirectoryUser(db, ctx, models.AuthSourceLDAP, email, name, authenticator.mappedRoles(entry.GetAttributeValues(config.GroupAttribute)), authenticator.managedRoles())
+}
+
+// Role names the groups map to
+func (authenticator *LDAPAuthenticator) mappedRoles(groups []string) []string {
+	roles := make([]string, 0, len(groups))
+	seen := make(map[string]bool)
+	for _, group := range groups {
+		group = strings.ToLower(strings.TrimSpace(group))
+		role, ok := authenticator.Config.GroupRoles[group]
+		if !ok {
+			if dn, err := ldap.ParseDN(group); err == nil && len(dn.RDNs) > 0 && len(dn.RDNs[0].Attributes) > 0 && dn.RDNs[0].Attributes[0].Type == "cn" {
+				role, ok = authenticator.Config.GroupRoles[dn.RDNs[0].Attributes[0].Value]
+			}
+		}
+		if ok && !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// Roles any group rule can grant, only these are removed when the groups change so roles
+// assigned by hand stay
+func (authenticator *LDAPAuthenticator) managedRoles() []string {
+	roles := make([]string, 0, len(authenticator.Config.GroupRoles))
+	for _, role := range authenticator.Config.GroupRoles {
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// Creates or updates the local user of a directory login and syncs its roles, managed_roles are
+// the roles the directory decides on, the others are left as assigned. Local accounts with the
+// same email are not taken over.
+func ProvisionDirectoryUser(db *gorm.DB, ctx context.Context, source string, email string, name string, role_names []string, managed_roles []string) (models.User, error) {
+	var user models.User
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.User{}).Where("email = ?", email).First(&user)
+		if res.Error != nil && !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return res.Error
+		}
+		if res.Error == nil {
+			if user.AuthSource != source || user.Disabled {
+				return ErrInvalidCredentials
+			}
+			if user.Name != name {
+				if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Update("name", name).Error; err != nil {
+					return err
+				}
+				user.Name = name
+			}
+		} else {
+			// the local password is random and never used, the directory stays the only way in
+			random_password, err := GenerateOpaqueToken(32)
+			if err != nil {
+				return err
+			}
+			now := time.Now().UTC()
+			user = models.User{
+				Name:            name,
+				Email:           email,
+				Password:        random_password,
+				Status:          models.UserStatusActive,
+				EmailVerifiedAt: &now,
+				AuthSource:      source,
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(managed_roles) > 0 {
+			var held_roles []models.Role
+			if err := tx.Model(&models.Role{}).
+				Joins("INNER JOIN user_roles ON user_roles.role_id = roles.id").
+				Where("user_roles.user_id = ? AND roles.name IN ?", user.ID, managed_roles).
+				Find(&held_roles).Error; err != nil {
+				return err
+			}
+			stale_roles := make([]models.Role, 0, len(held_roles))
+			for _, role := range held_roles {
+				if !slices.Contains(role_names, role.Name) {
+					stale_roles = append(stale_roles, role)
+				}
+			}
+			if len(stale_roles) > 0 {
+				if err := tx.Model(&user).Association("Roles").Delete(stale_roles); err != nil {
+					return err
+				}
+			}
+		}
+		if len(role_names) > 0 {
+			var roles []models.Role
+			if err := tx.Model(&models.Role{}).Where("name IN ?", role_names).Find(&roles).Error; err != nil {
+				return err
+			}
+			if len(roles) > 0 {
+				if err := tx.Model(&user).Association("Roles").Append(roles); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
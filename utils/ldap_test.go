@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"blue-admin.com/models"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLDAPEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// Answers simple binds and searches by mail from a fixed set of entries, enough for the authenticator
+type testLDAPServer struct {
+	listener net.Listener
+	entries  []testLDAPEntry
+}
+
+func startTestLDAPServer(t *testing.T, entries []testLDAPEntry) *testLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &testLDAPServer{listener: listener, entries: entries}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *testLDAPServer) url() string {
+	return "ldap://" + server.listener.Addr().String()
+}
+
+func ldapResult(message_id int64, tag ber.Tag, code uint16) *ber.Packet {
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	return ldapMessage(message_id, response)
+}
+
+func ldapMessage(message_id int64, response *ber.Packet) *ber.Packet {
+	packet := ber.NewSequence("LDAPMessage")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, message_id, "messageID"))
+	packet.AppendChild(response)
+	return packet
+}
+
+func (server *testLDAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		message_id, _ := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			dn, _ := request.Children[1].Value.(string)
+			password := request.Children[2].Data.String()
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			for _, entry := range server.entries {
+				if entry.dn == dn && entry.password == password {
+					code = ldap.LDAPResultSuccess
+				}
+			}
+			conn.Write(ldapResult(message_id, ldap.ApplicationBindResponse, code).Bytes())
+		case ldap.ApplicationSearchRequest:
+			filter, _ := ldap.DecompileFilter(request.Children[6])
+			for _, entry := range server.entries {
+				if filter != "(mail="+entry.attributes["mail"][0]+")" {
+					continue
+				}
+				response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+				response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.dn, "objectName"))
+				attributes := ber.NewSequence("attributes")
+				for name, values := range entry.attributes {
+					attribute := ber.NewSequence("attribute")
+					attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+					set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+					for _, value := range values {
+						set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "value"))
+					}
+					attribute.AppendChild(set)
+					attributes.AppendChild(attribute)
+				}
+				response.AppendChild(attributes)
+				conn.Write(ldapMessage(message_id, response).Bytes())
+			}
+			conn.Write(ldapResult(message_id, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess).Bytes())
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func TestLDAPAuthenticator(t *testing.T) {
+	t.Setenv("ARGON2_MEMORY", "1024")
+	t.Setenv("ARGON2_ITERATIONS", "1")
+	db := memoryDB(t, &models.User{}, &models.Role{})
+	ctx := context.Background()
+
+	for _, name := range []string{"ldapadmin", "ldapreader", "manual"} {
+		require.NoError(t, db.Create(&models.Role{Name: name, Description: name, Active: true}).Error)
+	}
+	require.NoError(t, db.Create(&models.User{Name: "local", Email: "local@example.com", Password: "local-password"}).Error)
+
+	entries := []testLDAPEntry{
+		{dn: "cn=service,dc=example,dc=com", password: "service-password", attributes: map[string][]string{"mail": {"service@example.com"}}},
+		{dn: "uid=alice,ou=people,dc=example,dc=com", password: "alice-password", attributes: map[string][]string{
+			"mail":     {"alice@example.com"},
+			"cn":       {"Alice"},
+			"memberOf": {"cn=admins,ou=groups,dc=example,dc=com", "CN=Staff,ou=groups,dc=example,dc=com", "cn=other,ou=groups,dc=example,dc=com"},
+		}},
+		{dn: "uid=local,ou=people,dc=example,dc=com", password: "directory-password", attributes: map[string][]string{"mail": {"local@example.com"}}},
+	}
+	server := startTestLDAPServer(t, entries)
+
+	authenticator := &LDAPAuthenticator{Config: LDAPConfig{
+		URL:            server.url(),
+		BindDN:         "cn=service,dc=example,dc=com",
+		BindPassword:   "service-password",
+		BaseDN:         "dc=example,dc=com",
+		UserFilter:     "(mail=%s)",
+		NameAttribute:  "cn",
+		GroupAttribute: "memberOf",
+		Timeout:        5e9,
+		GroupRoles:     ParseLDAPGroupRoles("cn=admins,ou=groups,dc=example,dc=com=>ldapadmin; staff=>ldapreader"),
+	}}
+
+	_, err := authenticator.Authenticate(db, ctx, "alice@example.com", "wrong-password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "Wrong passwords should be refused")
+	_, err = authenticator.Authenticate(db, ctx, "nobody@example.com", "alice-password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "Unknown users should be refused")
+	_, err = authenticator.Authenticate(db, ctx, "alice@example.com", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "Empty passwords should be refused")
+
+	user, err := authenticator.Authenticate(db, ctx, "alice@example.com", "alice-password")
+	require.NoError(t, err, "Directory login should not return an error")
+	assert.Equal(t, models.AuthSourceLDAP, user.AuthSource)
+	assert.Equal(t, "Alice", user.Name)
+	assert.NotNil(t, user.EmailVerifiedAt)
+
+	var role_names []string
+	db.Model(&models.Role{}).Joins("INNER JOIN user_roles ON user_roles.role_id = roles.id").Where("user_roles.user_id = ?", user.ID).Pluck("roles.name", &role_names)
+	assert.ElementsMatch(t, []string{"ldapadmin", "ldapreader"}, role_names, "Mapped groups should grant their roles")
+
+	// leaving a group drops its role, roles assigned by hand stay
+	var manual models.Role
+	db.Where("name = ?", "manual").First(&manual)
+	require.NoError(t, db.Model(&user).Association("Roles").Append(&manual))
+	authenticator.Config.GroupRoles = ParseLDAPGroupRoles("cn=admins,ou=groups,dc=example,dc=com=>ldapadmin;staff=>ldapreader;readers=>ldapreader")
+	server.entries[1].attributes["memberOf"] = []string{"cn=staff,ou=groups,dc=example,dc=com"}
+	again, err := authenticator.Authenticate(db, ctx, "alice@example.com", "alice-password")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, again.ID, "The provisioned user should be reused")
+	role_names = nil
+	db.Model(&models.Role{}).Joins("INNER JOIN user_roles ON user_roles.role_id = roles.id").Where("user_roles.user_id = ?", user.ID).Pluck("roles.name", &role_names)
+	assert.ElementsMatch(t, []string{"ldapreader", "manual"}, role_names)
+
+	_, err = authenticator.Authenticate(db, ctx, "local@example.com", "directory-password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "Local accounts should not be taken over")
+
+	// the chain tries the local users first and then the directory
+	t.Setenv("AUTHENTICATORS", "local,ldap")
+	t.Setenv("LDAP_URL", server.url())
+	t.Setenv("LDAP_BIND_DN", "cn=service,dc=example,dc=com")
+	t.Setenv("LDAP_BIND_PASSWORD", "service-password")
+	t.Setenv("LDAP_BASE_DN", "dc=example,dc=com")
+	local, err := AuthenticateUser(db, ctx, "local@example.com", "local-password")
+	require.NoError(t, err)
+	assert.Equal(t, models.AuthSourceLocal, local.AuthSource)
+	directory, err := AuthenticateUser(db, ctx, "alice@example.com", "alice-password")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, directory.ID)
+	assert.NotEmpty(t, directory.Roles, "Roles should be loaded")
+	_, err = AuthenticateUser(db, ctx, "alice@example.com", "wrong-password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// an unreachable directory is a backend failure, not a wrong password
+	t.Setenv("LDAP_URL", "ldap://127.0.0.1:1")
+	_, err = AuthenticateUser(db, ctx, "alice@example.com", "alice-password")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInvalidCredentials))
+}
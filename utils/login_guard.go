@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/messages"
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrLoginThrottled = errors.New("too many failed login attempts, try again later")
+
+// Brute force protection thresholds, durations are configured in minutes except the backoff in seconds
+type loginGuardSettings struct {
+	MaxFailures   int
+	IPMaxFailures int
+	Lockout       time.Duration
+	IPBlock       time.Duration
+	Window        time.Duration
+	BackoffBase   time.Duration
+	BackoffMax    time.Duration
+}
+
+func configInt(key string, fallback string) int {
+	value, err := strconv.Atoi(configs.AppConfig.GetOrDefault(key, fallback))
+	if err != nil {
+		value, _ = strconv.Atoi(fallback)
+	}
+	return value
+}
+
+func loginGuardConfig() loginGuardSettings {
+	return loginGuardSettings{
+		MaxFailures:   configInt("LOGIN_MAX_FAILURES", "5"),
+		IPMaxFailures: configInt("LOGIN_IP_MAX_FAILURES", "50"),
+		Lockout:       time.Duration(configInt("LOGIN_LOCKOUT_DURATION", "15")) * time.Minute,
+		IPBlock:       time.Duration(configInt("LOGIN_IP_BLOCK_DURATION", "15")) * time.Minute,
+		Window:        time.Duration(configInt("LOGIN_FAILURE_WINDOW", "15")) * time.Minute,
+		BackoffBase:   time.Duration(configInt("LOGIN_BACKOFF_BASE", "1")) * time.Second,
+		BackoffMax:    time.Duration(configInt("LOGIN_BACKOFF_MAX", "60")) * time.Second,
+	}
+}
+
+func emailAttemptKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Delay enforced after the given number of consecutive failures, doubling from base up to max
+func LoginBackoff(failures int, base time.Duration, max time.Duration) time.Duration {
+	if failures <= 0 || base <= 0 {
+		return 0
+	}
+	backoff := base
+	for i := 1; i < failures; i++ {
+		backoff *= 2
+		if backoff >= max {
+			return max
+		}
+	}
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
+
+// How long the client has to wait before the email may be tried again from the ip,
+// zero when the attempt may go ahead
+func LoginRetryAfter(db *gorm.DB, ctx context.Context, email string, ip string) (time.Duration, error) {
+	settings := loginGuardConfig()
+	now := time.Now().UTC()
+	email_key := emailAttemptKey(email)
+
+	var login_attempts []models.LoginAttempt
+	if res := db.WithContext(ctx).Model(&models.LoginAttempt{}).Where("identifier IN ?", []string{email_key, ipAttemptKey(ip)}).Find(&login_attempts); res.Error != nil {
+		return 0, res.Error
+	}
+
+	var wait time.Duration
+	later := func(until time.Time) {
+		if until.Sub(now) > wait {
+			wait = until.Sub(now)
+		}
+	}
+	for _, value := range login_attempts {
+		if value.BlockedUntil.Valid {
+			later(value.BlockedUntil.Time)
+		}
+		if value.Identifier == email_key && now.Sub(value.LastFailureAt) < settings.Window {
+			later(value.LastFailureAt.Add(LoginBackoff(value.Failures, settings.BackoffBase, settings.BackoffMax)))
+		}
+	}
+
+	var locked_until sql.NullTime
+	if res := db.WithContext(ctx).Model(&models.User{}).Select("locked_until").Where("email = ?", email).Scan(&locked_until); res.Error != nil {
+		return 0, res.Error
+	}
+	if locked_until.Valid {
+		later(locked_until.Time)
+	}
+
+	// rounded up so a Retry-After header never invites a request that is still rejected
+	if wait%time.Second != 0 {
+		wait = wait.Truncate(time.Second) + time.Second
+	}
+	return wait, nil
+}
+
+// Increments the failure counter of the identifier, restarting it when the last failure is outside the window
+func bumpLoginAttempt(db *gorm.DB, ctx context.Context, identifier string, now time.Time, window time.Duration) (int, error) {
+	tx := db.WithContext(ctx)
+	if res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.LoginAttempt{Identifier: identifier, LastFailureAt: now}); res.Error != nil {
+		return 0, res.Error
+	}
+	if res := tx.Model(&models.LoginAttempt{}).Where("identifier = ? AND last_failure_at < ?", identifier, now.Add(-window)).Update("failures", 0); res.Error != nil {
+		return 0, res.Error
+	}
+	if res := tx.Model(&models.LoginAttempt{}).Where("identifier = ?", identifier).Updates(map[string]interface{}{
+		"failures":        gorm.Expr("failures + 1"),
+		"last_failure_at": now,
+	}); res.Error != nil {
+		return 0, res.Error
+	}
+
+	var login_attempt models.LoginAttempt
+	if res := tx.Model(&models.LoginAttempt{}).Where("identifier = ?", identifier).First(&login_attempt); res.Error != nil {
+		return 0, res.Error
+	}
+	return login_attempt.Failures, nil
+}
+
+// Blocks the identifier until the given time and starts its counter over
+func blockLoginAttempt(db *gorm.DB, ctx context.Context, identifier string, until time.Time) error {
+	return db.WithContext(ctx).Model(&models.LoginAttempt{}).Where("identifier = ?", identifier).Updates(map[string]interface{}{
+		"failures":      0,
+		"blocked_until": sql.NullTime{Time: until, Valid: true},
+	}).Error
+}
+
+// Counts a failed login for the email and the ip, locks the account after LOGIN_MAX_FAILURES
+// and blocks the ip after LOGIN_IP_MAX_FAILURES failures within LOGIN_FAILURE_WINDOW
+func RecordLoginFailure(db *gorm.DB, ctx context.Context, email string, ip string) error {
+	settings := loginGuardConfig()
+	now := time.Now().UTC()
+
+	email_key := emailAttemptKey(email)
+	email_failures, err := bumpLoginAttempt(db, ctx, email_key, now, settings.Window)
+	if err != nil {
+		return err
+	}
+	if email_failures >= settings.MaxFailures {
+		locked_until := now.Add(settings.Lockout)
+		// unknown emails are blocked the same way so a lockout does not reveal which accounts exist
+		if err := blockLoginAttempt(db, ctx, email_key, locked_until); err != nil {
+			return err
+		}
+		var user models.User
+		if res := db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).Limit(1).Find(&user); res.Error != nil {
+			return res.Error
+		}
+		if user.ID != 0 {
+			if res := db.WithContext(ctx).Model(&user).Update("locked_until", locked_until); res.Error != nil {
+				return res.Error
+			}
+		}
+		EmitSecurityEvent(messages.SecurityEvent{
+			Type:    EventAccountLocked,
+			UserID:  user.ID,
+			Email:   email,
+			IP:      ip,
+			Details: map[string]string{"locked_until": locked_until.Format(time.RFC3339)},
+		})
+	}
+
+	if ip == "" {
+		return nil
+	}
+	ip_key := ipAttemptKey(ip)
+	ip_failures, err := bumpLoginAttempt(db, ctx, ip_key, now, settings.Window)
+	if err != nil {
+		return err
+	}
+	if ip_failures >= settings.IPMaxFailures {
+		blocked_until := now.Add(settings.IPBlock)
+		if err := blockLoginAttempt(db, ctx, ip_key, blocked_until); err != nil {
+			return err
+		}
+		EmitSecurityEvent(messages.SecurityEvent{
+			Type:    EventIPBlocked,
+			IP:      ip,
+			Details: map[string]string{"blocked_until": blocked_until.Format(time.RFC3339)},
+		})
+	}
+	return nil
+}
+
+// Clears the failure counter of the email after a successful login, the ip counter is kept
+// so one valid account can not be used to reset an ip that is guessing others
+func RecordLoginSuccess(db *gorm.DB, ctx context.Context, email string) error {
+	return db.WithContext(ctx).Where("identifier = ?", emailAttemptKey(email)).Delete(&models.LoginAttempt{}).Error
+}
+
+// Lifts the lockout of the user and clears the failure counter of the user's email
+func UnlockUser(db *gorm.DB, ctx context.Context, user models.User, ip string) error {
+	if res := db.WithContext(ctx).Model(&user).Update("locked_until", nil); res.Error != nil {
+		return res.Error
+	}
+	if err := RecordLoginSuccess(db, ctx, user.Email); err != nil {
+		return err
+	}
+	EmitSecurityEvent(messages.SecurityEvent{
+		Type:   EventAccountUnlocked,
+		UserID: user.ID,
+		Email:  user.Email,
+		IP:     ip,
+	})
+	return nil
+}
+
+// Removes counters whose window passed and whose block expired
+func CleanExpiredLoginAttempts() {
+	db, err := database.ReturnSession()
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	db.Where("last_failure_at < ? AND (blocked_until IS NULL OR blocked_until < ?)", now.Add(-loginGuardConfig().Window), now).Delete(&models.LoginAttempt{})
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), LoginBackoff(0, time.Second, time.Minute))
+	assert.Equal(t, time.Second, LoginBackoff(1, time.Second, time.Minute))
+	assert.Equal(t, 4*time.Second, LoginBackoff(3, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, LoginBackoff(20, time.Second, time.Minute), "Backoff should be capped")
+}
+
+func TestLoginLockout(t *testing.T) {
+	t.Setenv("LOGIN_MAX_FAILURES", "3")
+	t.Setenv("LOGIN_IP_MAX_FAILURES", "100")
+	db := memoryDB(t, &models.User{}, &models.LoginAttempt{})
+	ctx := context.Background()
+
+	user := models.User{Name: "locked", Email: "locked@mail.com", Password: "password"}
+	require.NoError(t, db.Create(&user).Error)
+
+	wait, err := LoginRetryAfter(db, ctx, user.Email, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Zero(t, wait, "Fresh account should not be throttled")
+
+	require.NoError(t, RecordLoginFailure(db, ctx, user.Email, "10.0.0.1"))
+	wait, _ = LoginRetryAfter(db, ctx, user.Email, "10.0.0.1")
+	assert.Equal(t, time.Second, wait, "First failure should back off one second")
+
+	require.NoError(t, RecordLoginFailure(db, ctx, user.Email, "10.0.0.1"))
+	require.NoError(t, RecordLoginFailure(db, ctx, user.Email, "10.0.0.2"))
+	db.First(&user, user.ID)
+	require.NotNil(t, user.LockedUntil, "Account should be locked on the threshold")
+	wait, _ = LoginRetryAfter(db, ctx, user.Email, "10.0.0.3")
+	assert.Greater(t, wait, 14*time.Minute, "Lockout should apply from any ip")
+
+	require.NoError(t, UnlockUser(db, ctx, user, ""))
+	wait, _ = LoginRetryAfter(db, ctx, user.Email, "10.0.0.3")
+	assert.Zero(t, wait, "Unlocked account should be accepted again")
+
+	// unknown emails lock the same way
+	for i := 0; i < 3; i++ {
+		require.NoError(t, RecordLoginFailure(db, ctx, "nobody@mail.com", "10.0.0.4"))
+	}
+	wait, _ = LoginRetryAfter(db, ctx, "nobody@mail.com", "10.0.0.5")
+	assert.Greater(t, wait, 14*time.Minute)
+}
+
+func TestLoginIPBlock(t *testing.T) {
+	t.Setenv("LOGIN_IP_MAX_FAILURES", "3")
+	db := memoryDB(t, &models.User{}, &models.LoginAttempt{})
+	ctx := context.Background()
+
+	for _, email := range []string{"a@mail.com", "b@mail.com", "c@mail.com"} {
+		require.NoError(t, RecordLoginFailure(db, ctx, email, "10.0.0.9"))
+	}
+	wait, _ := LoginRetryAfter(db, ctx, "d@mail.com", "10.0.0.9")
+	assert.Greater(t, wait, 14*time.Minute, "Ip guessing many accounts should be blocked")
+
+	wait, _ = LoginRetryAfter(db, ctx, "d@mail.com", "10.0.0.10")
+	assert.Zero(t, wait, "Other ips should not be affected")
+
+	// a successful login does not clear the ip counter
+	require.NoError(t, RecordLoginSuccess(db, ctx, "a@mail.com"))
+	wait, _ = LoginRetryAfter(db, ctx, "a@mail.com", "10.0.0.9")
+	assert.Greater(t, wait, 14*time.Minute)
+}
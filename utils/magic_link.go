@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/messages"
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMagicLinkInvalid  = errors.New("invalid or expired magic link")
+	ErrMagicLinkDisabled = errors.New("magic link login is not enabled for the app")
+	ErrMagicLinkOtherApp = errors.New("the magic link was issued for another app")
+)
+
+// Magic link lifetime in minutes from MAGIC_LINK_LIFE_TIME, defaults to 10 minutes
+func magicLinkLifeTime() time.Duration {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("MAGIC_LINK_LIFE_TIME", "10"))
+	if life_time <= 0 {
+		life_time = 10
+	}
+	return time.Duration(life_time) * time.Minute
+}
+
+// Active app that allows magic link logins
+func MagicLinkApp(db *gorm.DB, ctx context.Context, app_uuid string) (models.App, error) {
+	app, err := FindActiveApp(db, ctx, app_uuid)
+	if err != nil {
+		return models.App{}, err
+	}
+	if !app.MagicLinkEnabled {
+		return models.App{}, ErrMagicLinkDisabled
+	}
+	return app, nil
+}
+
+// Issues a single use login token for the user bound to the app, links issued before for the app stop working
+func IssueMagicLinkToken(db *gorm.DB, ctx context.Context, user_id uint, app_id uint, ip string) (string, error) {
+	token, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	tx := db.WithContext(ctx).Begin()
+	if err := tx.Where("user_id = ? AND app_id = ? AND used_at IS NULL", user_id, app_id).Delete(&models.MagicLinkToken{}).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	magic_link_token := models.MagicLinkToken{
+		TokenHash: HashOpaqueToken(token),
+		UserID:    user_id,
+		AppID:     app_id,
+		IP:        ip,
+		ExpiresAt: now.Add(magicLinkLifeTime()),
+		CreatedAt: now,
+	}
+	if err := tx.Create(&magic_link_token).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Marks the magic link token as used and returns its record with its app, expired or used tokens are rejected,
+// so are links of apps that turned magic links off and, when app_id is given, links of other apps
+func ConsumeMagicLinkToken(db *gorm.DB, ctx context.Context, token string, app_id *uint) (models.MagicLinkToken, models.App, error) {
+	var magic_link_token models.MagicLinkToken
+	if res := db.WithContext(ctx).Model(&models.MagicLinkToken{}).Where("token_hash = ?", HashOpaqueToken(token)).First(&magic_link_token); res.Error != nil {
+		return models.MagicLinkToken{}, models.App{}, ErrMagicLinkInvalid
+	}
+
+	// the app is checked before the token is used up so a link presented for the wrong app keeps working
+	if app_id != nil && *app_id != magic_link_token.AppID {
+		return models.MagicLinkToken{}, models.App{}, ErrMagicLinkOtherApp
+	}
+	var app models.App
+	if res := db.WithContext(ctx).Model(&models.App{}).Where("id = ? AND active = ? AND magic_link_enabled = ?", magic_link_token.AppID, true, true).First(&app); res.Error != nil {
+		return models.MagicLinkToken{}, models.App{}, ErrMagicLinkDisabled
+	}
+
+	// conditional update so the token can only be used once
+	now := time.Now().UTC()
+	res := db.WithContext(ctx).Model(&models.MagicLinkToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", magic_link_token.ID, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return models.MagicLinkToken{}, models.App{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return models.MagicLinkToken{}, models.App{}, ErrMagicLinkInvalid
+	}
+	return magic_link_token, app, nil
+}
+
+// Link sent to the user, MAGIC_LINK_URL is the login page that exchanges the token with the magic_link grant
+func MagicLink(token string, app models.App) string {
+	link_url := configs.AppConfig.GetOrDefault("MAGIC_LINK_URL", OIDCIssuer()+"/admin/magic-link")
+	return link_url + "?" + url.Values{"token": {token}, "app_uuid": {app.UUID}}.Encode()
+}
+
+// Queues the login link on the email queue (EMAIL_QUEUE) consumed by the email consumer
+func SendMagicLinkEmail(user models.User, app models.App, token string) error {
+	return messages.PublishEmailQueue(messages.EmailMessage{
+		Emails:  []string{user.Email},
+		Subject: fmt.Sprintf("Sign in to %v", app.Name),
+		Message: fmt.Sprintf("Use the following link to sign in to %v, it can be used once and expires in %v minutes: %v", app.Name, int(magicLinkLifeTime().Minutes()), MagicLink(token, app)),
+	}, configs.AppConfig.GetOrDefault("EMAIL_QUEUE", "email"))
+}
+
+// Removes expired and used magic link tokens, run by the scheduler
+func CleanExpiredMagicLinkTokens() {
+	db, err := database.ReturnSession()
+	if err != nil {
+		return
+	}
+	db.Where("expires_at < ? OR used_at IS NOT NULL", time.Now().UTC()).Delete(&models.MagicLinkToken{})
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMagicLinkToken(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.MagicLinkToken{})
+	ctx := context.Background()
+
+	tools := models.App{Name: "tools", Description: "internal tools", Active: true}
+	require.NoError(t, db.Create(&tools).Error)
+	_, err := MagicLinkApp(db, ctx, tools.UUID)
+	assert.ErrorIs(t, err, ErrMagicLinkDisabled, "Apps should not allow magic links by default")
+	db.Model(&tools).Update("magic_link_enabled", true)
+	app, err := MagicLinkApp(db, ctx, tools.UUID)
+	require.NoError(t, err)
+
+	first, err := IssueMagicLinkToken(db, ctx, 5, app.ID, "10.0.0.1")
+	require.NoError(t, err, "Issuing should not return an error")
+	second, err := IssueMagicLinkToken(db, ctx, 5, app.ID, "10.0.0.1")
+	require.NoError(t, err)
+
+	_, _, err = ConsumeMagicLinkToken(db, ctx, first, nil)
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid, "Earlier link should stop working")
+
+	other_app_id := app.ID + 1
+	_, _, err = ConsumeMagicLinkToken(db, ctx, second, &other_app_id)
+	assert.ErrorIs(t, err, ErrMagicLinkOtherApp, "Links should only be redeemed for their app")
+
+	magic_link_token, link_app, err := ConsumeMagicLinkToken(db, ctx, second, nil)
+	require.NoError(t, err, "A link presented for the wrong app should not be used up")
+	assert.Equal(t, uint(5), magic_link_token.UserID)
+	assert.Equal(t, app.ID, magic_link_token.AppID, "Token should be bound to the app")
+	assert.Equal(t, app.UUID, link_app.UUID, "The link's app should be returned")
+
+	_, _, err = ConsumeMagicLinkToken(db, ctx, second, nil)
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid, "Link should be single use")
+
+	expired, _ := IssueMagicLinkToken(db, ctx, 6, app.ID, "")
+	db.Model(&models.MagicLinkToken{}).Where("user_id = ?", 6).Update("expires_at", time.Now().UTC().Add(-time.Minute))
+	_, _, err = ConsumeMagicLinkToken(db, ctx, expired, &app.ID)
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid, "Expired link should be rejected")
+
+	link, err := url.Parse(MagicLink(second, app))
+	require.NoError(t, err)
+	assert.Equal(t, second, link.Query().Get("token"))
+	assert.Equal(t, app.UUID, link.Query().Get("app_uuid"))
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+)
+
+// Tokens and revocations read their tables through database.ReturnSession,
+// point it to a shared in memory database kept open for the whole run
+func TestMain(m *testing.M) {
+	os.Setenv("DB_TYPE", "sqlite")
+	os.Setenv("SQLLITE_URI", "file:utilstest?mode=memory&cache=shared")
+
+	db, err := database.ReturnSession()
+	if err != nil {
+		panic(err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.UserRevocation{},
+		&models.SigningKey{},
+		&models.Session{},
+		&models.App{},
+		&models.Role{},
+		&models.Feature{},
+		&models.Endpoint{},
+		&models.PermissionVersion{},
+		&models.FeatureRole{},
+		&models.AppClient{},
+	); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
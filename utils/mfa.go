@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const MFAChallengeSubject = "MFA Challenge"
+
+var (
+	ErrMFACodeInvalid      = errors.New("invalid mfa code")
+	ErrMFAChallengeInvalid = errors.New("invalid mfa challenge token")
+	ErrNotAccessToken      = errors.New("token is not an access token")
+)
+
+// Claims of the short lived token handed out after the password step of a two step login
+type MFAClaim struct {
+	jwt.RegisteredClaims
+	UserID int `json:"user_id"`
+	// set when the user has to enroll before completing the login
+	Enrollment bool `json:"mfa_enrollment,omitempty"`
+}
+
+func mfaChallengeLifeTime() int {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("MFA_CHALLENGE_LIFE_TIME", "5"))
+	return life_time
+}
+
+// Name shown in authenticator apps
+func MFAIssuer() string {
+	return configs.AppConfig.GetOrDefault("MFA_ISSUER", "Blue Admin")
+}
+
+// MFA is required when the user enrolled or when one of the user's roles or the app of one
+// of the roles requires it
+func MFARequired(db *gorm.DB, ctx context.Context, user models.User) (bool, error) {
+	if user.MFAEnabled {
+		return true, nil
+	}
+
+	var count int64
+	query_string := `SELECT count(*) FROM user_roles
+		INNER JOIN roles ON roles.id = user_roles.role_id
+		LEFT JOIN apps ON apps.id = roles.app_id
+		WHERE user_roles.user_id = ?
+		  AND roles.active = true
+		  AND (roles.mfa_required = true OR apps.mfa_required = true)`
+	if res := db.WithContext(ctx).Raw(query_string, user.ID).Scan(&count); res.Error != nil {
+		return false, res.Error
+	}
+	return count > 0, nil
+}
+
+func CreateMFAChallengeToken(user_id uint, enrollment bool) (string, error) {
+	now := time.Now().UTC()
+	mfa_claim := MFAClaim{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        newTokenID(),
+			Issuer:    "Blue Admin",
+			Subject:   MFAChallengeSubject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(mfaChallengeLifeTime()) * time.Minute)),
+		},
+		UserID:     int(user_id),
+		Enrollment: enrollment,
+	}
+	return signClaims(mfa_claim)
+}
+
+func ParseMFAChallengeToken(jwtToken string) (MFAClaim, error) {
+	response := MFAClaim{}
+	token, err := jwt.ParseWithClaims(jwtToken, &response, signingKeyFunc, signingMethods)
+	if err != nil || !token.Valid || response.Subject != MFAChallengeSubject {
+		return MFAClaim{}, ErrMFAChallengeInvalid
+	}
+
+	issued_at := time.Time{}
+	if response.IssuedAt != nil {
+		issued_at = response.IssuedAt.Time
+	}
+	if Revocations.IsRevoked(response.ID, uint(response.UserID), issued_at) {
+		return MFAClaim{}, ErrTokenRevoked
+	}
+	return response, nil
+}
+
+// Challenge tokens are single use, revoked once the login completed
+func RevokeMFAChallengeToken(db *gorm.DB, ctx context.Context, mfa_claim MFAClaim) error {
+	return RevokeToken(db, ctx, UserClaim{RegisteredClaims: mfa_claim.RegisteredClaims, UserID: mfa_claim.UserID})
+}
+
+// Checks a TOTP code of the enrolled user, falling back to the unused recovery codes
+func VerifyMFACode(db *gorm.DB, ctx context.Context, user models.User, code string) error {
+	if user.MFASecret == "" {
+		return ErrMFACodeInvalid
+	}
+
+	if step, ok := ValidateTOTP(user.MFASecret, code, time.Now().UTC(), user.MFALastStep); ok {
+		// conditional update so a code can not be replayed within its window
+		res := db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND mfa_last_step < ?", user.ID, step).UpdateColumn("mfa_last_step", step)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrMFACodeInvalid
+		}
+		return nil
+	}
+
+	res := db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, HashOpaqueToken(normalizeRecoveryCode(code))).
+		UpdateColumn("used_at", sql.NullTime{Time: time.Now().UTC(), Valid: true})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return ErrMFACodeInvalid
+	}
+	return nil
+}
+
+// recovery codes are shown grouped as xxxxx-xxxxx, they are compared without separators and case
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// Replaces the user's recovery codes with ten new ones and returns them
+func GenerateRecoveryCodes(db *gorm.DB, ctx context.Context, user_id uint) ([]string, error) {
+	codes := make([]string, 0, 10)
+	recovery_codes := make([]models.RecoveryCode, 0, 10)
+	for len(codes) < 10 {
+		secret, err := GenerateTOTPSecret()
+		if err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(secret[:5] + "-" + secret[5:10])
+		codes = append(codes, code)
+		recovery_codes = append(recovery_codes, models.RecoveryCode{
+			UserID:    user_id,
+			CodeHash:  HashOpaqueToken(normalizeRecoveryCode(code)),
+			CreatedAt: time.Now().UTC(),
+		})
+	}
+
+	tx := db.WithContext(ctx).Begin()
+	if err := tx.Where("user_id = ?", user_id).Delete(&models.RecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Create(&recovery_codes).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyMFACode(t *testing.T) {
+	db := memoryDB(t, &models.User{}, &models.RecoveryCode{})
+	ctx := context.Background()
+
+	secret, _ := GenerateTOTPSecret()
+	user := models.User{Name: "mfa", Email: "mfa@mail.com", Password: "password", MFAEnabled: true, MFASecret: secret}
+	require.NoError(t, db.Create(&user).Error)
+
+	code, _ := TOTPCode(secret, TOTPStep(time.Now().UTC()))
+	require.NoError(t, VerifyMFACode(db, ctx, user, code), "Current code should be accepted")
+	assert.ErrorIs(t, VerifyMFACode(db, ctx, user, code), ErrMFACodeInvalid, "Code should not be replayed")
+
+	recovery_codes, err := GenerateRecoveryCodes(db, ctx, user.ID)
+	require.NoError(t, err, "Generating recovery codes should not return an error")
+	assert.Len(t, recovery_codes, 10)
+	require.NoError(t, VerifyMFACode(db, ctx, user, recovery_codes[0]), "Recovery code should be accepted")
+	assert.ErrorIs(t, VerifyMFACode(db, ctx, user, recovery_codes[0]), ErrMFACodeInvalid, "Recovery code should be single use")
+	assert.ErrorIs(t, VerifyMFACode(db, ctx, user, "not-a-code"), ErrMFACodeInvalid)
+}
+
+func TestMFARequired(t *testing.T) {
+	db := memoryDB(t, &models.User{}, &models.Role{}, &models.App{})
+	ctx := context.Background()
+
+	app := models.App{Name: "secure", Description: "secure app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	plain := models.Role{Name: "plain", Description: "plain", Active: true}
+	app_role := models.Role{Name: "app_role", Description: "app role", Active: true, AppID: sql.NullInt64{Int64: int64(app.ID), Valid: true}}
+	require.NoError(t, db.Create(&plain).Error)
+	require.NoError(t, db.Create(&app_role).Error)
+
+	user := models.User{Name: "policy", Email: "policy@mail.com", Password: "password", Roles: []models.Role{plain}}
+	require.NoError(t, db.Create(&user).Error)
+
+	required, err := MFARequired(db, ctx, user)
+	require.NoError(t, err)
+	assert.False(t, required, "No policy applies yet")
+
+	db.Model(&plain).Update("mfa_required", true)
+	required, _ = MFARequired(db, ctx, user)
+	assert.True(t, required, "Role policy should apply")
+
+	db.Model(&plain).Update("mfa_required", false)
+	db.Model(&user).Association("Roles").Append(&app_role)
+	db.Model(&app).Update("mfa_required", true)
+	required, _ = MFARequired(db, ctx, user)
+	assert.True(t, required, "App policy should apply")
+}
+
+func TestMFAChallengeToken(t *testing.T) {
+	mfa_token, err := CreateMFAChallengeToken(31, true)
+	require.NoError(t, err, "Challenge token creation should not return an error")
+
+	mfa_claim, err := ParseMFAChallengeToken(mfa_token)
+	require.NoError(t, err, "Challenge token should verify")
+	assert.Equal(t, 31, mfa_claim.UserID)
+	assert.True(t, mfa_claim.Enrollment)
+
+	_, err = ParseJWTToken(mfa_token)
+	assert.ErrorIs(t, err, ErrNotAccessToken, "Challenge token should not be an access token")
+
+	access_token, err := CreateJWTToken("test@mail.com", "uuid", 31, []string{"admin"}, 5)
+	require.NoError(t, err)
+	_, err = ParseMFAChallengeToken(access_token)
+	assert.ErrorIs(t, err, ErrMFAChallengeInvalid, "Access token should not be a challenge token")
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAuthorizationCodeInvalid = errors.New("invalid authorization code")
+	ErrAuthorizationCodeExpired = errors.New("authorization code has expired")
+	ErrPKCEMismatch             = errors.New("code verifier does not match the code challenge")
+)
+
+// Claims of the OpenID Connect id_token
+type IDTokenClaim struct {
+	jwt.RegisteredClaims
+	Nonce    string `json:"nonce,omitempty"`
+	AuthTime int64  `json:"auth_time"`
+	Email    string `json:"email,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Public base url of this server, used as the OpenID Connect issuer
+func OIDCIssuer() string {
+	return configs.AppConfig.GetOrDefault("OIDC_ISSUER", "http://localhost:"+configs.AppConfig.Get("HTTP_PORT"))
+}
+
+func authorizationCodeLifeTime() time.Duration {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("OIDC_CODE_LIFE_TIME", "60"))
+	return time.Duration(life_time) * time.Second
+}
+
+// S256 PKCE check, base64url(sha256(code_verifier)) must equal the code challenge
+func VerifyPKCE(code_verifier string, code_challenge string) bool {
+	if len(code_verifier) < 43 || len(code_verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(code_verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:]) == code_challenge
+}
+
+// Stores the authorization code and returns the code handed to the client
+func IssueAuthorizationCode(db *gorm.DB, ctx context.Context, authorization_code models.AuthorizationCode) (string, error) {
+	code, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	authorization_code.CodeHash = HashOpaqueToken(code)
+	authorization_code.ExpiresAt = time.Now().UTC().Add(authorizationCodeLifeTime())
+	authorization_code.CreatedAt = time.Now().UTC()
+	if err := db.WithContext(ctx).Create(&authorization_code).Error; err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// Marks the code as used after checking it was issued to the client for the redirect uri
+// and that the code verifier matches the challenge sent to the authorization endpoint
+func ConsumeAuthorizationCode(db *gorm.DB, ctx context.Context, code string, client_id string, redirect_uri string, code_verifier string) (models.AuthorizationCode, error) {
+	var authorization_code models.AuthorizationCode
+	if res := db.WithContext(ctx).Model(&models.AuthorizationCode{}).Where("code_hash = ?", HashOpaqueToken(code)).First(&authorization_code); res.Error != nil {
+		return models.AuthorizationCode{}, ErrAuthorizationCodeInvalid
+	}
+
+	if authorization_code.UsedAt.Valid || authorization_code.ClientID != client_id || authorization_code.RedirectURI != redirect_uri {
+		return models.AuthorizationCode{}, ErrAuthorizationCodeInvalid
+	}
+	if time.Now().UTC().After(authorization_code.ExpiresAt) {
+		return models.AuthorizationCode{}, ErrAuthorizationCodeExpired
+	}
+	if !VerifyPKCE(code_verifier, authorization_code.CodeChallenge) {
+		return models.AuthorizationCode{}, ErrPKCEMismatch
+	}
+
+	// conditional update so the code can only be exchanged once
+	res := db.WithContext(ctx).Model(&models.AuthorizationCode{}).
+		Where("id = ? AND used_at IS NULL", authorization_code.ID).
+		Update("used_at", time.Now().UTC())
+	if res.Error != nil {
+		return models.AuthorizationCode{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return models.AuthorizationCode{}, ErrAuthorizationCodeInvalid
+	}
+
+	return authorization_code, nil
+}
+
+// Signs an id_token for the user with the client as audience
+func CreateIDToken(user models.User, client_id string, nonce string, auth_time time.Time, duration int) (string, error) {
+	now := time.Now().UTC()
+	id_claim := IDTokenClaim{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    OIDCIssuer(),
+			Subject:   user.UUID,
+			Audience:  jwt.ClaimStrings{client_id},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(duration) * time.Minute)),
+		},
+		Nonce:    nonce,
+		AuthTime: auth_time.Unix(),
+		Email:    user.Email,
+		Name:     user.Name,
+	}
+	return signClaims(id_claim)
+}
+
+// Removes expired authorization codes, run by the scheduler
+func CleanExpiredAuthorizationCodes() {
+	db, _ := database.ReturnSession()
+	db.Where("expires_at < ?", time.Now().UTC()).Delete(&models.AuthorizationCode{})
+}
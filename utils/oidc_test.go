@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mJ92K9SmDdpgw91RwOjGI9ttOjDwN3U"
+	testCodeChallenge = "05v0LQw9d2zGmudg7A-iT0GjOSAPAEzs2ed6vJ_ElcQ"
+)
+
+func TestVerifyPKCE(t *testing.T) {
+	assert.True(t, VerifyPKCE(testCodeVerifier, testCodeChallenge), "Matching verifier should be accepted")
+	assert.False(t, VerifyPKCE(testCodeVerifier+"x", testCodeChallenge), "Other verifier should be rejected")
+	assert.False(t, VerifyPKCE("short", "short"), "Too short verifier should be rejected")
+}
+
+func TestAuthorizationCode(t *testing.T) {
+	db := memoryDB(t, &models.AuthorizationCode{})
+	ctx := context.Background()
+
+	code, err := IssueAuthorizationCode(db, ctx, models.AuthorizationCode{
+		ClientID:      "client",
+		UserID:        3,
+		RedirectURI:   "https://app.example.com/callback",
+		Scope:         "openid",
+		Nonce:         "nonce",
+		CodeChallenge: testCodeChallenge,
+		AuthTime:      time.Now().UTC(),
+	})
+	require.NoError(t, err, "Issuing should not return an error")
+
+	_, err = ConsumeAuthorizationCode(db, ctx, code, "other", "https://app.example.com/callback", testCodeVerifier)
+	assert.ErrorIs(t, err, ErrAuthorizationCodeInvalid, "Code should be bound to the client")
+	_, err = ConsumeAuthorizationCode(db, ctx, code, "client", "https://evil.example.com/callback", testCodeVerifier)
+	assert.ErrorIs(t, err, ErrAuthorizationCodeInvalid, "Code should be bound to the redirect uri")
+	_, err = ConsumeAuthorizationCode(db, ctx, code, "client", "https://app.example.com/callback", testCodeVerifier+"x")
+	assert.ErrorIs(t, err, ErrPKCEMismatch, "Code verifier should be checked")
+
+	authorization_code, err := ConsumeAuthorizationCode(db, ctx, code, "client", "https://app.example.com/callback", testCodeVerifier)
+	require.NoError(t, err, "Valid exchange should be accepted")
+	assert.Equal(t, uint(3), authorization_code.UserID)
+	assert.Equal(t, "nonce", authorization_code.Nonce)
+
+	_, err = ConsumeAuthorizationCode(db, ctx, code, "client", "https://app.example.com/callback", testCodeVerifier)
+	assert.ErrorIs(t, err, ErrAuthorizationCodeInvalid, "Code should only be exchanged once")
+}
+
+func TestCreateIDToken(t *testing.T) {
+	user := models.User{Name: "Test", Email: "test@mail.com", UUID: "user-uuid"}
+	auth_time := time.Now().UTC().Add(-time.Minute)
+
+	id_token, err := CreateIDToken(user, "client", "nonce", auth_time, 5)
+	require.NoError(t, err, "Signing should not return an error")
+
+	claims := IDTokenClaim{}
+	_, err = jwt.ParseWithClaims(id_token, &claims, func(token *jwt.Token) (interface{}, error) {
+		signing_key, err := SigningKeys.Get(token.Header["kid"].(string))
+		return signing_key.Public, err
+	}, jwt.WithAudience("client"), jwt.WithIssuer(OIDCIssuer()))
+	require.NoError(t, err, "Id token should verify with the published key")
+	assert.Equal(t, "user-uuid", claims.Subject)
+	assert.Equal(t, "nonce", claims.Nonce)
+	assert.Equal(t, auth_time.Unix(), claims.AuthTime)
+	assert.Equal(t, "test@mail.com", claims.Email)
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"blue-admin.com/models"
+	"blue-admin.com/passwords"
+	"gorm.io/gorm"
+)
+
+func appPasswordPolicy(password_policy models.PasswordPolicy) passwords.Policy {
+	return passwords.Policy{
+		MinLength:     password_policy.MinLength,
+		RequireUpper:  password_policy.RequireUpper,
+		RequireLower:  password_policy.RequireLower,
+		RequireDigit:  password_policy.RequireDigit,
+		RequireSymbol: password_policy.RequireSymbol,
+		DenyCommon:    password_policy.DenyCommon,
+		HistoryCount:  password_policy.HistoryCount,
+		MaxAgeDays:    password_policy.MaxAgeDays,
+	}
+}
+
+// The global policy tightened by the policies of the apps the user has active roles in
+func UserPasswordPolicy(db *gorm.DB, ctx context.Context, user_id uint) (passwords.Policy, error) {
+	policy := passwords.GlobalPolicy()
+	if user_id == 0 {
+		return policy, nil
+	}
+
+	var password_policies []models.PasswordPolicy
+	query_string := `SELECT DISTINCT password_policies.* FROM password_policies
+		INNER JOIN roles ON roles.app_id = password_policies.app_id
+		INNER JOIN user_roles ON user_roles.role_id = roles.id
+		WHERE user_roles.user_id = ? AND roles.active = true`
+	if res := db.WithContext(ctx).Raw(query_string, user_id).Scan(&password_policies); res.Error != nil {
+		return passwords.Policy{}, res.Error
+	}
+	for _, value := range password_policies {
+		policy = policy.Merge(appPasswordPolicy(value))
+	}
+	return policy, nil
+}
+
+// Checks the password against the user's policy including the reuse of previous passwords,
+// violations are returned as a *passwords.PolicyError
+func CheckUserPassword(db *gorm.DB, ctx context.Context, user models.User, password string) (passwords.Policy, error) {
+	policy, err := UserPasswordPolicy(db, ctx, user.ID)
+	if err != nil {
+		return policy, err
+	}
+	if err := policy.Check(password); err != nil {
+		return policy, err
+	}
+	if user.ID == 0 || policy.HistoryCount <= 0 {
+		return policy, nil
+	}
+
+	var history []models.PasswordHistory
+	if res := db.WithContext(ctx).Model(&models.PasswordHistory{}).Where("user_id = ?", user.ID).Order("id desc").Limit(policy.HistoryCount).Find(&history); res.Error != nil {
+		return policy, res.Error
+	}
+	// users created before the history was kept only have their current password to compare
+	previous := []string{user.Password}
+	for _, value := range history {
+		previous = append(previous, value.PasswordHash)
+	}
+	for _, hash := range previous {
+		if hash != "" && PasswordsMatch(hash, password) {
+			return policy, &passwords.PolicyError{Violations: []passwords.Violation{{
+				Field:   "password",
+				Rule:    "history",
+				Message: fmt.Sprintf("must not be one of the last %v passwords", policy.HistoryCount),
+			}}}
+		}
+	}
+	return policy, nil
+}
+
+// Adds the hash to the user's history keeping only the newest entries
+func RecordPasswordHistory(db *gorm.DB, ctx context.Context, user_id uint, password_hash string, keep int) error {
+	if keep < 1 {
+		keep = 1
+	}
+	if err := db.WithContext(ctx).Create(&models.PasswordHistory{UserID: user_id, PasswordHash: password_hash, CreatedAt: time.Now().UTC()}).Error; err != nil {
+		return err
+	}
+
+	var history_ids []uint
+	if res := db.WithContext(ctx).Model(&models.PasswordHistory{}).Where("user_id = ?", user_id).Order("id desc").Pluck("id", &history_ids); res.Error != nil {
+		return res.Error
+	}
+	if len(history_ids) <= keep {
+		return nil
+	}
+	return db.WithContext(ctx).Where("id IN ?", history_ids[keep:]).Delete(&models.PasswordHistory{}).Error
+}
+
+// The user has to choose a new password before getting tokens, either forced by an admin
+// or because the password is older than the policy allows
+func PasswordChangeDue(db *gorm.DB, ctx context.Context, user models.User) (bool, error) {
+	// passwords of directory users are managed by the directory
+	if user.AuthSource != "" && user.AuthSource != models.AuthSourceLocal {
+		return false, nil
+	}
+	if user.PasswordChangeRequired {
+		return true, nil
+	}
+	policy, err := UserPasswordPolicy(db, ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	if policy.MaxAgeDays <= 0 {
+		return false, nil
+	}
+
+	changed_at := user.DateRegistred
+	if user.PasswordChangedAt != nil {
+		changed_at = *user.PasswordChangedAt
+	}
+	return time.Now().UTC().After(changed_at.Add(time.Duration(policy.MaxAgeDays) * 24 * time.Hour)), nil
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"blue-admin.com/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserPasswordPolicy(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "8")
+	t.Setenv("PASSWORD_MAX_AGE_DAYS", "0")
+	db := memoryDB(t, &models.User{}, &models.Role{}, &models.App{}, &models.PasswordPolicy{}, &models.PasswordHistory{})
+	ctx := context.Background()
+
+	app := models.App{Name: "strict", Description: "strict app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	role := models.Role{Name: "strict_role", Description: "strict role", Active: true, AppID: sql.NullInt64{Int64: int64(app.ID), Valid: true}}
+	require.NoError(t, db.Create(&role).Error)
+	require.NoError(t, db.Create(&models.PasswordPolicy{AppID: app.ID, MinLength: 14, RequireSymbol: true, MaxAgeDays: 30}).Error)
+
+	user := models.User{Name: "policy", Email: "policy@mail.com", Password: "password", Roles: []models.Role{role}}
+	require.NoError(t, db.Create(&user).Error)
+
+	policy, err := UserPasswordPolicy(db, ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 14, policy.MinLength, "App policy should tighten the global one")
+	assert.True(t, policy.RequireSymbol)
+	assert.Equal(t, 30, policy.MaxAgeDays)
+
+	_, err = CheckUserPassword(db, ctx, user, "Short-1")
+	var policy_err *passwords.PolicyError
+	require.True(t, errors.As(err, &policy_err))
+	assert.Equal(t, "min_length", policy_err.Violations[0].Rule)
+
+	due, err := PasswordChangeDue(db, ctx, user)
+	require.NoError(t, err)
+	assert.False(t, due, "Fresh password should not be expired")
+	db.Model(&user).Update("password_changed_at", time.Now().UTC().Add(-31*24*time.Hour))
+	db.First(&user, user.ID)
+	due, _ = PasswordChangeDue(db, ctx, user)
+	assert.True(t, due, "Password older than the max age should be expired")
+}
+
+func TestPasswordHistory(t *testing.T) {
+	t.Setenv("PASSWORD_HISTORY_COUNT", "2")
+	t.Setenv("ARGON2_MEMORY", "1024")
+	t.Setenv("ARGON2_ITERATIONS", "1")
+	db := memoryDB(t, &models.User{}, &models.PasswordPolicy{}, &models.PasswordHistory{}, &models.LoginAttempt{}, &models.RefreshToken{}, &models.UserRevocation{}, &models.Session{})
+	ctx := context.Background()
+
+	user := models.User{Name: "history", Email: "history@mail.com", Password: "first-password"}
+	require.NoError(t, db.Create(&user).Error)
+
+	_, err := CheckUserPassword(db, ctx, user, "first-password")
+	var policy_err *passwords.PolicyError
+	require.True(t, errors.As(err, &policy_err), "Current password should not be reused")
+	assert.Equal(t, "history", policy_err.Violations[0].Rule)
+
+	require.NoError(t, SetUserPassword(db, ctx, user, "second-password"))
+	require.NoError(t, SetUserPassword(db, ctx, user, "third-password"))
+	db.First(&user, user.ID)
+	assert.Error(t, SetUserPassword(db, ctx, user, "second-password"), "Password in the history should be rejected")
+	require.NoError(t, SetUserPassword(db, ctx, user, "fourth-password"))
+
+	var count int64
+	db.Model(&models.PasswordHistory{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(2), count, "History should be trimmed to the configured count")
+
+	db.First(&user, user.ID)
+	assert.NoError(t, SetUserPassword(db, ctx, user, "first-password"), "Passwords older than the history can be used again")
+}
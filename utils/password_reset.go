@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/messages"
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+var ErrResetTokenInvalid = errors.New("invalid or expired password reset token")
+
+// Reset token lifetime in minutes from PASSWORD_RESET_LIFE_TIME, defaults to 30 minutes
+func passwordResetLifeTime() time.Duration {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("PASSWORD_RESET_LIFE_TIME", "30"))
+	if life_time <= 0 {
+		life_time = 30
+	}
+	return time.Duration(life_time) * time.Minute
+}
+
+// Issues a single use reset token for the user, tokens issued before stop working
+func IssuePasswordResetToken(db *gorm.DB, ctx context.Context, user_id uint) (string, error) {
+	token, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	tx := db.WithContext(ctx).Begin()
+	if err := tx.Where("user_id = ? AND used_at IS NULL", user_id).Delete(&models.PasswordResetToken{}).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	reset_token := models.PasswordResetToken{
+		TokenHash: HashOpaqueToken(token),
+		UserID:    user_id,
+		ExpiresAt: now.Add(passwordResetLifeTime()),
+		CreatedAt: now,
+	}
+	if err := tx.Create(&reset_token).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Returns the record of a usable reset token without using it up
+func FindPasswordResetToken(db *gorm.DB, ctx context.Context, token string) (models.PasswordResetToken, error) {
+	var reset_token models.PasswordResetToken
+	if res := db.WithContext(ctx).Model(&models.PasswordResetToken{}).Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", HashOpaqueToken(token), time.Now().UTC()).First(&reset_token); res.Error != nil {
+		return models.PasswordResetToken{}, ErrResetTokenInvalid
+	}
+	return reset_token, nil
+}
+
+// Marks the reset token as used and returns its record, expired or used tokens are rejected
+func ConsumePasswordResetToken(db *gorm.DB, ctx context.Context, token string) (models.PasswordResetToken, error) {
+	var reset_token models.PasswordResetToken
+	if res := db.WithContext(ctx).Model(&models.PasswordResetToken{}).Where("token_hash = ?", HashOpaqueToken(token)).First(&reset_token); res.Error != nil {
+		return models.PasswordResetToken{}, ErrResetTokenInvalid
+	}
+
+	// conditional update so the token can only be used once
+	now := time.Now().UTC()
+	res := db.WithContext(ctx).Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", reset_token.ID, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return models.PasswordResetToken{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return models.PasswordResetToken{}, ErrResetTokenInvalid
+	}
+	return reset_token, nil
+}
+
+// Link sent to the user, PASSWORD_RESET_URL is the page of the admin ui that posts the new password
+func PasswordResetLink(token string) string {
+	reset_url := configs.AppConfig.GetOrDefault("PASSWORD_RESET_URL", OIDCIssuer()+"/admin/reset-password")
+	return reset_url + "?" + url.Values{"token": {token}}.Encode()
+}
+
+// Queues the reset link on the email queue (EMAIL_QUEUE) consumed by the email consumer
+func SendPasswordResetEmail(user models.User, token string) error {
+	return messages.PublishEmailQueue(messages.EmailMessage{
+		Emails:  []string{user.Email},
+		Subject: "Password Reset",
+		Message: fmt.Sprintf("Use the following link to choose a new password, it expires in %v minutes: %v", int(passwordResetLifeTime().Minutes()), PasswordResetLink(token)),
+	}, configs.AppConfig.GetOrDefault("EMAIL_QUEUE", "email"))
+}
+
+// Sets a new password that passes the user's policy, clears a forced change and a lockout
+// and signs the user out everywhere
+func SetUserPassword(db *gorm.DB, ctx context.Context, user models.User, password string) error {
+	policy, err := CheckUserPassword(db, ctx, user, password)
+	if err != nil {
+		return err
+	}
+	hashed_password, err := HashFunc(password)
+	if err != nil {
+		return err
+	}
+	if res := db.WithContext(ctx).Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"password":                 hashed_password,
+		"password_change_required": false,
+		"password_changed_at":      time.Now().UTC(),
+		"locked_until":             nil,
+	}); res.Error != nil {
+		return res.Error
+	}
+	if err := RecordPasswordHistory(db, ctx, user.ID, hashed_password, policy.HistoryCount); err != nil {
+		return err
+	}
+	RecordLoginSuccess(db, ctx, user.Email)
+	return RevokeUserTokens(db, ctx, user.ID)
+}
+
+// Removes expired and used reset tokens, run by the scheduler
+func CleanExpiredPasswordResetTokens() {
+	db, err := database.ReturnSession()
+	if err != nil {
+		return
+	}
+	db.Where("expires_at < ? OR used_at IS NOT NULL", time.Now().UTC()).Delete(&models.PasswordResetToken{})
+}
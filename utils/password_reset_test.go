@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetToken(t *testing.T) {
+	db := memoryDB(t, &models.PasswordResetToken{})
+	ctx := context.Background()
+
+	first, err := IssuePasswordResetToken(db, ctx, 5)
+	require.NoError(t, err, "Issuing should not return an error")
+	second, err := IssuePasswordResetToken(db, ctx, 5)
+	require.NoError(t, err)
+
+	_, err = ConsumePasswordResetToken(db, ctx, first)
+	assert.ErrorIs(t, err, ErrResetTokenInvalid, "Earlier token should stop working")
+
+	reset_token, err := ConsumePasswordResetToken(db, ctx, second)
+	require.NoError(t, err, "Latest token should be accepted")
+	assert.Equal(t, uint(5), reset_token.UserID)
+
+	_, err = ConsumePasswordResetToken(db, ctx, second)
+	assert.ErrorIs(t, err, ErrResetTokenInvalid, "Token should be single use")
+
+	expired, _ := IssuePasswordResetToken(db, ctx, 6)
+	db.Model(&models.PasswordResetToken{}).Where("user_id = ?", 6).Update("expires_at", time.Now().UTC().Add(-time.Minute))
+	_, err = ConsumePasswordResetToken(db, ctx, expired)
+	assert.ErrorIs(t, err, ErrResetTokenInvalid, "Expired token should be rejected")
+}
+
+func TestSetUserPassword(t *testing.T) {
+	db := memoryDB(t, &models.User{}, &models.PasswordPolicy{}, &models.PasswordHistory{}, &models.LoginAttempt{}, &models.RefreshToken{}, &models.UserRevocation{}, &models.Session{})
+	ctx := context.Background()
+
+	locked_until := time.Now().UTC().Add(time.Hour)
+	user := models.User{Name: "reset", Email: "reset@mail.com", Password: "old-password", PasswordChangeRequired: true, LockedUntil: &locked_until}
+	require.NoError(t, db.Create(&user).Error)
+
+	require.NoError(t, SetUserPassword(db, ctx, user, "new-password"))
+	var updated models.User
+	db.First(&updated, user.ID)
+	assert.True(t, PasswordsMatch(updated.Password, "new-password"), "New password should be stored hashed")
+	assert.False(t, updated.PasswordChangeRequired, "Forced change should be cleared")
+	assert.Nil(t, updated.LockedUntil, "Lockout should be cleared")
+}
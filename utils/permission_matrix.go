@@ -0,0 +1,355 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/messages"
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// id of the single permission_versions row
+const permissionVersionID = 1
+
+// A grant with its parsed condition, nil for unconditional grants. A condition that no longer parses
+// is broken and the grant allows nothing.
+type routeGrant struct {
+	RouteGrant
+	condition *Condition
+	broken    bool
+}
+
+// An endpoint's route template split in segments with the grants on it
+type routeRule struct {
+	method     string
+	route_path string
+	segments   []string
+	endpoints  []string
+	grants     []routeGrant
+}
+
+// Outcome of an authorization with the endpoint matched and the role and feature that allowed it
+type AuthorizationDecision struct {
+	Allowed   bool   `json:"allowed"`
+	Role      string `json:"role,omitempty"`
+	Feature   string `json:"feature,omitempty"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	Method    string `json:"method,omitempty"`
+	RoutePath string `json:"route_path,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// Decides whether any of the roles may make the request, superuser is allowed everywhere
+// and a role passes with a grant that has no condition or a condition the request meets
+func (route routeRule) decide(roles []string, request ConditionRequest) AuthorizationDecision {
+	decision := AuthorizationDecision{Method: route.method, RoutePath: route.route_path}
+	if len(route.endpoints) > 0 {
+		decision.Endpoint = route.endpoints[0]
+	}
+	if slices.Contains(roles, "superuser") {
+		decision.Allowed = true
+		decision.Role = "superuser"
+		decision.Reason = "superuser is allowed everywhere"
+		return decision
+	}
+	if route.segments == nil {
+		decision.Reason = "no endpoint matches the request"
+		return decision
+	}
+	if len(route.grants) == 0 {
+		decision.Reason = "no role is granted the endpoint"
+		return decision
+	}
+
+	conditional := false
+	for _, grant := range route.grants {
+		if !slices.Contains(roles, grant.Role) {
+			continue
+		}
+		if grant.broken || !grant.condition.Allows(request) {
+			conditional = true
+			continue
+		}
+		decision.Allowed = true
+		decision.Role = grant.Role
+		decision.Feature = grant.Feature
+		decision.Reason = fmt.Sprintf("%v is granted %v", grant.Role, grant.Feature)
+		if grant.InheritedFrom != "" {
+			decision.Reason += " inherited from " + grant.InheritedFrom
+		}
+		if grant.condition != nil {
+			decision.Reason += " and the request meets its condition"
+		}
+		return decision
+	}
+
+	if conditional {
+		decision.Reason = "the request does not meet the conditions of the roles' grants"
+	} else {
+		decision.Reason = "none of the roles is granted the endpoint"
+	}
+	return decision
+}
+
+// In memory copy of the endpoint to roles matrix of the apps authorized by this process, the route
+// validator uses this deployment's app (APP_ID) and the decision api loads the other apps on first use.
+// Requests are matched by method and path against the endpoints' route templates, see MatchRoutePattern.
+// Changes made through this process are applied after their commit and broadcast on the PERMISSIONS_EXCHANGE
+// exchange, other instances and prefork children reload on the message. Without a broker, or when a message
+// is lost, they see the version row bumped and reload within PERMISSION_MATRIX_TTL seconds.
+type permissionMatrix struct {
+	mu         sync.RWMutex
+	version    int64
+	apps       map[string][]routeRule
+	checked_at time.Time
+}
+
+var Permissions = &permissionMatrix{apps: make(map[string][]routeRule)}
+
+func permissionMatrixTTL() time.Duration {
+	ttl, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("PERMISSION_MATRIX_TTL", "5"))
+	return time.Duration(ttl) * time.Second
+}
+
+func permissionVersion(db *gorm.DB, ctx context.Context) (int64, error) {
+	var permission_version models.PermissionVersion
+	res := db.WithContext(ctx).Model(&models.PermissionVersion{}).Where("id = ?", permissionVersionID).Limit(1).Find(&permission_version)
+	return permission_version.Version, res.Error
+}
+
+// Route rules of the app, most specific templates first so the first match decides
+func loadAppRoutes(db *gorm.DB, ctx context.Context, app_uuid string) ([]routeRule, error) {
+	route_roles, err := GetAppRoutesReturn(app_uuid, db, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]routeRule, 0, len(route_roles))
+	for _, route := range route_roles {
+		rule := routeRule{method: route.Method, route_path: route.RoutePath, segments: routeSegments(route.RoutePath), endpoints: route.Endpoints}
+		for _, grant := range route.Grants {
+			// conditions are validated when saved, one broken in the database only disables its grant
+			condition, err := ParseCondition(grant.Condition)
+			if err != nil {
+				fmt.Printf("ignoring the grant of %v on %v %v: %v\n", grant.Role, route.Method, route.RoutePath, err)
+			}
+			rule.grants = append(rule.grants, routeGrant{RouteGrant: grant, condition: condition, broken: err != nil})
+		}
+		routes = append(routes, rule)
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return moreSpecificRoute(routes[i].segments, routes[j].segments)
+	})
+	return routes, nil
+}
+
+// Rebuilds the matrix of this deployment's app and of every app loaded before from the database,
+// the version is read first so a change committed while loading is picked up by the next check
+func (matrix *permissionMatrix) Reload(db *gorm.DB, ctx context.Context) error {
+	version, err := permissionVersion(db, ctx)
+	if err != nil {
+		return err
+	}
+
+	matrix.mu.RLock()
+	app_uuids := []string{BlueAdminAudience()}
+	for app_uuid := range matrix.apps {
+		app_uuids = appendRole(app_uuids, app_uuid)
+	}
+	matrix.mu.RUnlock()
+
+	apps := make(map[string][]routeRule, len(app_uuids))
+	for _, app_uuid := range app_uuids {
+		if apps[app_uuid], err = loadAppRoutes(db, ctx, app_uuid); err != nil {
+			return err
+		}
+	}
+
+	matrix.mu.Lock()
+	matrix.version = version
+	matrix.apps = apps
+	matrix.checked_at = time.Now()
+	matrix.mu.Unlock()
+	return nil
+}
+
+// Reloads the matrix when the stored version moved, checked at most once per ttl
+func (matrix *permissionMatrix) refresh() {
+	matrix.mu.RLock()
+	fresh := time.Since(matrix.checked_at) < permissionMatrixTTL()
+	current := matrix.version
+	matrix.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	// on failure the previous matrix is kept until the next check
+	matrix.mu.Lock()
+	matrix.checked_at = time.Now()
+	matrix.mu.Unlock()
+
+	db, err := database.ReturnSession()
+	if err != nil {
+		return
+	}
+	version, err := permissionVersion(db, context.Background())
+	if err != nil || version == current {
+		return
+	}
+	if err := matrix.Reload(db, context.Background()); err != nil {
+		fmt.Printf("reloading the permission matrix failed: %v\n", err)
+	}
+}
+
+// Route rules of the app, loaded on first use
+func (matrix *permissionMatrix) appRoutes(app_uuid string) []routeRule {
+	matrix.refresh()
+
+	matrix.mu.RLock()
+	routes, loaded := matrix.apps[app_uuid]
+	matrix.mu.RUnlock()
+	if loaded {
+		return routes
+	}
+
+	db, err := database.ReturnSession()
+	if err != nil {
+		return nil
+	}
+	routes, err = loadAppRoutes(db, context.Background(), app_uuid)
+	if err != nil {
+		fmt.Printf("loading the permission matrix of %v failed: %v\n", app_uuid, err)
+		return nil
+	}
+	matrix.mu.Lock()
+	matrix.apps[app_uuid] = routes
+	matrix.mu.Unlock()
+	return routes
+}
+
+// most specific endpoint of the app matching the method and path
+func (matrix *permissionMatrix) route(app_uuid string, method string, path string) routeRule {
+	segments := routeSegments(path)
+	for _, route := range matrix.appRoutes(app_uuid) {
+		if MatchRouteMethod(route.method, method) && matchSegments(route.segments, segments) {
+			return route
+		}
+	}
+	return routeRule{method: strings.ToUpper(method)}
+}
+
+// Roles allowed on the request to this deployment's app, those of the most specific endpoint matching
+// its method and path, whether the conditions of their grants hold is not checked. Requests no endpoint
+// matches get none.
+func (matrix *permissionMatrix) Roles(method string, path string) []string {
+	route := matrix.route(BlueAdminAudience(), method, path)
+	roles := make([]string, 0, len(route.grants))
+	for _, grant := range route.grants {
+		roles = appendRole(roles, grant.Role)
+	}
+	return roles
+}
+
+// Whether any of the roles may make the request to this deployment's app, see Decide
+func (matrix *permissionMatrix) Allowed(method string, path string, roles []string, request ConditionRequest) bool {
+	return matrix.Decide(BlueAdminAudience(), method, path, roles, request).Allowed
+}
+
+// Decides a request to the app by its method and path, the most specific endpoint matching them is used
+func (matrix *permissionMatrix) Decide(app_uuid string, method string, path string, roles []string, request ConditionRequest) AuthorizationDecision {
+	return matrix.route(app_uuid, method, path).decide(roles, request)
+}
+
+// Decides a request to the app's endpoint named
+func (matrix *permissionMatrix) DecideEndpoint(app_uuid string, endpoint string, roles []string, request ConditionRequest) AuthorizationDecision {
+	for _, route := range matrix.appRoutes(app_uuid) {
+		if slices.Contains(route.endpoints, endpoint) {
+			decision := route.decide(roles, request)
+			decision.Endpoint = endpoint
+			return decision
+		}
+	}
+	return routeRule{endpoints: []string{endpoint}}.decide(roles, request)
+}
+
+// Version of the loaded matrix
+func (matrix *permissionMatrix) Version() int64 {
+	matrix.mu.RLock()
+	defer matrix.mu.RUnlock()
+	return matrix.version
+}
+
+// Bumps the stored version, reloads this process' copy and broadcasts the change so every instance reloads.
+// Called by the controllers after committing a change to apps, roles, features or endpoints.
+func InvalidatePermissions(db *gorm.DB, ctx context.Context) error {
+	err := db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"version": gorm.Expr("permission_versions.version + 1"), "updated_at": time.Now().UTC()}),
+	}).Create(&models.PermissionVersion{ID: permissionVersionID, Version: 1, UpdatedAt: time.Now().UTC()}).Error
+	if err == nil {
+		err = Permissions.Reload(db, ctx)
+	}
+	if err != nil {
+		fmt.Printf("invalidating the permission matrix failed: %v\n", err)
+		return err
+	}
+	publishPermissionsChanged(Permissions.Version())
+	return nil
+}
+
+func permissionsExchange() string {
+	return configs.AppConfig.GetOrDefault("PERMISSIONS_EXCHANGE", "permissions")
+}
+
+// Publishes in the background like EmitSecurityEvent, a lost message is covered by the version row
+func publishPermissionsChanged(version int64) {
+	if configs.AppConfig.Get("RABBIT_URI") == "" {
+		return
+	}
+	go func() {
+		change := messages.PermissionsChanged{Version: version, CreatedAt: time.Now().UTC()}
+		if err := messages.PublishPermissionsChanged(change, permissionsExchange()); err != nil {
+			fmt.Printf("publishing the permission change %v failed: %v\n", version, err)
+		}
+	}()
+}
+
+// Reloads the matrix on a broadcast change unless this process already loaded the version
+func (matrix *permissionMatrix) changed(change messages.PermissionsChanged) {
+	if change.Version != 0 && change.Version == matrix.Version() {
+		return
+	}
+	db, err := database.ReturnSession()
+	if err != nil {
+		return
+	}
+	if err := matrix.Reload(db, context.Background()); err != nil {
+		fmt.Printf("reloading the permission matrix failed: %v\n", err)
+	}
+}
+
+// Subscribes the process to the broadcast permission changes, resubscribing after the broker connection
+// is lost. Without a RABBIT_URI the matrix relies on the version row alone.
+func SubscribePermissionChanges() {
+	if configs.AppConfig.Get("RABBIT_URI") == "" {
+		return
+	}
+	go func() {
+		for {
+			err := messages.PermissionsSubscriber(permissionsExchange(), Permissions.changed)
+			fmt.Printf("permission change subscription ended: %v\n", err)
+			// changes missed meanwhile are picked up from the version row
+			time.Sleep(permissionMatrixTTL() + time.Second)
+		}
+	}()
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"blue-admin.com/database"
+	"blue-admin.com/messages"
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionMatrixReload(t *testing.T) {
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	app := models.App{Name: "permissions", Description: "permissions app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	t.Setenv("APP_ID", app.UUID)
+	t.Setenv("PERMISSION_MATRIX_TTL", "0")
+
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+	editor := models.Role{Name: "permissions_editor", Description: "editor", Active: true, AppID: app_id}
+	viewer := models.Role{Name: "permissions_viewer", Description: "viewer", Active: true, AppID: app_id}
+	require.NoError(t, db.Create(&editor).Error)
+	require.NoError(t, db.Create(&viewer).Error)
+	audit := models.Feature{Name: "permissions_audit", Description: "audit", Active: true, Roles: []models.Role{editor}}
+	require.NoError(t, db.Create(&audit).Error)
+	feature_id := sql.NullInt64{Int64: int64(audit.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "get_audit_get", RoutePath: "/api/v1/audit/:audit_id", Method: "GET", Description: "audit", FeatureID: feature_id}).Error)
+
+	require.NoError(t, Permissions.Reload(db, ctx))
+	assert.Equal(t, []string{"permissions_editor"}, Permissions.Roles("GET", "/api/v1/audit/7"))
+	version := Permissions.Version()
+
+	// changes made through this instance apply right after the invalidation
+	require.NoError(t, db.Model(&audit).Association("Roles").Append(&viewer))
+	require.NoError(t, InvalidatePermissions(db, ctx))
+	assert.Equal(t, version+1, Permissions.Version(), "Invalidating should bump the version")
+	assert.ElementsMatch(t, []string{"permissions_editor", "permissions_viewer"}, Permissions.Roles("GET", "/api/v1/audit/7"))
+
+	// another instance deactivating the role is seen through the version row
+	require.NoError(t, db.Model(&editor).UpdateColumn("active", false).Error)
+	assert.ElementsMatch(t, []string{"permissions_editor", "permissions_viewer"}, Permissions.Roles("GET", "/api/v1/audit/7"), "Unversioned changes should not reload")
+	require.NoError(t, db.Model(&models.PermissionVersion{}).Where("id = ?", permissionVersionID).UpdateColumn("version", version+2).Error)
+	assert.Equal(t, []string{"permissions_viewer"}, Permissions.Roles("GET", "/api/v1/audit/7"), "A bumped version should reload the matrix")
+	assert.Equal(t, version+2, Permissions.Version())
+}
+
+// Other instances broadcast their changes, the version row is only polled once the ttl passed
+func TestPermissionMatrixBroadcast(t *testing.T) {
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	app := models.App{Name: "broadcast", Description: "broadcast app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	t.Setenv("APP_ID", app.UUID)
+	t.Setenv("PERMISSION_MATRIX_TTL", "3600")
+
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+	editor := models.Role{Name: "broadcast_editor", Description: "editor", Active: true, AppID: app_id}
+	viewer := models.Role{Name: "broadcast_viewer", Description: "viewer", Active: true, AppID: app_id}
+	require.NoError(t, db.Create(&editor).Error)
+	require.NoError(t, db.Create(&viewer).Error)
+	reports := models.Feature{Name: "broadcast_reports", Description: "reports", Active: true, Roles: []models.Role{editor, viewer}}
+	require.NoError(t, db.Create(&reports).Error)
+	feature_id := sql.NullInt64{Int64: int64(reports.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "get_broadcast_get", RoutePath: "/api/v1/broadcast", Method: "GET", Description: "reports", FeatureID: feature_id}).Error)
+
+	require.NoError(t, Permissions.Reload(db, ctx))
+	version := Permissions.Version()
+
+	// another instance deactivating the role and bumping the version is not polled within the ttl
+	require.NoError(t, db.Model(&editor).UpdateColumn("active", false).Error)
+	require.NoError(t, db.Model(&models.PermissionVersion{}).Where("id = ?", permissionVersionID).UpdateColumn("version", version+1).Error)
+	assert.ElementsMatch(t, []string{"broadcast_editor", "broadcast_viewer"}, Permissions.Roles("GET", "/api/v1/broadcast"), "The version row should only be polled after the ttl")
+
+	// its broadcast reloads right away
+	Permissions.changed(messages.PermissionsChanged{Version: version + 1})
+	assert.Equal(t, []string{"broadcast_viewer"}, Permissions.Roles("GET", "/api/v1/broadcast"), "A broadcast change should reload the matrix")
+	assert.Equal(t, version+1, Permissions.Version())
+
+	// a change of the version already loaded, like this process' own, is not reloaded again
+	require.NoError(t, db.Model(&viewer).UpdateColumn("active", false).Error)
+	Permissions.changed(messages.PermissionsChanged{Version: version + 1})
+	assert.Equal(t, []string{"broadcast_viewer"}, Permissions.Roles("GET", "/api/v1/broadcast"), "The loaded version should not reload")
+}
+
+func TestPermissionMatrixRoutes(t *testing.T) {
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	app := models.App{Name: "routes", Description: "routes app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	t.Setenv("APP_ID", app.UUID)
+
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+	admin := models.Role{Name: "routes_admin", Description: "admin", Active: true, AppID: app_id}
+	member := models.Role{Name: "routes_member", Description: "member", Active: true, AppID: app_id}
+	require.NoError(t, db.Create(&admin).Error)
+	require.NoError(t, db.Create(&member).Error)
+	manage := models.Feature{Name: "routes_manage", Description: "manage", Active: true, Roles: []models.Role{admin}}
+	profile := models.Feature{Name: "routes_profile", Description: "profile", Active: true, Roles: []models.Role{member}}
+	require.NoError(t, db.Create(&manage).Error)
+	require.NoError(t, db.Create(&profile).Error)
+
+	manage_id := sql.NullInt64{Int64: int64(manage.ID), Valid: true}
+	profile_id := sql.NullInt64{Int64: int64(profile.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "routes_get_member", RoutePath: "/api/v1/members/:member_id", Method: "GET", Description: "member", FeatureID: manage_id}).Error)
+	require.NoError(t, db.Create(&models.Endpoint{Name: "routes_delete_member", RoutePath: "/api/v1/members/:member_id", Method: "DELETE", Description: "member", FeatureID: manage_id}).Error)
+	require.NoError(t, db.Create(&models.Endpoint{Name: "routes_get_me", RoutePath: "/api/v1/members/me", Method: "GET", Description: "me", FeatureID: profile_id}).Error)
+	require.NoError(t, db.Create(&models.Endpoint{Name: "routes_files", RoutePath: "/api/v1/files/*", Method: "*", Description: "files", FeatureID: profile_id}).Error)
+	private := models.Feature{Name: "routes_private", Description: "private", Active: true, Roles: []models.Role{admin}}
+	require.NoError(t, db.Create(&private).Error)
+	require.NoError(t, db.Model(&private).UpdateColumn("active", false).Error)
+	require.NoError(t, db.Create(&models.Endpoint{Name: "routes_private_file", RoutePath: "/api/v1/files/private", Method: "GET", Description: "private", FeatureID: sql.NullInt64{Int64: int64(private.ID), Valid: true}}).Error)
+
+	// endpoints of another app's features are not part of this app's matrix
+	other_app := models.App{Name: "routes_other", Description: "other routes app", Active: true}
+	require.NoError(t, db.Create(&other_app).Error)
+	other_role := models.Role{Name: "routes_other_reader", Description: "reader", Active: true, AppID: sql.NullInt64{Int64: int64(other_app.ID), Valid: true}}
+	require.NoError(t, db.Create(&other_role).Error)
+	other_files := models.Feature{Name: "routes_other_files", Description: "files", Active: true, Roles: []models.Role{other_role}}
+	require.NoError(t, db.Create(&other_files).Error)
+	require.NoError(t, db.Create(&models.Endpoint{Name: "routes_other_report", RoutePath: "/api/v1/files/report", Method: "GET", Description: "report", FeatureID: sql.NullInt64{Int64: int64(other_files.ID), Valid: true}}).Error)
+	require.NoError(t, db.Create(&models.Endpoint{Name: "routes_unassigned", RoutePath: "/api/v1/files/unassigned", Method: "GET", Description: "no feature"}).Error)
+
+	require.NoError(t, Permissions.Reload(db, ctx))
+	assert.Equal(t, []string{"routes_admin"}, Permissions.Roles("GET", "/api/v1/members/12"))
+	assert.Equal(t, []string{"routes_member"}, Permissions.Roles("GET", "/api/v1/members/me"), "Static segments should win over parameters")
+	assert.Equal(t, []string{"routes_admin"}, Permissions.Roles("DELETE", "/api/v1/members/me"), "Only endpoints of the method should match")
+	assert.Equal(t, []string{"routes_member"}, Permissions.Roles("PUT", "/api/v1/files/reports/2024.pdf"))
+	assert.Empty(t, Permissions.Roles("POST", "/api/v1/members/12"), "Requests without an endpoint should get no roles")
+
+	// an endpoint of the app whose grants are all deactivated denies instead of falling through to the wildcard
+	assert.Empty(t, Permissions.Roles("GET", "/api/v1/files/private"))
+	request := ConditionRequest{Time: time.Now()}
+	assert.False(t, Permissions.Allowed("GET", "/api/v1/files/private", []string{"routes_member"}, request), "Endpoints without grants should deny")
+	assert.True(t, Permissions.Allowed("PUT", "/api/v1/files/private", []string{"routes_member"}, request), "Other methods should still match the wildcard")
+	decision := Permissions.Decide(app.UUID, "GET", "/api/v1/files/private", []string{"routes_member"}, request)
+	assert.Equal(t, "routes_private_file", decision.Endpoint)
+	assert.Equal(t, "no role is granted the endpoint", decision.Reason)
+
+	// the wildcard of the app still covers paths only other apps or no feature have endpoints for
+	decision = Permissions.Decide(app.UUID, "GET", "/api/v1/files/report", []string{"routes_member"}, request)
+	assert.True(t, decision.Allowed, decision.Reason)
+	assert.Equal(t, "routes_files", decision.Endpoint, "Endpoints of other apps should not be named")
+	assert.True(t, Permissions.Allowed("GET", "/api/v1/files/unassigned", []string{"routes_member"}, request))
+	decision = Permissions.Decide(other_app.UUID, "GET", "/api/v1/files/report", []string{"routes_other_reader"}, request)
+	assert.True(t, decision.Allowed, decision.Reason)
+	assert.Equal(t, "routes_other_report", decision.Endpoint)
+	assert.False(t, Permissions.Decide(other_app.UUID, "GET", "/api/v1/files/private", []string{"routes_member"}, request).Allowed)
+}
+
+func TestPermissionMatrixConditions(t *testing.T) {
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	app := models.App{Name: "conditions", Description: "conditions app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	t.Setenv("APP_ID", app.UUID)
+
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+	operator := models.Role{Name: "conditions_operator", Description: "operator", Active: true, AppID: app_id}
+	require.NoError(t, db.Create(&operator).Error)
+	lead := models.Role{Name: "conditions_lead", Description: "lead", Active: true, AppID: app_id, Parents: []models.Role{operator}}
+	require.NoError(t, db.Create(&lead).Error)
+	payouts := models.Feature{Name: "conditions_payouts", Description: "payouts", Active: true, Roles: []models.Role{operator}}
+	require.NoError(t, db.Create(&payouts).Error)
+	feature_id := sql.NullInt64{Int64: int64(payouts.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "conditions_post_payout", RoutePath: "/api/v1/payouts", Method: "POST", Description: "payouts", FeatureID: feature_id}).Error)
+	require.NoError(t, db.Model(&models.FeatureRole{}).Where("feature_id = ? AND role_id = ?", payouts.ID, operator.ID).Update("grant_condition", `ip in "10.0.0.0/8" && mfa`).Error)
+
+	require.NoError(t, Permissions.Reload(db, ctx))
+	office := ConditionRequest{IP: "10.0.0.5", Time: time.Now(), Claims: UserClaim{MFA: true}}
+	assert.True(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_operator"}, office))
+	assert.True(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_lead"}, office), "Inherited grants should keep their condition")
+	home := ConditionRequest{IP: "203.0.113.9", Time: time.Now(), Claims: UserClaim{MFA: true}}
+	assert.False(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_operator"}, home))
+	assert.False(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_lead"}, home))
+	assert.True(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"superuser"}, home))
+
+	// an unconditional grant of the lead lets it through anywhere
+	require.NoError(t, db.Model(&payouts).Association("Roles").Append(&lead))
+	require.NoError(t, Permissions.Reload(db, ctx))
+	assert.True(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_lead"}, home))
+	assert.False(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_operator"}, home))
+
+	// a condition broken in the database disables only its grant
+	require.NoError(t, db.Model(&models.FeatureRole{}).Where("feature_id = ? AND role_id = ?", payouts.ID, operator.ID).Update("grant_condition", `ip in`).Error)
+	require.NoError(t, Permissions.Reload(db, ctx))
+	assert.False(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_operator"}, office))
+	assert.True(t, Permissions.Allowed("POST", "/api/v1/payouts", []string{"conditions_lead"}, office))
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+)
+
+// Refresh token lifetime in minutes from REFRESH_TOKEN_LIFE_TIME, defaults to 7 days
+func RefreshTokenLifeTime() time.Duration {
+	life_time, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("REFRESH_TOKEN_LIFE_TIME", "10080"))
+	if life_time <= 0 {
+		life_time = 10080
+	}
+	return time.Duration(life_time) * time.Minute
+}
+
+// Generates random url safe opaque token of n bytes
+func GenerateOpaqueToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Only the sha256 of opaque tokens is persisted
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issues a new refresh token for the user in the given token family
+// an empty family_id starts a new family (a new login), mfa records whether the login used a second factor
+func IssueRefreshToken(db *gorm.DB, ctx context.Context, user_id uint, family_id string, mfa bool) (string, error) {
+	return IssueRefreshTokenUntil(db, ctx, user_id, family_id, mfa, time.Now().UTC().Add(RefreshTokenLifeTime()))
+}
+
+// Issues a refresh token expiring at the given time, used for the lifetimes of the session's token policy
+func IssueRefreshTokenUntil(db *gorm.DB, ctx context.Context, user_id uint, family_id string, mfa bool, expires_at time.Time) (string, error) {
+	token, err := GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if family_id == "" {
+		gen, _ := uuid.NewV7()
+		family_id = gen.String()
+	}
+
+	refresh_token := models.RefreshToken{
+		TokenHash: HashOpaqueToken(token),
+		FamilyID:  family_id,
+		UserID:    user_id,
+		MFA:       mfa,
+		ExpiresAt: expires_at,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := db.WithContext(ctx).Create(&refresh_token).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Marks the refresh token as used and returns its record so a replacement can be issued in the same family.
+// Presenting an already used or revoked token revokes the whole family.
+func ConsumeRefreshToken(db *gorm.DB, ctx context.Context, token string) (models.RefreshToken, error) {
+	var refresh_token models.RefreshToken
+	if res := db.WithContext(ctx).Model(&models.RefreshToken{}).Where("token_hash = ?", HashOpaqueToken(token)).First(&refresh_token); res.Error != nil {
+		return models.RefreshToken{}, ErrRefreshTokenInvalid
+	}
+
+	if refresh_token.Revoked || refresh_token.UsedAt.Valid {
+		RevokeRefreshFamily(db, ctx, refresh_token.FamilyID)
+		return models.RefreshToken{}, ErrRefreshTokenReused
+	}
+
+	if time.Now().UTC().After(refresh_token.ExpiresAt) {
+		return models.RefreshToken{}, ErrRefreshTokenExpired
+	}
+
+	// conditional update so two concurrent uses can not both succeed
+	res := db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked = ?", refresh_token.ID, false).
+		Update("used_at", time.Now().UTC())
+	if res.Error != nil {
+		return models.RefreshToken{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		RevokeRefreshFamily(db, ctx, refresh_token.FamilyID)
+		return models.RefreshToken{}, ErrRefreshTokenReused
+	}
+
+	return refresh_token, nil
+}
+
+// Revokes every refresh token issued in the family
+func RevokeRefreshFamily(db *gorm.DB, ctx context.Context, family_id string) error {
+	return db.WithContext(ctx).Model(&models.RefreshToken{}).Where("family_id = ?", family_id).Update("revoked", true).Error
+}
+
+// Revokes the family of the presented refresh token if it was issued to the user
+func RevokeRefreshToken(db *gorm.DB, ctx context.Context, token string, user_id uint) error {
+	var refresh_token models.RefreshToken
+	if res := db.WithContext(ctx).Model(&models.RefreshToken{}).Where("token_hash = ? AND user_id = ?", HashOpaqueToken(token), user_id).First(&refresh_token); res.Error != nil {
+		return ErrRefreshTokenInvalid
+	}
+	return RevokeRefreshFamily(db, ctx, refresh_token.FamilyID)
+}
+
+// Revokes every refresh token of the user
+func RevokeUserRefreshTokens(db *gorm.DB, ctx context.Context, user_id uint) error {
+	return db.WithContext(ctx).Model(&models.RefreshToken{}).Where("user_id = ?", user_id).Update("revoked", true).Error
+}
+
+// Removes expired refresh tokens, run by the scheduler
+func CleanExpiredRefreshTokens() {
+	db, _ := database.ReturnSession()
+	db.Where("expires_at < ?", time.Now().UTC()).Delete(&models.RefreshToken{})
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func memoryDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err, "Opening memory database should not return an error")
+	require.NoError(t, db.AutoMigrate(tables...), "Migrating memory database should not return an error")
+
+	// revocations cached by a test belong to its own database
+	resetRevocations()
+	t.Cleanup(resetRevocations)
+	return db
+}
+
+func resetRevocations() {
+	Revocations.mu.Lock()
+	Revocations.tokens = make(map[string]time.Time)
+	Revocations.users = make(map[uint]time.Time)
+	Revocations.sessions = make(map[string]time.Time)
+	Revocations.mu.Unlock()
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	db := memoryDB(t, &models.RefreshToken{})
+	ctx := context.Background()
+
+	first, err := IssueRefreshToken(db, ctx, 4, "", false)
+	require.NoError(t, err, "Issuing should not return an error")
+
+	record, err := ConsumeRefreshToken(db, ctx, first)
+	require.NoError(t, err, "First use should be accepted")
+	assert.Equal(t, uint(4), record.UserID, "Token should be bound to the user")
+
+	second, err := IssueRefreshToken(db, ctx, record.UserID, record.FamilyID, record.MFA)
+	require.NoError(t, err, "Rotating should not return an error")
+
+	// replaying the first token revokes the family including the rotated one
+	_, err = ConsumeRefreshToken(db, ctx, first)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused, "Reuse should be detected")
+
+	_, err = ConsumeRefreshToken(db, ctx, second)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused, "Family should be revoked after reuse")
+
+	_, err = ConsumeRefreshToken(db, ctx, "unknown")
+	assert.ErrorIs(t, err, ErrRefreshTokenInvalid, "Unknown token should be invalid")
+}
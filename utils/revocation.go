@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// In memory copy of the revocation tables so token validation does not hit the database on every request.
+// Local revocations are applied immediately, revocations made by other instances or prefork
+// children are picked up on the next reload after REVOCATION_CACHE_TTL seconds.
+type revocationCache struct {
+	mu        sync.RWMutex
+	tokens    map[string]time.Time
+	users     map[uint]time.Time
+	sessions  map[string]time.Time
+	loaded_at time.Time
+}
+
+var Revocations = &revocationCache{
+	tokens:   make(map[string]time.Time),
+	users:    make(map[uint]time.Time),
+	sessions: make(map[string]time.Time),
+}
+
+func revocationCacheTTL() time.Duration {
+	ttl, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("REVOCATION_CACHE_TTL", "30"))
+	return time.Duration(ttl) * time.Second
+}
+
+// Reloads the cache from the database when it is older than the configured ttl
+func (cache *revocationCache) refresh() {
+	cache.mu.RLock()
+	fresh := time.Since(cache.loaded_at) < revocationCacheTTL()
+	cache.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	// on failure the previous state is kept until the next reload
+	defer func() {
+		cache.mu.Lock()
+		cache.loaded_at = time.Now()
+		cache.mu.Unlock()
+	}()
+
+	db, err := database.ReturnSession()
+	if err != nil {
+		return
+	}
+
+	var revoked_tokens []models.RevokedToken
+	if err := db.Model(&models.RevokedToken{}).Where("expires_at > ?", time.Now().UTC()).Find(&revoked_tokens).Error; err != nil {
+		return
+	}
+	var user_revocations []models.UserRevocation
+	if err := db.Model(&models.UserRevocation{}).Find(&user_revocations).Error; err != nil {
+		return
+	}
+	var revoked_sessions []models.Session
+	if err := db.Model(&models.Session{}).Select("session_id", "revoked_at").Where("revoked_at IS NOT NULL").Find(&revoked_sessions).Error; err != nil {
+		return
+	}
+
+	tokens := make(map[string]time.Time, len(revoked_tokens))
+	for _, value := range revoked_tokens {
+		tokens[value.JTI] = value.ExpiresAt
+	}
+	users := make(map[uint]time.Time, len(user_revocations))
+	for _, value := range user_revocations {
+		users[value.UserID] = value.NotBefore
+	}
+	sessions := make(map[string]time.Time, len(revoked_sessions))
+	for _, value := range revoked_sessions {
+		sessions[value.SessionID] = *value.RevokedAt
+	}
+
+	cache.mu.Lock()
+	cache.tokens = tokens
+	cache.users = users
+	cache.sessions = sessions
+	cache.mu.Unlock()
+}
+
+// Reports whether the token id was revoked or the token was issued before the user's not before time
+func (cache *revocationCache) IsRevoked(jti string, user_id uint, issued_at time.Time) bool {
+	cache.refresh()
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if _, ok := cache.tokens[jti]; ok && jti != "" {
+		return true
+	}
+	// iat and not before share microsecond precision, tokens issued up to the revocation are revoked
+	if not_before, ok := cache.users[user_id]; ok && !issued_at.After(not_before) {
+		return true
+	}
+	return false
+}
+
+// Reports whether the session the token was issued in has been signed out
+func (cache *revocationCache) IsSessionRevoked(session_id string) bool {
+	if session_id == "" {
+		return false
+	}
+	cache.refresh()
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	_, ok := cache.sessions[session_id]
+	return ok
+}
+
+func (cache *revocationCache) addToken(jti string, expires_at time.Time) {
+	cache.mu.Lock()
+	cache.tokens[jti] = expires_at
+	cache.mu.Unlock()
+}
+
+func (cache *revocationCache) addUser(user_id uint, not_before time.Time) {
+	cache.mu.Lock()
+	cache.users[user_id] = not_before
+	cache.mu.Unlock()
+}
+
+// the id is copied as it may point into a request buffer the server reuses
+func (cache *revocationCache) addSession(session_id string, revoked_at time.Time) {
+	cache.mu.Lock()
+	cache.sessions[strings.Clone(session_id)] = revoked_at
+	cache.mu.Unlock()
+}
+
+// Revokes a single access token by its jti until it expires
+func RevokeToken(db *gorm.DB, ctx context.Context, claims UserClaim) error {
+	if claims.ID == "" {
+		return errors.New("token has no id to revoke")
+	}
+
+	expires_at := time.Now().UTC()
+	if claims.ExpiresAt != nil {
+		expires_at = claims.ExpiresAt.Time
+	}
+
+	revoked_token := models.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    uint(claims.UserID),
+		ExpiresAt: expires_at,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked_token).Error; err != nil {
+		return err
+	}
+	Revocations.addToken(claims.ID, expires_at)
+	return nil
+}
+
+// Revokes every access and refresh token issued to the user so far, tokens issued afterwards stay valid
+func RevokeUserTokens(db *gorm.DB, ctx context.Context, user_id uint) error {
+	not_before := time.Now().UTC().Truncate(jwt.TimePrecision)
+	user_revocation := models.UserRevocation{UserID: user_id, NotBefore: not_before}
+	if err := db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"not_before"}),
+	}).Create(&user_revocation).Error; err != nil {
+		return err
+	}
+	Revocations.addUser(user_id, not_before)
+
+	// the not before time already rejects their tokens, the sessions are only marked for the listing
+	if err := db.WithContext(ctx).Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", user_id).Update("revoked_at", not_before).Error; err != nil {
+		return err
+	}
+	return RevokeUserRefreshTokens(db, ctx, user_id)
+}
+
+// Removes revoked token ids of already expired tokens, run by the scheduler
+func CleanExpiredRevocations() {
+	db, _ := database.ReturnSession()
+	db.Where("expires_at < ?", time.Now().UTC()).Delete(&models.RevokedToken{})
+}
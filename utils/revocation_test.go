@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocations(t *testing.T) {
+	db := memoryDB(t, &models.RevokedToken{}, &models.UserRevocation{}, &models.RefreshToken{}, &models.Session{})
+	ctx := context.Background()
+
+	// keeping the cache from reloading out of the memory database
+	Revocations.mu.Lock()
+	Revocations.loaded_at = time.Now()
+	Revocations.mu.Unlock()
+
+	issued_at := time.Now().UTC().Add(-time.Minute)
+	claims := UserClaim{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "revoked-jti",
+			IssuedAt:  jwt.NewNumericDate(issued_at),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+		},
+		UserID: 7,
+	}
+
+	assert.False(t, Revocations.IsRevoked(claims.ID, 7, issued_at), "Token should not be revoked yet")
+	require.NoError(t, RevokeToken(db, ctx, claims), "Revoking token should not return an error")
+	assert.True(t, Revocations.IsRevoked(claims.ID, 7, issued_at), "Token should be revoked")
+	assert.False(t, Revocations.IsRevoked("other-jti", 7, issued_at), "Other tokens should stay valid")
+
+	require.NoError(t, RevokeUserTokens(db, ctx, 7), "Revoking user tokens should not return an error")
+	assert.True(t, Revocations.IsRevoked("other-jti", 7, issued_at), "Tokens issued before should be revoked")
+	assert.False(t, Revocations.IsRevoked("other-jti", 7, time.Now().UTC().Add(time.Second)), "Tokens issued after should be valid")
+	assert.False(t, Revocations.IsRevoked("other-jti", 8, issued_at), "Other users should not be affected")
+
+	// tokens issued right before and right after the revocation, within the same second, are told apart
+	before, err := CreateJWTToken("nine@mail.com", "uuid", 9, []string{"admin"}, 5)
+	require.NoError(t, err)
+	require.NoError(t, RevokeUserTokens(db, ctx, 9))
+	after, err := CreateJWTToken("nine@mail.com", "uuid", 9, []string{"admin"}, 5)
+	require.NoError(t, err)
+	_, err = ParseJWTToken(before)
+	assert.ErrorIs(t, err, ErrTokenRevoked, "Tokens issued just before the revocation should be revoked")
+	_, err = ParseJWTToken(after)
+	assert.NoError(t, err, "Tokens issued right after the revocation should be valid")
+
+	var count int64
+	db.Model(&models.UserRevocation{}).Where("user_id = ?", 7).Count(&count)
+	assert.Equal(t, int64(1), count, "Revocation should be persisted")
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleCycle    = errors.New("the parent role already inherits the role")
+	ErrRoleOtherApp = errors.New("a role can only inherit roles of its own app")
+)
+
+type roleParentID struct {
+	RoleID   uint
+	ParentID uint
+}
+
+type roleParentName struct {
+	RoleName   string
+	ParentName string
+}
+
+// Every role the role inherits from, directly or through its parents
+func roleAncestors(parents map[uint][]uint, role_id uint) []uint {
+	ancestors := make([]uint, 0)
+	pending := slices.Clone(parents[role_id])
+	for len(pending) > 0 {
+		parent_id := pending[0]
+		pending = pending[1:]
+		if slices.Contains(ancestors, parent_id) {
+			continue
+		}
+		ancestors = append(ancestors, parent_id)
+		pending = append(pending, parents[parent_id]...)
+	}
+	return ancestors
+}
+
+// Checks the role may inherit the parent, both must belong to the same app and the parent
+// must not inherit the role already as the hierarchy would become a cycle
+func CheckRoleParent(db *gorm.DB, ctx context.Context, role models.Role, parent models.Role) error {
+	if role.ID == parent.ID {
+		return ErrRoleCycle
+	}
+	if !role.AppID.Valid || role.AppID != parent.AppID {
+		return ErrRoleOtherApp
+	}
+
+	var role_parents []roleParentID
+	if res := db.WithContext(ctx).Table("role_parents").
+		Select("role_parents.role_id, role_parents.parent_id").
+		Joins("INNER JOIN roles ON roles.id = role_parents.role_id").
+		Where("roles.app_id = ?", role.AppID).
+		Scan(&role_parents); res.Error != nil {
+		return res.Error
+	}
+	parents := make(map[uint][]uint)
+	for _, role_parent := range role_parents {
+		parents[role_parent.RoleID] = append(parents[role_parent.RoleID], role_parent.ParentID)
+	}
+
+	if slices.Contains(roleAncestors(parents, parent.ID), role.ID) {
+		return ErrRoleCycle
+	}
+	return nil
+}
+
+// Active roles of the app mapped to the active roles directly inheriting them,
+// a deactivated role neither grants nor passes on inherited permissions
+func roleInheritors(db *gorm.DB, ctx context.Context, app_uuid string) (map[string][]string, error) {
+	var role_parents []roleParentName
+
+	query_string := `SELECT roles.name as role_name,parents.name as parent_name FROM role_parents
+		INNER JOIN roles ON roles.id = role_parents.role_id
+		INNER JOIN roles parents ON parents.id = role_parents.parent_id
+		INNER JOIN apps ON apps.id = roles.app_id
+		WHERE apps.uuid = ?
+		  AND parents.app_id = roles.app_id
+		  AND roles.active = true
+		  AND parents.active = true`
+	if res := db.WithContext(ctx).Raw(query_string, app_uuid).Scan(&role_parents); res.Error != nil {
+		return nil, res.Error
+	}
+
+	inheritors := make(map[string][]string)
+	for _, role_parent := range role_parents {
+		inheritors[role_parent.ParentName] = appendRole(inheritors[role_parent.ParentName], role_parent.RoleName)
+	}
+	return inheritors, nil
+}
+
+// The roles with every role inheriting them transitively, so admin passes where editor,
+// which admin inherits, is allowed
+func expandInheritedRoles(roles []string, inheritors map[string][]string) []string {
+	expanded := slices.Clone(roles)
+	for i := 0; i < len(expanded); i++ {
+		for _, inheritor := range inheritors[expanded[i]] {
+			expanded = appendRole(expanded, inheritor)
+		}
+	}
+	return expanded
+}
+
+// Passes the grants of the route's roles on to the roles inheriting them, inherited grants
+// are appended and passed on in turn
+func inheritGrants(route *RouteRoles, inheritors map[string][]string) {
+	for i := 0; i < len(route.Grants); i++ {
+		grant := route.Grants[i]
+		origin := grant.InheritedFrom
+		if origin == "" {
+			origin = grant.Role
+		}
+		for _, inheritor := range inheritors[grant.Role] {
+			route.grant(RouteGrant{Role: inheritor, Feature: grant.Feature, Condition: grant.Condition, InheritedFrom: origin})
+		}
+	}
+}
+
+// Expands the roles allowed on every key of the app's matrix with the roles inheriting them
+func inheritRoleMatrix(db *gorm.DB, ctx context.Context, app_uuid string, role_matrix map[string][]string) error {
+	inheritors, err := roleInheritors(db, ctx, app_uuid)
+	if err != nil {
+		return err
+	}
+	for key, roles := range role_matrix {
+		role_matrix[key] = expandInheritedRoles(roles, inheritors)
+	}
+	return nil
+}
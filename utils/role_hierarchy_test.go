@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleHierarchy(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.Role{}, &models.Feature{}, &models.Endpoint{}, &models.FeatureRole{})
+	ctx := context.Background()
+
+	app := models.App{Name: "hierarchy", Description: "hierarchy app", Active: true}
+	other_app := models.App{Name: "other", Description: "other app", Active: true}
+	require.NoError(t, db.Create(&app).Error)
+	require.NoError(t, db.Create(&other_app).Error)
+	app_id := sql.NullInt64{Int64: int64(app.ID), Valid: true}
+	admin := models.Role{Name: "admin", Description: "admin", Active: true, AppID: app_id}
+	editor := models.Role{Name: "editor", Description: "editor", Active: true, AppID: app_id}
+	viewer := models.Role{Name: "viewer", Description: "viewer", Active: true, AppID: app_id}
+	outsider := models.Role{Name: "outsider", Description: "outsider", Active: true, AppID: sql.NullInt64{Int64: int64(other_app.ID), Valid: true}}
+	for _, role := range []*models.Role{&admin, &editor, &viewer, &outsider} {
+		require.NoError(t, db.Create(role).Error)
+	}
+
+	require.NoError(t, CheckRoleParent(db, ctx, editor, viewer))
+	require.NoError(t, db.Model(&editor).Association("Parents").Append(&viewer))
+	require.NoError(t, CheckRoleParent(db, ctx, admin, editor))
+	require.NoError(t, db.Model(&admin).Association("Parents").Append(&editor))
+
+	assert.ErrorIs(t, CheckRoleParent(db, ctx, viewer, admin), ErrRoleCycle, "Inheriting a role that inherits it should be rejected")
+	assert.ErrorIs(t, CheckRoleParent(db, ctx, viewer, viewer), ErrRoleCycle)
+	assert.ErrorIs(t, CheckRoleParent(db, ctx, admin, outsider), ErrRoleOtherApp)
+
+	reports := models.Feature{Name: "reports", Description: "reports", Active: true, Roles: []models.Role{viewer}}
+	require.NoError(t, db.Create(&reports).Error)
+	feature_id := sql.NullInt64{Int64: int64(reports.ID), Valid: true}
+	require.NoError(t, db.Create(&models.Endpoint{Name: "get_reports_get", RoutePath: "/api/v1/reports", Method: "GET", Description: "reports", FeatureID: feature_id}).Error)
+
+	matrix, err := GetAppFeaturesReturn(app.UUID, db, ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"viewer", "editor", "admin"}, matrix["get_reports_get"], "Roles should inherit transitively")
+	routes, err := GetAppRoutesReturn(app.UUID, db, ctx)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.ElementsMatch(t, []string{"viewer", "editor", "admin"}, routes[0].Roles)
+
+	// a deactivated role stops passing on what it inherits
+	require.NoError(t, db.Model(&editor).UpdateColumn("active", false).Error)
+	matrix, _ = GetAppFeaturesReturn(app.UUID, db, ctx)
+	assert.Equal(t, []string{"viewer"}, matrix["get_reports_get"])
+}
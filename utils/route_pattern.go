@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"strings"
+)
+
+// Endpoint route paths are templates in fiber's syntax, matched a whole segment at a time:
+//
+//	:name   one non empty segment
+//	:name?  an optional segment
+//	*       any number of segments, none included
+//	+       at least one segment
+//
+// Other segments are compared ignoring case and a trailing slash is ignored, as fiber routes them.
+func routeSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
+
+// how loosely a template segment matches, lower is more specific
+func segmentRank(segment string) int {
+	switch {
+	case segment == "*" || segment == "+":
+		return 3
+	case strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "?"):
+		return 2
+	case strings.HasPrefix(segment, ":"):
+		return 1
+	}
+	return 0
+}
+
+// Whether the pattern should be tried before the other one, the first segment that differs decides
+// so /api/v1/user/me wins over /api/v1/user/:user_id and both over /api/v1/*
+func moreSpecificRoute(pattern []string, other []string) bool {
+	for i := 0; i < len(pattern) && i < len(other); i++ {
+		if rank, other_rank := segmentRank(pattern[i]), segmentRank(other[i]); rank != other_rank {
+			return rank < other_rank
+		}
+	}
+	return len(pattern) > len(other)
+}
+
+func matchSegments(pattern []string, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	segment := pattern[0]
+	switch segmentRank(segment) {
+	case 3:
+		from := 0
+		if segment == "+" {
+			from = 1
+		}
+		for i := from; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	case 2:
+		if matchSegments(pattern[1:], path) {
+			return true
+		}
+		return len(path) > 0 && path[0] != "" && matchSegments(pattern[1:], path[1:])
+	case 1:
+		return len(path) > 0 && path[0] != "" && matchSegments(pattern[1:], path[1:])
+	}
+	return len(path) > 0 && strings.EqualFold(segment, path[0]) && matchSegments(pattern[1:], path[1:])
+}
+
+// Whether the request path matches the route template
+func MatchRoutePattern(pattern string, path string) bool {
+	return matchSegments(routeSegments(pattern), routeSegments(path))
+}
+
+// Whether the request method matches the endpoint's method, * matches any and HEAD requests are served by GET routes
+func MatchRouteMethod(endpoint_method string, method string) bool {
+	if endpoint_method == "*" || strings.EqualFold(endpoint_method, method) {
+		return true
+	}
+	return strings.EqualFold(method, "HEAD") && strings.EqualFold(endpoint_method, "GET")
+}
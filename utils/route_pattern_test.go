@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRoutePattern(t *testing.T) {
+	assert.True(t, MatchRoutePattern("/api/v1/role", "/api/v1/role"))
+	assert.True(t, MatchRoutePattern("/api/v1/role", "/API/v1/Role/"), "Case and a trailing slash should be ignored")
+	assert.False(t, MatchRoutePattern("/api/v1/role", "/api/v1/roles"))
+
+	assert.True(t, MatchRoutePattern("/api/v1/userrole/:user_id/:role_id", "/api/v1/userrole/4/2"))
+	assert.False(t, MatchRoutePattern("/api/v1/userrole/:user_id/:role_id", "/api/v1/userrole/4"), "Parameters should need a segment")
+	assert.False(t, MatchRoutePattern("/api/v1/role/:role_id", "/api/v1/role/4/users"))
+
+	assert.True(t, MatchRoutePattern("/api/v1/invitation/:app_id?", "/api/v1/invitation"))
+	assert.True(t, MatchRoutePattern("/api/v1/invitation/:app_id?", "/api/v1/invitation/3"))
+
+	assert.True(t, MatchRoutePattern("/docs/*", "/docs"), "* should match no segment")
+	assert.True(t, MatchRoutePattern("/docs/*", "/docs/swagger/index.html"))
+	assert.False(t, MatchRoutePattern("/docs/+", "/docs"), "+ should need a segment")
+	assert.True(t, MatchRoutePattern("/api/*/role", "/api/v1/role"))
+
+	assert.True(t, MatchRouteMethod("GET", "get"))
+	assert.True(t, MatchRouteMethod("GET", "HEAD"), "GET routes should serve HEAD requests")
+	assert.True(t, MatchRouteMethod("*", "DELETE"))
+	assert.False(t, MatchRouteMethod("POST", "GET"))
+}
+
+func TestMoreSpecificRoute(t *testing.T) {
+	assert.True(t, moreSpecificRoute(routeSegments("/api/v1/user/me"), routeSegments("/api/v1/user/:user_id")))
+	assert.True(t, moreSpecificRoute(routeSegments("/api/v1/user/:user_id"), routeSegments("/api/v1/*")))
+	assert.False(t, moreSpecificRoute(routeSegments("/api/v1/*"), routeSegments("/api/v1/user")))
+}
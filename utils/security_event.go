@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/messages"
+)
+
+// Security event types published on the EVENTS_QUEUE queue
+const (
+	EventAccountLocked   = "ACCOUNT_LOCKED"
+	EventAccountUnlocked = "ACCOUNT_UNLOCKED"
+	EventIPBlocked       = "IP_BLOCKED"
+)
+
+// Publishes the event in the background so a slow or missing broker never delays a request,
+// without a RABBIT_URI the event is only logged
+func EmitSecurityEvent(event messages.SecurityEvent) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	if len(event.Details) > 0 {
+		fmt.Printf("security event %v user=%v email=%v ip=%v details=%v\n", event.Type, event.UserID, event.Email, event.IP, event.Details)
+	} else {
+		fmt.Printf("security event %v user=%v email=%v ip=%v\n", event.Type, event.UserID, event.Email, event.IP)
+	}
+	if configs.AppConfig.Get("RABBIT_URI") == "" {
+		return
+	}
+
+	go func() {
+		if err := messages.PublishEventQueue(event, configs.AppConfig.GetOrDefault("EVENTS_QUEUE", "events")); err != nil {
+			fmt.Printf("publishing security event %v failed: %v\n", event.Type, err)
+		}
+	}()
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found or already signed out")
+	ErrSessionClient   = errors.New("the session was started for another client")
+)
+
+// Last seen times are written at most once per interval for each session or api key,
+// the time of the last write is kept in memory so most requests do not touch the database
+type lastSeenThrottle struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+var sessionsSeen = &lastSeenThrottle{seen: make(map[string]time.Time)}
+
+// Reports whether a write is due for the key and records it as written
+func (throttle *lastSeenThrottle) due(key string, now time.Time, interval time.Duration) bool {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	if last_seen, ok := throttle.seen[key]; ok && now.Sub(last_seen) < interval {
+		return false
+	}
+	throttle.seen[key] = now
+	return true
+}
+
+func (throttle *lastSeenThrottle) forget(key string) {
+	throttle.mu.Lock()
+	delete(throttle.seen, key)
+	throttle.mu.Unlock()
+}
+
+func (throttle *lastSeenThrottle) expire(before time.Time) {
+	throttle.mu.Lock()
+	for key, seen_at := range throttle.seen {
+		if seen_at.Before(before) {
+			delete(throttle.seen, key)
+		}
+	}
+	throttle.mu.Unlock()
+}
+
+func sessionLastSeenInterval() time.Duration {
+	interval, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("SESSION_LAST_SEEN_INTERVAL", "60"))
+	if interval <= 0 {
+		interval = 60
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// Records a new login, the session id is used as the refresh token family of the login.
+// client_id is the OpenID Connect client of a code flow login, empty for direct logins.
+func StartSession(db *gorm.DB, ctx context.Context, user_id uint, app_id *uint, client_id string, ip string, user_agent string, mfa bool) (models.Session, error) {
+	gen, _ := uuid.NewV7()
+	if len(user_agent) > 255 {
+		user_agent = user_agent[:255]
+	}
+
+	now := time.Now().UTC()
+	session := models.Session{
+		SessionID:  gen.String(),
+		UserID:     user_id,
+		AppID:      app_id,
+		ClientID:   client_id,
+		IP:         ip,
+		UserAgent:  user_agent,
+		MFA:        mfa,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := db.WithContext(ctx).Create(&session).Error; err != nil {
+		return models.Session{}, err
+	}
+	sessionsSeen.due(session.SessionID, now, 0)
+	return session, nil
+}
+
+// Updates the last seen time of the session unless it was written within the interval
+func TouchSession(db *gorm.DB, ctx context.Context, session_id string) error {
+	if session_id == "" {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if !sessionsSeen.due(session_id, now, sessionLastSeenInterval()) {
+		return nil
+	}
+	return db.WithContext(ctx).Model(&models.Session{}).Where("session_id = ? AND revoked_at IS NULL", session_id).Update("last_seen_at", now).Error
+}
+
+// Moves the end of the session to the expiry of its latest refresh token
+func ExtendSession(db *gorm.DB, ctx context.Context, session_id string, expires_at time.Time) error {
+	return db.WithContext(ctx).Model(&models.Session{}).Where("session_id = ?", session_id).Update("expires_at", expires_at).Error
+}
+
+// Signed in sessions of the user, a session ends when its last refresh token expires.
+// Sessions recorded before expires_at was kept end a refresh lifetime after they were last seen.
+func UserSessions(db *gorm.DB, ctx context.Context, user_id uint) ([]models.Session, error) {
+	var sessions []models.Session
+	now := time.Now().UTC()
+	res := db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", user_id).
+		Where("expires_at > ? OR (expires_at IS NULL AND last_seen_at > ?)", now, now.Add(-RefreshTokenLifeTime())).
+		Order("last_seen_at desc").Find(&sessions)
+	return sessions, res.Error
+}
+
+// Signs out a single session of the user, its refresh tokens are revoked and
+// the access tokens issued in it are rejected from now on
+func RevokeSession(db *gorm.DB, ctx context.Context, user_id uint, session_id string) error {
+	now := time.Now().UTC()
+	res := db.WithContext(ctx).Model(&models.Session{}).Where("session_id = ? AND user_id = ? AND revoked_at IS NULL", session_id, user_id).Update("revoked_at", now)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return ErrSessionNotFound
+	}
+	Revocations.addSession(session_id, now)
+	sessionsSeen.forget(session_id)
+	return RevokeRefreshFamily(db, ctx, session_id)
+}
+
+// Removes sessions whose refresh tokens have all expired, run by the scheduler
+func CleanExpiredSessions() {
+	db, _ := database.ReturnSession()
+	now := time.Now().UTC()
+	expired_before := now.Add(-RefreshTokenLifeTime())
+	db.Where("expires_at < ? OR (expires_at IS NULL AND last_seen_at < ?)", now, expired_before).Delete(&models.Session{})
+	sessionsSeen.expire(expired_before)
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	db := memoryDB(t, &models.Session{}, &models.RefreshToken{}, &models.UserRevocation{})
+	ctx := context.Background()
+
+	// keeping the cache from reloading out of the memory database
+	Revocations.mu.Lock()
+	Revocations.loaded_at = time.Now()
+	Revocations.mu.Unlock()
+
+	app_id := uint(3)
+	first, err := StartSession(db, ctx, 21, &app_id, "", "10.0.0.1", "test-agent", false)
+	require.NoError(t, err, "Starting a session should not return an error")
+	second, err := StartSession(db, ctx, 21, nil, "", "10.0.0.2", "other-agent", true)
+	require.NoError(t, err)
+	_, err = IssueRefreshToken(db, ctx, 21, first.SessionID, false)
+	require.NoError(t, err)
+	bound, err := StartSession(db, ctx, 21, nil, "spa-client", "10.0.0.3", "browser", false)
+	require.NoError(t, err)
+	session_policy, err := LoadSessionPolicy(db, ctx, bound.SessionID)
+	require.NoError(t, err)
+	assert.Equal(t, "spa-client", session_policy.Session.ClientID, "Code flow sessions should be bound to their client")
+	require.NoError(t, RevokeSession(db, ctx, 21, bound.SessionID))
+
+	sessions, err := UserSessions(db, ctx, 21)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2, "Both sessions should be listed")
+
+	// last seen is not written again inside the interval
+	db.Model(&models.Session{}).Where("session_id = ?", first.SessionID).Update("last_seen_at", time.Now().UTC().Add(-time.Hour))
+	require.NoError(t, TouchSession(db, ctx, first.SessionID))
+	var touched models.Session
+	db.Where("session_id = ?", first.SessionID).First(&touched)
+	assert.True(t, touched.LastSeenAt.Before(time.Now().UTC().Add(-30*time.Minute)), "Last seen should be throttled")
+
+	require.NoError(t, RevokeSession(db, ctx, 21, first.SessionID), "Revoking should not return an error")
+	assert.True(t, Revocations.IsSessionRevoked(first.SessionID), "Session should be revoked")
+	assert.False(t, Revocations.IsSessionRevoked(second.SessionID), "Other sessions should stay valid")
+	assert.ErrorIs(t, RevokeSession(db, ctx, 21, first.SessionID), ErrSessionNotFound, "Session can only be revoked once")
+	assert.ErrorIs(t, RevokeSession(db, ctx, 22, second.SessionID), ErrSessionNotFound, "Sessions of other users can not be revoked")
+
+	var revoked_tokens int64
+	db.Model(&models.RefreshToken{}).Where("family_id = ? AND revoked = ?", first.SessionID, true).Count(&revoked_tokens)
+	assert.Equal(t, int64(1), revoked_tokens, "Refresh tokens of the session should be revoked")
+
+	require.NoError(t, RevokeUserTokens(db, ctx, 21))
+	sessions, err = UserSessions(db, ctx, 21)
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "Revoking all tokens should sign out every session")
+}
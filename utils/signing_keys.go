@@ -0,0 +1,332 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// Parsed signing key ready to sign or verify tokens
+type SigningKey struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+	Active  bool
+}
+
+// JSON Web Key as published on /.well-known/jwks.json
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// In memory copy of the published signing keys, reloaded every SIGNING_KEY_CACHE_TTL seconds
+// or early when a token carries an unknown kid (rotation done by another instance). Early reloads
+// happen at most once every SIGNING_KEY_RELOAD_INTERVAL seconds and kids still unknown after one
+// are rejected until the next reload, so tokens with made up kids can not flood the database.
+type signingKeyStore struct {
+	mu          sync.RWMutex
+	keys        map[string]SigningKey
+	missing     map[string]bool
+	active      string
+	loaded_at   time.Time
+	reloaded_at time.Time
+}
+
+var SigningKeys = &signingKeyStore{keys: make(map[string]SigningKey), missing: make(map[string]bool)}
+
+func signingKeyCacheTTL() time.Duration {
+	ttl, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("SIGNING_KEY_CACHE_TTL", "60"))
+	return time.Duration(ttl) * time.Second
+}
+
+func signingKeyReloadInterval() time.Duration {
+	interval, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("SIGNING_KEY_RELOAD_INTERVAL", "10"))
+	return time.Duration(interval) * time.Second
+}
+
+// Retired keys stay published for JWT_KEY_GRACE_PERIOD minutes so tokens they signed keep verifying, and at
+// least as long as the longest access token lifetime (global, client, impersonation or an app's token policy)
+// so a rotation never cuts a token short
+func signingKeyGracePeriod(db *gorm.DB) time.Duration {
+	grace, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("JWT_KEY_GRACE_PERIOD", "120"))
+	longest := max(DefaultTokenPolicy().AccessTokenLifeTime, minutesSetting("CLIENT_TOKEN_LIFE_TIME", "60"), time.Duration(impersonationLifeTime())*time.Minute)
+
+	var app_life_time sql.NullInt64
+	if res := db.Model(&models.App{}).Select("MAX(access_token_life_time)").Scan(&app_life_time); res.Error == nil && app_life_time.Valid {
+		longest = max(longest, time.Duration(app_life_time.Int64)*time.Minute)
+	}
+	return max(time.Duration(grace)*time.Minute, longest)
+}
+
+func (store *signingKeyStore) load(force bool) error {
+	store.mu.RLock()
+	fresh := time.Since(store.loaded_at) < signingKeyCacheTTL()
+	store.mu.RUnlock()
+	if fresh && !force {
+		return nil
+	}
+
+	db, err := database.ReturnSession()
+	if err != nil {
+		return err
+	}
+
+	var signing_keys []models.SigningKey
+	if res := db.Model(&models.SigningKey{}).Where("active = ? OR publish_until > ?", true, time.Now().UTC()).Order("id asc").Find(&signing_keys); res.Error != nil {
+		return res.Error
+	}
+
+	keys := make(map[string]SigningKey, len(signing_keys))
+	active := ""
+	for _, value := range signing_keys {
+		key, err := parseSigningKey(value)
+		if err != nil {
+			fmt.Printf("skipping signing key %v: %v\n", value.Kid, err)
+			continue
+		}
+		keys[key.Kid] = key
+		if key.Active {
+			active = key.Kid
+		}
+	}
+
+	store.mu.Lock()
+	store.keys = keys
+	store.missing = make(map[string]bool)
+	store.active = active
+	store.loaded_at = time.Now()
+	store.mu.Unlock()
+	return nil
+}
+
+// Claims the early reload for an unknown kid, false when the kid was already missing
+// in the last reload or another early reload happened within the interval
+func (store *signingKeyStore) claimReload(kid string) bool {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.missing[kid] || time.Since(store.reloaded_at) < signingKeyReloadInterval() {
+		return false
+	}
+	store.reloaded_at = time.Now()
+	return true
+}
+
+// Returns the key new tokens are signed with, generating the first one if needed
+func (store *signingKeyStore) Active() (SigningKey, error) {
+	if err := store.load(false); err != nil {
+		return SigningKey{}, err
+	}
+
+	store.mu.RLock()
+	key, ok := store.keys[store.active]
+	store.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := SigningKeyRotate(); err != nil {
+		return SigningKey{}, err
+	}
+	if err := store.load(true); err != nil {
+		return SigningKey{}, err
+	}
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	if key, ok := store.keys[store.active]; ok {
+		return key, nil
+	}
+	return SigningKey{}, ErrUnknownSigningKey
+}
+
+// Returns the published key with the given kid
+func (store *signingKeyStore) Get(kid string) (SigningKey, error) {
+	if err := store.load(false); err != nil {
+		return SigningKey{}, err
+	}
+
+	store.mu.RLock()
+	key, ok := store.keys[kid]
+	store.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if !store.claimReload(kid) {
+		return SigningKey{}, ErrUnknownSigningKey
+	}
+	if err := store.load(true); err != nil {
+		return SigningKey{}, err
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if key, ok := store.keys[kid]; ok {
+		return key, nil
+	}
+	store.missing[kid] = true
+	return SigningKey{}, ErrUnknownSigningKey
+}
+
+// Public part of all published keys
+func (store *signingKeyStore) JWKS() (JWKS, error) {
+	if err := store.load(false); err != nil {
+		return JWKS{}, err
+	}
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	jwks := JWKS{Keys: make([]JWK, 0, len(store.keys))}
+	for _, key := range store.keys {
+		jwks.Keys = append(jwks.Keys, key.JWK())
+	}
+	return jwks, nil
+}
+
+func (key SigningKey) JWK() JWK {
+	jwk := JWK{Kid: key.Kid, Use: "sig", Alg: key.Method.Alg()}
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// Generates a new active signing key with JWT_SIGNING_ALGORITHM (RS256 or EdDSA),
+// retires the current one and removes keys past their publishing window
+func SigningKeyRotate() error {
+	dbcon, _ := database.ReturnSession()
+
+	signing_key, err := generateSigningKey(configs.AppConfig.GetOrDefault("JWT_SIGNING_ALGORITHM", "RS256"))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	grace_period := signingKeyGracePeriod(dbcon)
+	tx := dbcon.Begin()
+	if err := tx.Model(&models.SigningKey{}).Where("active = ?", true).Updates(map[string]interface{}{
+		"active":        false,
+		"publish_until": sql.NullTime{Time: now.Add(grace_period), Valid: true},
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Create(&signing_key).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("active = ? AND publish_until < ?", false, now).Delete(&models.SigningKey{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return SigningKeys.load(true)
+}
+
+func generateSigningKey(algorithm string) (models.SigningKey, error) {
+	var private crypto.Signer
+	switch algorithm {
+	case "EdDSA":
+		_, ed_private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return models.SigningKey{}, err
+		}
+		private = ed_private
+	case "RS256":
+		rsa_private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return models.SigningKey{}, err
+		}
+		private = rsa_private
+	default:
+		return models.SigningKey{}, fmt.Errorf("unsupported signing algorithm %v", algorithm)
+	}
+
+	private_der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+	public_der, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	gen, _ := uuid.NewV7()
+	return models.SigningKey{
+		Kid:        gen.String(),
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private_der})),
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public_der})),
+		Active:     true,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func parseSigningKey(signing_key models.SigningKey) (SigningKey, error) {
+	method := jwt.GetSigningMethod(signing_key.Algorithm)
+	if method == nil {
+		return SigningKey{}, fmt.Errorf("unsupported signing algorithm %v", signing_key.Algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(signing_key.PrivateKey))
+	if block == nil {
+		return SigningKey{}, errors.New("invalid private key pem")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return SigningKey{}, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return SigningKey{}, errors.New("private key can not sign")
+	}
+
+	return SigningKey{
+		Kid:     signing_key.Kid,
+		Method:  method,
+		Private: private,
+		Public:  private.Public(),
+		Active:  signing_key.Active,
+	}, nil
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"blue-admin.com/database"
+	"blue-admin.com/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningKeyRotate(t *testing.T) {
+	for _, algorithm := range []string{"EdDSA", "RS256"} {
+		t.Run(algorithm, func(t *testing.T) {
+			os.Setenv("JWT_SIGNING_ALGORITHM", algorithm)
+			defer os.Unsetenv("JWT_SIGNING_ALGORITHM")
+
+			require.NoError(t, SigningKeyRotate(), "Rotating should not return an error")
+			active, err := SigningKeys.Active()
+			require.NoError(t, err, "Active key should be available")
+			assert.Equal(t, algorithm, active.Method.Alg(), "Active key should use the configured algorithm")
+
+			token, err := CreateJWTToken("test@mail.com", "uuid", 21, []string{"admin"}, 5)
+			require.NoError(t, err, "Token creation should not return an error")
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &UserClaim{})
+			require.NoError(t, err, "Token should be well formed")
+			assert.Equal(t, active.Kid, parsed.Header["kid"], "Token should name its signing key")
+
+			claims, err := ParseJWTToken(token)
+			require.NoError(t, err, "Token should verify with the published key")
+			assert.Equal(t, 21, claims.UserID)
+
+			jwks, err := SigningKeys.JWKS()
+			require.NoError(t, err, "JWKS should not return an error")
+			found := false
+			for _, key := range jwks.Keys {
+				if key.Kid == active.Kid {
+					found = true
+					assert.Equal(t, algorithm, key.Alg)
+					assert.Equal(t, "sig", key.Use)
+				}
+			}
+			assert.True(t, found, "Active key should be published")
+		})
+	}
+
+	// tokens signed before a rotation keep verifying during the grace period
+	token, err := CreateJWTToken("test@mail.com", "uuid", 21, []string{"admin"}, 5)
+	require.NoError(t, err, "Token creation should not return an error")
+	require.NoError(t, SigningKeyRotate(), "Rotating should not return an error")
+	_, err = ParseJWTToken(token)
+	assert.NoError(t, err, "Retired key should still verify")
+
+	_, err = SigningKeys.Get("unknown")
+	assert.ErrorIs(t, err, ErrUnknownSigningKey)
+}
+
+func TestSigningKeyUnknownKidReload(t *testing.T) {
+	require.NoError(t, SigningKeyRotate(), "Rotating should not return an error")
+	SigningKeys.mu.Lock()
+	SigningKeys.reloaded_at = time.Time{}
+	SigningKeys.mu.Unlock()
+
+	_, err := SigningKeys.Get("made-up")
+	assert.ErrorIs(t, err, ErrUnknownSigningKey)
+	SigningKeys.mu.RLock()
+	reloaded_at := SigningKeys.reloaded_at
+	assert.True(t, SigningKeys.missing["made-up"], "Kids missing after a reload should be remembered")
+	SigningKeys.mu.RUnlock()
+
+	// a key published by another instance
+	signing_key, err := generateSigningKey("EdDSA")
+	require.NoError(t, err)
+	signing_key.Active = false
+	signing_key.PublishUntil = sql.NullTime{Time: time.Now().UTC().Add(time.Hour), Valid: true}
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&signing_key).Error)
+
+	_, err = SigningKeys.Get(signing_key.Kid)
+	assert.ErrorIs(t, err, ErrUnknownSigningKey, "Unknown kids should not reload again within the interval")
+
+	SigningKeys.mu.Lock()
+	SigningKeys.reloaded_at = reloaded_at.Add(-signingKeyReloadInterval())
+	SigningKeys.mu.Unlock()
+	_, err = SigningKeys.Get("made-up")
+	assert.ErrorIs(t, err, ErrUnknownSigningKey)
+	SigningKeys.mu.RLock()
+	assert.True(t, SigningKeys.reloaded_at.Before(reloaded_at), "Kids missing in the last reload should not reload again")
+	SigningKeys.mu.RUnlock()
+
+	key, err := SigningKeys.Get(signing_key.Kid)
+	require.NoError(t, err, "Unknown kids should reload once the interval passed")
+	assert.Equal(t, signing_key.Kid, key.Kid)
+}
+
+func TestSigningKeyRotateLongLivedToken(t *testing.T) {
+	db, err := database.ReturnSession()
+	require.NoError(t, err)
+	t.Setenv("JWT_KEY_GRACE_PERIOD", "120")
+
+	// an app issuing access tokens for a day, longer than the configured grace period
+	app := models.App{Name: "long_lived", Description: "long lived tokens", Active: true, TokenPolicy: models.AppTokenPolicy{AccessTokenLifeTime: 1440}}
+	require.NoError(t, db.Create(&app).Error)
+	token, err := CreateClaimJWTToken(UserClaim{RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{app.UUID}}, UserID: 21}, 1440)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &UserClaim{})
+	require.NoError(t, err)
+	claims := parsed.Claims.(*UserClaim)
+
+	require.NoError(t, SigningKeyRotate(), "Rotating should not return an error")
+	var retired models.SigningKey
+	require.NoError(t, db.Where("kid = ?", parsed.Header["kid"]).First(&retired).Error, "The retired key should stay published")
+	assert.False(t, retired.PublishUntil.Time.Before(claims.ExpiresAt.Time), "The retired key should stay published until the token expires")
+
+	// later rotations within the token's lifetime keep its key
+	require.NoError(t, SigningKeyRotate())
+	_, err = ParseJWTToken(token)
+	assert.NoError(t, err, "Tokens should verify until they expire")
+}
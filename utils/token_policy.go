@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"blue-admin.com/configs"
+	"blue-admin.com/models"
+	"gorm.io/gorm"
+)
+
+var ErrSessionExpired = errors.New("session expired, sign in again")
+
+// Lifetimes of the tokens issued in a session, a zero idle timeout or session lifetime is no limit
+type TokenPolicy struct {
+	AccessTokenLifeTime  time.Duration
+	RefreshTokenLifeTime time.Duration
+	SessionIdleTimeout   time.Duration
+	SessionMaxLifeTime   time.Duration
+}
+
+func minutesSetting(key string, fallback string) time.Duration {
+	minutes, _ := strconv.Atoi(configs.AppConfig.GetOrDefault(key, fallback))
+	if minutes < 0 {
+		minutes = 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Global token policy in minutes from ACCESS_TOKEN_LIFE_TIME (default 60), REFRESH_TOKEN_LIFE_TIME,
+// SESSION_IDLE_TIMEOUT and SESSION_MAX_LIFE_TIME (both default 0, no limit)
+func DefaultTokenPolicy() TokenPolicy {
+	access_life_time := minutesSetting("ACCESS_TOKEN_LIFE_TIME", "60")
+	if access_life_time == 0 {
+		access_life_time = 60 * time.Minute
+	}
+	return TokenPolicy{
+		AccessTokenLifeTime:  access_life_time,
+		RefreshTokenLifeTime: RefreshTokenLifeTime(),
+		SessionIdleTimeout:   minutesSetting("SESSION_IDLE_TIMEOUT", "0"),
+		SessionMaxLifeTime:   minutesSetting("SESSION_MAX_LIFE_TIME", "0"),
+	}
+}
+
+// Token policy of logins to the app, the settings the app leaves at zero keep the global default
+func AppTokenPolicy(app *models.App) TokenPolicy {
+	policy := DefaultTokenPolicy()
+	if app == nil {
+		return policy
+	}
+	if app.TokenPolicy.AccessTokenLifeTime > 0 {
+		policy.AccessTokenLifeTime = time.Duration(app.TokenPolicy.AccessTokenLifeTime) * time.Minute
+	}
+	if app.TokenPolicy.RefreshTokenLifeTime > 0 {
+		policy.RefreshTokenLifeTime = time.Duration(app.TokenPolicy.RefreshTokenLifeTime) * time.Minute
+	}
+	if app.TokenPolicy.SessionIdleTimeout > 0 {
+		policy.SessionIdleTimeout = time.Duration(app.TokenPolicy.SessionIdleTimeout) * time.Minute
+	}
+	if app.TokenPolicy.SessionMaxLifeTime > 0 {
+		policy.SessionMaxLifeTime = time.Duration(app.TokenPolicy.SessionMaxLifeTime) * time.Minute
+	}
+	return policy
+}
+
+// A session with the app it was started for, nil for sessions not scoped to an app, and the policy that applies to it
+type SessionPolicy struct {
+	Session models.Session
+	App     *models.App
+	Policy  TokenPolicy
+}
+
+// Loads the session and the token policy of its app. Deactivating the app ends its sessions at the next refresh.
+func LoadSessionPolicy(db *gorm.DB, ctx context.Context, session_id string) (SessionPolicy, error) {
+	var session models.Session
+	if res := db.WithContext(ctx).Model(&models.Session{}).Where("session_id = ? AND revoked_at IS NULL", session_id).First(&session); res.Error != nil {
+		return SessionPolicy{}, ErrSessionNotFound
+	}
+	if session.AppID == nil {
+		return SessionPolicy{Session: session, Policy: DefaultTokenPolicy()}, nil
+	}
+
+	var app models.App
+	if res := db.WithContext(ctx).Model(&models.App{}).Where("id = ? AND active = ?", *session.AppID, true).First(&app); res.Error != nil {
+		return SessionPolicy{}, ErrAppInactive
+	}
+	return SessionPolicy{Session: session, App: &app, Policy: AppTokenPolicy(&app)}, nil
+}
+
+// Whether the session was idle longer than the idle timeout or outlived the absolute lifetime
+func (session_policy SessionPolicy) Expired(now time.Time) bool {
+	policy := session_policy.Policy
+	if policy.SessionIdleTimeout > 0 && now.Sub(session_policy.Session.LastSeenAt) > policy.SessionIdleTimeout {
+		return true
+	}
+	return policy.SessionMaxLifeTime > 0 && now.Sub(session_policy.Session.CreatedAt) > policy.SessionMaxLifeTime
+}
+
+// Expiry of tokens issued now with the lifetime, never past the end of the session
+func (session_policy SessionPolicy) expiry(now time.Time, life_time time.Duration) time.Time {
+	expires_at := now.Add(life_time)
+	if max_life_time := session_policy.Policy.SessionMaxLifeTime; max_life_time > 0 {
+		if session_end := session_policy.Session.CreatedAt.Add(max_life_time); session_end.Before(expires_at) {
+			return session_end
+		}
+	}
+	return expires_at
+}
+
+func (session_policy SessionPolicy) AccessExpiry(now time.Time) time.Time {
+	return session_policy.expiry(now, session_policy.Policy.AccessTokenLifeTime)
+}
+
+func (session_policy SessionPolicy) RefreshExpiry(now time.Time) time.Time {
+	return session_policy.expiry(now, session_policy.Policy.RefreshTokenLifeTime)
+}
+
+// Checks the session of a token against its policy, tokens outside a session pass
+func CheckSessionPolicy(db *gorm.DB, ctx context.Context, session_id string) error {
+	if session_id == "" {
+		return nil
+	}
+	session_policy, err := LoadSessionPolicy(db, ctx, session_id)
+	if err != nil {
+		return err
+	}
+	if session_policy.Expired(time.Now().UTC()) {
+		return ErrSessionExpired
+	}
+	return nil
+}
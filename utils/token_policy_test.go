@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blue-admin.com/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenPolicy(t *testing.T) {
+	db := memoryDB(t, &models.App{}, &models.Session{}, &models.RefreshToken{})
+	ctx := context.Background()
+
+	defaults := DefaultTokenPolicy()
+	assert.Equal(t, 60*time.Minute, defaults.AccessTokenLifeTime)
+	assert.Equal(t, RefreshTokenLifeTime(), defaults.RefreshTokenLifeTime)
+	assert.Zero(t, defaults.SessionIdleTimeout, "Sessions should not time out by default")
+
+	app := models.App{Name: "payments", Description: "payments service", Active: true}
+	app.TokenPolicy = models.AppTokenPolicy{AccessTokenLifeTime: 5, SessionIdleTimeout: 30, SessionMaxLifeTime: 120}
+	require.NoError(t, db.Create(&app).Error)
+
+	policy := AppTokenPolicy(&app)
+	assert.Equal(t, 5*time.Minute, policy.AccessTokenLifeTime)
+	assert.Equal(t, defaults.RefreshTokenLifeTime, policy.RefreshTokenLifeTime, "Unset settings should keep the default")
+	assert.Equal(t, 30*time.Minute, policy.SessionIdleTimeout)
+
+	session, err := StartSession(db, ctx, 1, &app.ID, "", "", "", false)
+	require.NoError(t, err)
+	session_policy, err := LoadSessionPolicy(db, ctx, session.SessionID)
+	require.NoError(t, err)
+	require.NotNil(t, session_policy.App)
+	assert.Equal(t, 5*time.Minute, session_policy.Policy.AccessTokenLifeTime)
+
+	now := time.Now().UTC()
+	assert.False(t, session_policy.Expired(now))
+	assert.True(t, session_policy.Expired(now.Add(31*time.Minute)), "Idle sessions should expire")
+	assert.WithinDuration(t, session.CreatedAt.Add(120*time.Minute), session_policy.RefreshExpiry(now), time.Second, "Refresh tokens should not outlive the session")
+	assert.WithinDuration(t, now.Add(5*time.Minute), session_policy.AccessExpiry(now), time.Second)
+
+	require.NoError(t, CheckSessionPolicy(db, ctx, session.SessionID))
+	assert.NoError(t, CheckSessionPolicy(db, ctx, ""), "Tokens outside a session should pass")
+	db.Model(&models.Session{}).Where("session_id = ?", session.SessionID).Update("last_seen_at", now.Add(-time.Hour))
+	assert.ErrorIs(t, CheckSessionPolicy(db, ctx, session.SessionID), ErrSessionExpired)
+	db.Model(&models.Session{}).Where("session_id = ?", session.SessionID).Updates(map[string]interface{}{"last_seen_at": now, "created_at": now.Add(-3 * time.Hour)})
+	assert.ErrorIs(t, CheckSessionPolicy(db, ctx, session.SessionID), ErrSessionExpired, "Sessions should end after their absolute lifetime")
+
+	require.NoError(t, ExtendSession(db, ctx, session.SessionID, now.Add(time.Hour)))
+	sessions, err := UserSessions(db, ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	require.NoError(t, ExtendSession(db, ctx, session.SessionID, now.Add(-time.Minute)))
+	sessions, _ = UserSessions(db, ctx, 1)
+	assert.Empty(t, sessions, "Sessions should end when their last refresh token expires")
+
+	db.Model(&models.App{}).Where("id = ?", app.ID).UpdateColumn("active", false)
+	assert.ErrorIs(t, CheckSessionPolicy(db, ctx, session.SessionID), ErrAppInactive)
+	assert.ErrorIs(t, CheckSessionPolicy(db, ctx, "unknown"), ErrSessionNotFound)
+}
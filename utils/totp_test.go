@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 appendix B vectors for SHA1, truncated to six digits
+func TestTOTPCode(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range vectors {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(unix, 0)))
+		require.NoError(t, err, "Code generation should not return an error")
+		assert.Equal(t, expected, code, "Code at %v", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err, "Secret generation should not return an error")
+
+	now := time.Now().UTC()
+	code, _ := TOTPCode(secret, TOTPStep(now))
+	step, ok := ValidateTOTP(secret, code, now, 0)
+	assert.True(t, ok, "Current code should be accepted")
+	assert.Equal(t, TOTPStep(now), step)
+
+	_, ok = ValidateTOTP(secret, code, now, step)
+	assert.False(t, ok, "Used step should be rejected")
+
+	previous, _ := TOTPCode(secret, TOTPStep(now)-1)
+	_, ok = ValidateTOTP(secret, previous, now, 0)
+	assert.True(t, ok, "Previous period should be accepted for drift")
+
+	old, _ := TOTPCode(secret, TOTPStep(now)-5)
+	_, ok = ValidateTOTP(secret, old, now, 0)
+	assert.False(t, ok, "Old code should be rejected")
+
+	uri := TOTPURI("Blue Admin", "test@mail.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Blue%20Admin:test@mail.com?"), uri)
+	assert.Contains(t, uri, "secret="+secret)
+}
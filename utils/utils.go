@@ -1,13 +1,14 @@
 package utils
 
 import (
-	"crypto/sha512"
-	"encoding/hex"
 	"fmt"
+	"slices"
 	"time"
 
 	"blue-admin.com/configs"
+	"blue-admin.com/passwords"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type UserClaim struct {
@@ -16,61 +17,99 @@ type UserClaim struct {
 	Roles  []string `json:"roles"`
 	UUID   string   `json:"uuid"`
 	UserID int      `json:"user_id"`
+	// set on tokens issued to app clients with the client_credentials grant
+	ClientID string `json:"client_id,omitempty"`
+	// set when the login completed a second factor
+	MFA bool `json:"mfa,omitempty"`
+	// session (refresh token family) the token was issued in
+	SessionID string `json:"sid,omitempty"`
+	// real user behind an impersonation token, the other claims are the impersonated user's
+	Actor *ActorClaim `json:"act,omitempty"`
 }
 
-// Combine password and salt then hash them using the SHA-512
-func HashFunc(password string) string {
-
-	// var salt []byte
-	// get salt from env variable
-	hsalt := configs.AppConfig.Get("SECRETE_SALT")
-	if configs.AppConfig.Get("SECRETE_SALT") == "" {
-		hsalt = "test_salt"
-	}
-	salt := []byte(hsalt)
-
-	// Convert password string to byte slice
-	var pwdByte = []byte(password)
-
-	// Create sha-512 hasher
-	var sha512 = sha512.New()
-
-	pwdByte = append(pwdByte, salt...)
-
-	sha512.Write(pwdByte)
-
-	// Get the SHA-512 hashed password
-	var hashedPassword = sha512.Sum(nil)
+// Acting party of an impersonation token, named after the act claim of RFC 8693
+type ActorClaim struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email"`
+	UserID  int    `json:"user_id"`
+}
 
-	// Convert the hashed to hex string
-	var hashedPasswordHex = hex.EncodeToString(hashedPassword)
-	return hashedPasswordHex
+// Hash password with the configured password hasher (argon2id or bcrypt)
+// the result carries the algorithm, its parameters and a per password salt
+func HashFunc(password string) (string, error) {
+	return passwords.Hash(password)
 }
 
+// Checks the password against both the encoded and the legacy SHA-512 hashes
 func PasswordsMatch(hashedPassword, currPassword string) bool {
+	match, err := passwords.Verify(hashedPassword, currPassword)
+	if err != nil {
+		return false
+	}
+	return match
+}
 
-	var currPasswordHash = HashFunc(currPassword)
-	return hashedPassword == currPasswordHash
+// Reports whether the stored hash is legacy or uses outdated parameters
+func PasswordNeedsRehash(hashedPassword string) bool {
+	return passwords.NeedsRehash(hashedPassword)
+}
+
+// Unique token id (jti) used to revoke single tokens
+func newTokenID() string {
+	gen, _ := uuid.NewV7()
+	return gen.String()
 }
 
 // source of this token encode decode functions
 // https://github.com/gurleensethi/go-jwt-tutorial/blob/main/main.go
 func CreateJWTToken(email string, uuid string, user_id int, roles []string, duration int) (string, error) {
 	my_claim := UserClaim{
-		RegisteredClaims: jwt.RegisteredClaims{},
+		RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{BlueAdminAudience()}},
 		Email:            email,
 		Roles:            roles,
 		UUID:             uuid,
 		UserID:           user_id,
 	}
+	my_claim.Subject = "UI Authentication Token"
+	return CreateClaimJWTToken(my_claim, duration)
+}
 
-	salt_a, _ := GetJWTSalt()
-	exp := time.Now().UTC().Add(time.Duration(duration) * time.Minute)
-	my_claim.ExpiresAt = jwt.NewNumericDate(exp)
+// Token for an app client, the uuid claim carries the app uuid and there is no user
+func CreateClientJWTToken(client_id string, app_uuid string, roles []string, duration int) (string, error) {
+	my_claim := UserClaim{
+		RegisteredClaims: jwt.RegisteredClaims{Audience: jwt.ClaimStrings{app_uuid}},
+		Roles:            roles,
+		UUID:             app_uuid,
+		ClientID:         client_id,
+	}
+	my_claim.Subject = "Client Credentials Token"
+	return CreateClaimJWTToken(my_claim, duration)
+}
+
+// Sets the token id and the expiry from the lifetime unless given, and the issuer then signs the claims
+func CreateClaimJWTToken(my_claim UserClaim, duration int) (string, error) {
+	now := time.Now().UTC()
+	if my_claim.ID == "" {
+		my_claim.ID = newTokenID()
+	}
+	if my_claim.ExpiresAt == nil {
+		my_claim.ExpiresAt = jwt.NewNumericDate(now.Add(time.Duration(duration) * time.Minute))
+	}
+	my_claim.IssuedAt = jwt.NewNumericDate(now)
 	my_claim.Issuer = "Blue Admin"
-	my_claim.Subject = "UI Authentication Token"
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, my_claim)
-	signedString, err := token.SignedString([]byte(salt_a))
+	return signClaims(my_claim)
+}
+
+// Signs any claims with the active signing key, naming it in the kid header
+func signClaims(claims jwt.Claims) (string, error) {
+	signing_key, err := SigningKeys.Active()
+	if err != nil {
+		return "", fmt.Errorf("error getting signing key: %v", err)
+	}
+
+	token := jwt.NewWithClaims(signing_key.Method, claims)
+	token.Header["kid"] = signing_key.Kid
+	signedString, err := token.SignedString(signing_key.Private)
 	if err != nil {
 		return "", fmt.Errorf("error creating signed string: %v", err)
 	}
@@ -78,17 +117,81 @@ func CreateJWTToken(email string, uuid string, user_id int, roles []string, dura
 	return signedString, nil
 }
 
+// tokens are verified with the published key named by their kid header
+func signingKeyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	signing_key, err := SigningKeys.Get(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != signing_key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Method.Alg())
+	}
+	return signing_key.Public, nil
+}
+
+// iat and exp carry microseconds so a token issued right after a user's tokens were revoked is told
+// apart from the tokens the revocation covers, see RevokeUserTokens
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
+
+var signingMethods = jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()})
+
 func ParseJWTToken(jwtToken string) (UserClaim, error) {
+	response := UserClaim{}
+
+	token, err := jwt.ParseWithClaims(jwtToken, &response, signingKeyFunc, signingMethods)
+
+	if err != nil || !token.Valid {
+		// HS512 tokens signed with the shared salts are only accepted while migrating
+		if configs.AppConfig.Get("JWT_ACCEPT_HS512") != "true" {
+			if err == nil {
+				err = fmt.Errorf("invalid token")
+			}
+			return UserClaim{}, err
+		}
+		response, err = parseSaltJWTToken(jwtToken)
+		if err != nil {
+			return UserClaim{}, err
+		}
+	}
+
+	// mfa challenge and onboarding tokens are signed with the same keys but only accepted by their own endpoints
+	switch response.Subject {
+	case MFAChallengeSubject, InvitationSubject, EmailVerificationSubject:
+		return UserClaim{}, ErrNotAccessToken
+	}
+
+	// check the token was not revoked by logout or a revoke all sessions
+	issued_at := time.Time{}
+	if response.IssuedAt != nil {
+		issued_at = response.IssuedAt.Time
+	}
+	if Revocations.IsRevoked(response.ID, uint(response.UserID), issued_at) || Revocations.IsSessionRevoked(response.SessionID) {
+		return UserClaim{}, ErrTokenRevoked
+	}
+	// impersonation tokens also end when the actor is signed out everywhere
+	if response.Actor != nil && Revocations.IsRevoked("", uint(response.Actor.UserID), issued_at) {
+		return UserClaim{}, ErrTokenRevoked
+	}
+	return response, nil
+
+}
+
+// Verifies the former HS512 tokens against the two rotating salts
+func parseSaltJWTToken(jwtToken string) (UserClaim, error) {
 	salt_a, salt_b := GetJWTSalt()
 	response_a := UserClaim{}
 	response_b := UserClaim{}
 
+	hmac_only := jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()})
 	token_a, aerr := jwt.ParseWithClaims(jwtToken, &response_a, func(token *jwt.Token) (interface{}, error) {
 		return []byte(salt_a), nil
-	})
+	}, hmac_only)
 	token_b, berr := jwt.ParseWithClaims(jwtToken, &response_b, func(token *jwt.Token) (interface{}, error) {
 		return []byte(salt_b), nil
-	})
+	}, hmac_only)
 
 	if aerr != nil && berr != nil {
 		return UserClaim{}, aerr
@@ -102,7 +205,6 @@ func ParseJWTToken(jwtToken string) (UserClaim, error) {
 		return response_b, nil
 	}
 	return response_a, nil
-
 }
 
 // Return Unique values in list
@@ -118,6 +220,16 @@ func UniqueSlice(slice []string) []string {
 	return list
 }
 
+// Whether any of the roles is allowed, superuser is allowed everywhere
+func CheckAnyValueExistsInSlice(slice []string, allowed []string) bool {
+	for _, role := range slice {
+		if role == "superuser" || slices.Contains(allowed, role) {
+			return true
+		}
+	}
+	return false
+}
+
 // Return Unique values in list
 func CheckValueExistsInSlice(slice []string, role_test string) bool {
 	for _, role := range slice {
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,21 +30,33 @@ func (m *mockConfig) Get(key string) string {
 
 func TestHashFunc(t *testing.T) {
 	password := "password123"
-	expectedHash := "d81a4bcb5018ed48fd54d83f7f3d164a1fd2e2c4039fc99b297e7fbe23af373d7309cebdc3a928c9dd01ec51f70d50d1a4ec98ef398fd13034b58760c60e79f6"
 
-	hashedPassword := HashFunc(password)
-	assert.Equal(t, expectedHash, hashedPassword, "Hashed password should match the expected hash")
+	hashedPassword, err := HashFunc(password)
+	require.NoError(t, err, "Hashing should not return an error")
+	assert.True(t, strings.HasPrefix(hashedPassword, "$argon2id$"), "Hashed password should be argon2id encoded")
+
+	otherHash, err := HashFunc(password)
+	require.NoError(t, err, "Hashing should not return an error")
+	assert.NotEqual(t, hashedPassword, otherHash, "Each hash should use its own salt")
 }
 
 func TestPasswordsMatch(t *testing.T) {
 	password := "password123"
-	hashedPassword := HashFunc(password)
+	hashedPassword, err := HashFunc(password)
+	require.NoError(t, err, "Hashing should not return an error")
 
 	match := PasswordsMatch(hashedPassword, password)
 	assert.True(t, match, "Passwords should match")
 
 	noMatch := PasswordsMatch(hashedPassword, "wrongpassword")
 	assert.False(t, noMatch, "Passwords should not match")
+
+	// hashes stored before the encoded format still verify
+	legacyHash := "d81a4bcb5018ed48fd54d83f7f3d164a1fd2e2c4039fc99b297e7fbe23af373d7309cebdc3a928c9dd01ec51f70d50d1a4ec98ef398fd13034b58760c60e79f6"
+	assert.True(t, PasswordsMatch(legacyHash, password), "Legacy hash should match")
+	assert.False(t, PasswordsMatch(legacyHash, "wrongpassword"), "Legacy hash should not match")
+	assert.True(t, PasswordNeedsRehash(legacyHash), "Legacy hash should be rehashed")
+	assert.False(t, PasswordNeedsRehash(hashedPassword), "Fresh hash should not be rehashed")
 }
 
 func TestCreateJWTToken(t *testing.T) {